@@ -16,15 +16,26 @@
 package commands
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/metrics"
+	"github.com/vertica/vcluster/vclusterops/tracing"
+	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
@@ -84,6 +95,28 @@ const (
 	connFlag                    = "conn"
 	connKey                     = "conn"
 	stopNodeFlag                = "stop-hosts"
+	nmaPortFlag                 = "nma-port"
+	nmaPortKey                  = "nmaPort"
+	httpsPortFlag               = "https-port"
+	httpsPortKey                = "httpsPort"
+	hostNMAPortsFlag            = "host-nma-ports"
+	hostNMAPortsKey             = "hostNMAPorts"
+	hostHTTPSPortsFlag          = "host-https-ports"
+	hostHTTPSPortsKey           = "hostHTTPSPorts"
+	dryRunFlag                  = "dry-run"
+	reportFileFlag              = "report-file"
+	metricsFileFlag             = "metrics-file"
+	traceFileFlag               = "trace-file"
+	maxConcurrentRequestsFlag   = "max-concurrent-requests"
+	commandTimeoutFlag          = "command-timeout"
+	requestTimeoutFlag          = "request-timeout"
+	pollingTimeoutFlag          = "polling-timeout"
+	resumeFlag                  = "resume"
+	logFormatFlag               = "log-format"
+	logLevelFlag                = "log-level"
+	logMaxSizeFlag              = "log-max-size"
+	logMaxBackupsFlag           = "log-max-backups"
+	logSamplingFlag             = "log-sampling"
 )
 
 // Flag and key for database replication
@@ -124,6 +157,10 @@ var flagKeyMap = map[string]string{
 	verboseFlag:                 verboseKey,
 	outputFileFlag:              outputFileKey,
 	sandboxFlag:                 sandboxKey,
+	nmaPortFlag:                 nmaPortKey,
+	httpsPortFlag:               httpsPortKey,
+	hostNMAPortsFlag:            hostNMAPortsKey,
+	hostHTTPSPortsFlag:          hostHTTPSPortsKey,
 	targetDBNameFlag:            targetDBNameKey,
 	targetHostsFlag:             targetHostsKey,
 	targetUserNameFlag:          targetUserNameKey,
@@ -147,6 +184,10 @@ const (
 	createConnectionSubCmd  = "create_connection"
 	configRecoverSubCmd     = "recover"
 	configShowSubCmd        = "show"
+	configValidateSubCmd    = "validate"
+	configDiffSubCmd        = "diff"
+	configHistorySubCmd     = "history"
+	configRollbackSubCmd    = "rollback"
 	replicationSubCmd       = "replication"
 	startReplicationSubCmd  = "start"
 	listAllNodesSubCmd      = "list_allnodes"
@@ -170,10 +211,27 @@ const (
 // cmdGlobals holds global variables shared by multiple
 // commands
 type cmdGlobals struct {
-	verbose  bool
-	file     *os.File
-	keyFile  string
-	certFile string
+	verbose    bool
+	file       *os.File
+	keyFile    string
+	certFile   string
+	reportFile string
+	// the options of the logger, except the log file that is given by
+	// --log-path, and the sampling given as "initial:thereafter" or "off"
+	logOptions  vlog.LogOptions
+	logSampling string
+	// the file that the metrics of the command are written to
+	metricsFile string
+	// the file that the spans of the command are written to, and the
+	// opened file once the command runs
+	traceFile   string
+	traceOutput *os.File
+	// the journal that the command is resumed from
+	resumeJournal string
+
+	// per-host ports given in the cli, keyed by host
+	hostNMAPorts   map[string]int
+	hostHTTPSPorts map[string]int
 
 	// Global variables for targetDB are used for the replication subcommand
 	targetHosts        []string
@@ -217,15 +275,21 @@ var logPath = defaultLogPath
 // for making a basic cobra command
 type cmdInterface interface {
 	Parse(inputArgv []string, logger vlog.Printer) error
-	Run(vcc vclusterops.ClusterCommands) error
+	Run(ctx context.Context, vcc vclusterops.ClusterCommands) error
 	SetDatabaseOptions(opt *vclusterops.DatabaseOptions)
 	SetParser(parser *pflag.FlagSet)
 	setCommonFlags(cmd *cobra.Command, flags []string)
 	initCmdOutputFile() (*os.File, error)
+	writeReport(logger vlog.Printer)
+	finishJournal(runError error, logger vlog.Printer)
 }
 
 func Execute() {
-	err := rootCmd.Execute()
+	// cancel the running operation when the user aborts vcluster, so that
+	// in-flight requests are stopped instead of being left behind
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
 		fmt.Printf("Error during execution: %s\n", err)
 		os.Exit(1)
@@ -236,13 +300,27 @@ func Execute() {
 func initVcc(cmd *cobra.Command) vclusterops.VClusterCommands {
 	// setup logs
 	logger := vlog.Printer{ForCli: true}
-	logger.SetupOrDie(dbOptions.LogPath)
+	logOptions := globals.logOptions
+	logOptions.File = dbOptions.LogPath
+	logger.SetupWithOptionsOrDie(logOptions)
 
 	vcc := vclusterops.VClusterCommands{
 		VClusterCommandsLogger: vclusterops.VClusterCommandsLogger{
 			Log: logger.WithName(cmd.CalledAs()),
 		},
 	}
+	if globals.metricsFile != "" {
+		vcc.Metrics = metrics.NewRegistry()
+	}
+	if globals.traceFile != "" {
+		traceOutput, err := os.OpenFile(globals.traceFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, outputFilePerm)
+		if err != nil {
+			logger.PrintWarning("Could not open the trace file %s, the command is not traced, details: %s", globals.traceFile, err)
+		} else {
+			globals.traceOutput = traceOutput
+			vcc.Tracer = tracing.NewJSONTracer(traceOutput)
+		}
+	}
 	vcc.LogInfo("New VCluster command initialization")
 
 	return vcc
@@ -277,6 +355,18 @@ func setDBOptionsUsingViper(flag string) error {
 		globals.certFile = viper.GetString(certFileKey)
 	case verboseFlag:
 		globals.verbose = viper.GetBool(verboseKey)
+	case nmaPortFlag:
+		dbOptions.NMAPort = viper.GetInt(nmaPortKey)
+	case httpsPortFlag:
+		dbOptions.HTTPSPort = viper.GetInt(httpsPortKey)
+	case hostNMAPortsFlag:
+		return setHostPortsUsingViper(hostNMAPortsKey, func(ports *vclusterops.HostPorts, port int) {
+			ports.NMAPort = port
+		})
+	case hostHTTPSPortsFlag:
+		return setHostPortsUsingViper(hostHTTPSPortsKey, func(ports *vclusterops.HostPorts, port int) {
+			ports.HTTPSPort = port
+		})
 	default:
 		return fmt.Errorf("cannot find the relevant database option for flag %q", flag)
 	}
@@ -284,6 +374,32 @@ func setDBOptionsUsingViper(flag string) error {
 	return nil
 }
 
+// setHostPortsUsingViper fills the per-host ports in the database options
+// using the host-to-port map stored in the given viper key. Host names are
+// resolved to addresses, as the ports are looked up by host address.
+func setHostPortsUsingViper(key string, setPort func(ports *vclusterops.HostPorts, port int)) error {
+	if dbOptions.HostPorts == nil {
+		dbOptions.HostPorts = make(map[string]vclusterops.HostPorts)
+	}
+	for host, val := range viper.GetStringMapString(key) {
+		port, err := strconv.Atoi(val)
+		if err != nil {
+			return fmt.Errorf("invalid port %q for host %s: %w", val, host, err)
+		}
+		address := host
+		if !util.IsIPv4(host) && !util.IsIPv6(host) {
+			address, err = util.ResolveToOneIP(host, viper.GetBool(ipv6Key))
+			if err != nil {
+				return err
+			}
+		}
+		ports := dbOptions.HostPorts[address]
+		setPort(&ports, port)
+		dbOptions.HostPorts[address] = ports
+	}
+	return nil
+}
+
 // setTargetDBOptionsUsingViper can set the value of flag using the relevant key
 // in viper
 func setTargetDBOptionsUsingViper(flag string) error {
@@ -316,13 +432,17 @@ func configViper(cmd *cobra.Command, flagsInConfig []string) error {
 	}
 	// log-path is a flag that all the subcommands need
 	flagsInConfig = append(flagsInConfig, logPathFlag)
-	// cert-file and key-file are not available for
+	// cert-file, key-file and the port flags are not available for
 	// - manage_config
 	// - manage_config show
+	// - manage_config validate
+	// - manage_config history
+	// - manage_config rollback
 	// - create_connection
-	if cmd.CalledAs() != manageConfigSubCmd &&
-		cmd.CalledAs() != configShowSubCmd && cmd.CalledAs() != createConnectionSubCmd {
-		flagsInConfig = append(flagsInConfig, certFileFlag, keyFileFlag)
+	if cmd.CalledAs() != manageConfigSubCmd && !isConfigFileSubCmd(cmd.CalledAs()) &&
+		cmd.CalledAs() != createConnectionSubCmd {
+		flagsInConfig = append(flagsInConfig, certFileFlag, keyFileFlag,
+			nmaPortFlag, httpsPortFlag, hostNMAPortsFlag, hostHTTPSPortsFlag)
 	}
 
 	// bind viper keys to cobra flags
@@ -366,16 +486,47 @@ func bindKeysToEnv() error {
 	return nil
 }
 
+// isConfigFileSubCmd returns true for the manage_config subcommands that
+// only work on the config file, which they read themselves
+func isConfigFileSubCmd(subCmd string) bool {
+	return subCmd == configShowSubCmd || subCmd == configValidateSubCmd ||
+		subCmd == configHistorySubCmd || subCmd == configRollbackSubCmd
+}
+
+// databaseChangingSubCmds are the subcommands that change the database, so
+// the config file must be valid before they run. manage_config recover is
+// not one of them, as it is how a broken config file is replaced.
+var databaseChangingSubCmds = []string{
+	createDBSubCmd, reviveDBSubCmd, dropDBSubCmd, startDBSubCmd, stopDBSubCmd,
+	addSCSubCmd, removeSCSubCmd, stopSCSubCmd, sandboxSubCmd, unsandboxSubCmd,
+	addNodeSubCmd, removeNodeSubCmd, stopNodeCmd, restartNodeSubCmd, reIPSubCmd,
+	installPkgSubCmd, startReplicationSubCmd,
+}
+
+// isDatabaseChangingSubCmd returns true for the subcommands that change the
+// database
+func isDatabaseChangingSubCmd(subCmd string) bool {
+	return util.StringInArray(subCmd, databaseChangingSubCmds)
+}
+
 // load db options from file to viper
 func loadConfig(cmd *cobra.Command) (err error) {
+	mustBeValid := isDatabaseChangingSubCmd(cmd.CalledAs())
+
 	// load db options from config file to viper
 	// note: config file is not available for create_db and revive_db
 	//       manage_config does not need viper to load config file info
 	if cmd.CalledAs() != createDBSubCmd &&
 		cmd.CalledAs() != reviveDBSubCmd &&
 		cmd.CalledAs() != configRecoverSubCmd &&
-		cmd.CalledAs() != configShowSubCmd {
-		err := loadConfigToViper()
+		!isConfigFileSubCmd(cmd.CalledAs()) {
+		err := loadConfigToViper(mustBeValid)
+		if err != nil {
+			return err
+		}
+	} else if mustBeValid {
+		// create_db and revive_db add their database to the config file
+		err := validateConfigFile()
 		if err != nil {
 			return err
 		}
@@ -430,6 +581,47 @@ func filterFlagsInConfig(flags []string) []string {
 	return flagsAccepted.Intersect(allFlagsInConfig).ToSlice()
 }
 
+// writeDryRunPlan writes the plan of a command run in dry run mode
+// as JSON to the output file
+func writeDryRunPlan(vcc vclusterops.VClusterCommands, plan *vclusterops.DryRunPlan) error {
+	vcc.PrintInfo("Dry run, the following operations were planned and not run:\n%s", plan.String())
+	planBytes, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal the dry run plan, details: %w", err)
+	}
+	planBytes = append(planBytes, '\n')
+	_, err = globals.file.Write(planBytes)
+	return err
+}
+
+// writeMetrics writes the metrics collected by the command in the text
+// exposition format to the metrics file, if one is given
+func writeMetrics(vcc vclusterops.VClusterCommands) {
+	if globals.metricsFile == "" || vcc.Metrics == nil {
+		return
+	}
+	var buf bytes.Buffer
+	err := vcc.Metrics.WriteText(&buf)
+	if err == nil {
+		err = os.WriteFile(globals.metricsFile, buf.Bytes(), outputFilePerm)
+	}
+	if err != nil {
+		vcc.PrintWarning("Could not write the metrics to file %s, details: %s", globals.metricsFile, err)
+	}
+}
+
+// checkTrace warns if some spans of the command could not be written to the
+// trace file, as the tracer only keeps the error of the first failed write
+func checkTrace(vcc vclusterops.VClusterCommands) {
+	tracer, ok := vcc.Tracer.(*tracing.JSONTracer)
+	if !ok {
+		return
+	}
+	if err := tracer.Err(); err != nil {
+		vcc.PrintWarning("Could not write the trace to file %s, details: %s", globals.traceFile, err)
+	}
+}
+
 // makeBasicCobraCmd can make a basic cobra command for all vcluster commands.
 // It will be called inside cmd_create_db.go, cmd_stop_db.go, ...
 func makeBasicCobraCmd(i cmdInterface, use, short, long string, commonFlags []string) *cobra.Command {
@@ -442,11 +634,15 @@ func makeBasicCobraCmd(i cmdInterface, use, short, long string, commonFlags []st
 			if globals.verbose {
 				fmt.Println("---{VCluster begin}---")
 			}
+			if err := setLogSampling(globals.logSampling); err != nil {
+				return err
+			}
 			flagsInConfig := filterFlagsInConfig(commonFlags)
 			return configViper(cmd, flagsInConfig)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			vcc := initVcc(cmd)
+			defer closeFile(globals.traceOutput)
 			i.SetParser(cmd.Flags())
 			f, err := i.initCmdOutputFile()
 			if err != nil {
@@ -463,7 +659,15 @@ func makeBasicCobraCmd(i cmdInterface, use, short, long string, commonFlags []st
 				vcc.LogError(parseError, "fail to parse command")
 				return parseError
 			}
-			runError := i.Run(vcc)
+			runError := i.Run(cmd.Context(), vcc)
+			i.writeReport(vcc.GetLog())
+			writeMetrics(vcc)
+			checkTrace(vcc)
+			i.finishJournal(runError, vcc.GetLog())
+			var plan *vclusterops.DryRunPlan
+			if errors.As(runError, &plan) {
+				return writeDryRunPlan(vcc, plan)
+			}
 			if runError != nil {
 				cmd.SilenceUsage = true // don't show usage when vcluster fails and operation has started
 				vcc.LogError(runError, "fail to run command")
@@ -622,6 +826,29 @@ func setLogPathImpl(opsys operatingSystem) string {
 	return filepath.Join(path, "vcluster.log")
 }
 
+// setLogSampling sets the sampling of the logger from --log-sampling
+func setLogSampling(sampling string) error {
+	if sampling == "" {
+		return nil
+	}
+	if sampling == "off" {
+		globals.logOptions.NoSampling = true
+		return nil
+	}
+	initial, thereafter, found := strings.Cut(sampling, ":")
+	var err error
+	if found {
+		globals.logOptions.SamplingInitial, err = strconv.Atoi(initial)
+		if err == nil {
+			globals.logOptions.SamplingThereafter, err = strconv.Atoi(thereafter)
+		}
+	}
+	if !found || err != nil || globals.logOptions.SamplingInitial <= 0 || globals.logOptions.SamplingThereafter <= 0 {
+		return fmt.Errorf("invalid value %q of --%s, it must be off or two positive numbers such as 100:100", sampling, logSamplingFlag)
+	}
+	return nil
+}
+
 func closeFile(f *os.File) {
 	if f != nil && f != os.Stdout {
 		if err := f.Close(); err != nil {
@@ -20,7 +20,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 func TestConfigPathDefaults(t *testing.T) {
@@ -112,3 +114,39 @@ func TestSetLogPathImpl(t *testing.T) {
 	expectedLogPath = defaultHomeConfigDirLogPath
 	assert.Equal(t, expectedLogPath, logPath)
 }
+
+func TestSetLogSampling(t *testing.T) {
+	defer func() { globals.logOptions = vlog.LogOptions{} }()
+
+	assert.NoError(t, setLogSampling("10:1000"))
+	assert.Equal(t, 10, globals.logOptions.SamplingInitial)
+	assert.Equal(t, 1000, globals.logOptions.SamplingThereafter)
+	assert.False(t, globals.logOptions.NoSampling)
+
+	assert.NoError(t, setLogSampling("off"))
+	assert.True(t, globals.logOptions.NoSampling)
+
+	for _, sampling := range []string{"100", "a:100", "100:0", "-1:100"} {
+		assert.ErrorContains(t, setLogSampling(sampling), "invalid value")
+	}
+}
+
+func TestDatabaseChangingSubCmds(t *testing.T) {
+	// the subcommands that can plan their changes in dry run mode are the
+	// ones that change the database
+	var checkedSubCmds []string
+	var walk func(cmds []*cobra.Command)
+	walk = func(cmds []*cobra.Command) {
+		for _, cmd := range cmds {
+			walk(cmd.Commands())
+			if cmd.Runnable() {
+				assert.Equal(t, cmd.Flags().Lookup(dryRunFlag) != nil, isDatabaseChangingSubCmd(cmd.Name()), cmd.Name())
+				checkedSubCmds = append(checkedSubCmds, cmd.Name())
+			}
+		}
+	}
+	walk(rootCmd.Commands())
+	assert.Contains(t, checkedSubCmds, startReplicationSubCmd)
+	assert.Contains(t, checkedSubCmds, listAllNodesSubCmd)
+	assert.False(t, isDatabaseChangingSubCmd(configRecoverSubCmd))
+}
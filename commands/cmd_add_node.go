@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -72,7 +73,7 @@ Examples:
     --node-names v_test_db_node0001,v_test_db_node0002
 `,
 		[]string{dbNameFlag, configFlag, hostsFlag, dataPathFlag, depotPathFlag,
-			passwordFlag},
+			passwordFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -188,12 +189,13 @@ func (c *CmdAddNode) parseNodeNameList() error {
 	return nil
 }
 
-func (c *CmdAddNode) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdAddNode) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
 	options := c.addNodeOptions
 
-	vdb, addNodeError := vcc.VAddNode(options)
+	vdb, report, addNodeError := vcc.VAddNode(ctx, options)
+	c.addReport(report)
 	if addNodeError != nil {
 		return addNodeError
 	}
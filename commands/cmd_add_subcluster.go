@@ -16,7 +16,11 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -76,9 +80,14 @@ Examples:
   vcluster db_add_subcluster --subcluster sc1 --db-name test_db \
 	--hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
 	--is-primary --control-set-size -1 --new-hosts 10.20.30.43
+
+  # Resume adding a subcluster after an earlier run did not finish
+  vcluster db_add_subcluster --subcluster sc1 \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --resume /opt/vertica/log/vcluster_db_add_subcluster_test_db.journal
 `,
 		[]string{dbNameFlag, configFlag, hostsFlag, eonModeFlag, passwordFlag,
-			dataPathFlag, depotPathFlag},
+			dataPathFlag, depotPathFlag, dryRunFlag, resumeFlag},
 	)
 
 	// local flags
@@ -188,6 +197,7 @@ func (c *CmdAddSubcluster) validateParse(logger vlog.Printer) error {
 	if err != nil {
 		return err
 	}
+	c.setJournal(&c.addSubclusterOptions.DatabaseOptions, addSCSubCmd)
 	return c.setDBPassword(&c.addSubclusterOptions.DatabaseOptions)
 }
 
@@ -196,12 +206,13 @@ func (c *CmdAddSubcluster) Analyze(logger vlog.Printer) error {
 	return nil
 }
 
-func (c *CmdAddSubcluster) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdAddSubcluster) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
 	options := c.addSubclusterOptions
 
-	err := vcc.VAddSubcluster(options)
+	report, err := vcc.VAddSubcluster(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "failed to add subcluster")
 		return err
@@ -211,12 +222,17 @@ func (c *CmdAddSubcluster) Run(vcc vclusterops.ClusterCommands) error {
 		fmt.Printf("Adding hosts %v to subcluster %s\n",
 			options.NewHosts, options.SCName)
 
+		// the nodes are added under the journal of the subcluster, so that
+		// the ones created already are not added again if the command is resumed
 		options.VAddNodeOptions.DatabaseOptions = c.addSubclusterOptions.DatabaseOptions
 		options.VAddNodeOptions.SCName = c.addSubclusterOptions.SCName
+		options.VAddNodeOptions.ContinueAddSubclusterJournal = true
 
-		vdb, err := vcc.VAddNode(&options.VAddNodeOptions)
+		vdb, addNodeReport, err := vcc.VAddNode(ctx, &options.VAddNodeOptions)
+		c.addReport(addNodeReport)
 		if err != nil {
 			vcc.LogError(err, "failed to add nodes into the new subcluster")
+			c.dropSubcluster(vcc)
 			return err
 		}
 		// update db info in the config file
@@ -235,6 +251,39 @@ func (c *CmdAddSubcluster) Run(vcc vclusterops.ClusterCommands) error {
 	return nil
 }
 
+// dropSubcluster drops the new subcluster when its nodes could not be added,
+// so that the command does not leave an empty subcluster in the database
+func (c *CmdAddSubcluster) dropSubcluster(vcc vclusterops.ClusterCommands) {
+	options := vclusterops.VRemoveScOptionsFactory()
+	options.DatabaseOptions = c.addSubclusterOptions.DatabaseOptions
+	options.SubclusterToRemove = c.addSubclusterOptions.SCName
+	// the directories of the nodes were created by this command
+	options.ForceDelete = true
+	// the drop is not a step of the command to resume
+	options.JournalPath = ""
+	options.ResumeFromJournal = false
+
+	vcc.PrintInfo("Dropping subcluster %s", options.SubclusterToRemove)
+	// the subcluster is dropped even if the command was canceled
+	_, report, err := vcc.VRemoveSubcluster(context.Background(), &options)
+	if report != nil && c.report != nil {
+		// the status of the command is still the one of the failed step
+		c.report.Ops = append(c.report.Ops, report.Ops...)
+	}
+	if err != nil {
+		vcc.PrintWarning("fail to drop subcluster %s, details: %s", options.SubclusterToRemove, err)
+		return
+	}
+
+	// the database is back to where it was, so there is nothing to resume
+	if c.journalPath != "" {
+		err = os.Remove(c.journalPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			vcc.PrintWarning("Could not remove the journal file %s, details: %s", c.journalPath, err)
+		}
+	}
+}
+
 // SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdAddSubcluster
 func (c *CmdAddSubcluster) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
 	c.addSubclusterOptions.DatabaseOptions = *opt
@@ -16,9 +16,14 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -28,7 +33,8 @@ import (
 )
 
 const (
-	outputFilePerm = 0600
+	outputFilePerm       = 0600
+	defaultLogMaxBackups = 5
 )
 
 /* CmdBase
@@ -45,6 +51,11 @@ type CmdBase struct {
 	output                 string
 	passwordFile           string
 	readPasswordFromPrompt bool
+
+	// the execution report of the VClusterCommands calls made by the command
+	report *vclusterops.ExecutionReport
+	// the journal where the command records its progress, if it can be resumed
+	journalPath string
 }
 
 // ValidateParseBaseOptions will validate and parse the required base options in each command
@@ -91,9 +102,11 @@ func (c *CmdBase) setCommonFlags(cmd *cobra.Command, flags []string) {
 		false,
 		"Show the details of VCluster run in the console",
 	)
+	c.setLogFlags(cmd)
 	// keyFile and certFile are flags that all subcommands require,
-	// except for create_connection and manage_config show
-	if cmd.Name() != configShowSubCmd && cmd.Name() != createConnectionSubCmd {
+	// except for create_connection and the manage_config subcommands that only
+	// work on the config file
+	if !isConfigFileSubCmd(cmd.Name()) && cmd.Name() != createConnectionSubCmd {
 		cmd.Flags().StringVar(
 			&globals.keyFile,
 			keyFileFlag,
@@ -110,6 +123,41 @@ func (c *CmdBase) setCommonFlags(cmd *cobra.Command, flags []string) {
 		)
 		markFlagsFileName(cmd, map[string][]string{certFileFlag: {"pem", "crt"}})
 		cmd.MarkFlagsRequiredTogether(keyFileFlag, certFileFlag)
+
+		c.setPortFlags(cmd)
+
+		cmd.Flags().StringVar(
+			&globals.reportFile,
+			reportFileFlag,
+			"",
+			"Write the execution report of the command as JSON to this file",
+		)
+		markFlagsFileName(cmd, map[string][]string{reportFileFlag: {"json"}})
+
+		cmd.Flags().StringVar(
+			&globals.metricsFile,
+			metricsFileFlag,
+			"",
+			"Write the metrics of the command in the Prometheus text format to this file",
+		)
+		markFlagsFileName(cmd, map[string][]string{metricsFileFlag: {"prom", "txt"}})
+
+		cmd.Flags().StringVar(
+			&globals.traceFile,
+			traceFileFlag,
+			"",
+			"Write the spans of the command, its steps and their requests as JSON lines to this file",
+		)
+		markFlagsFileName(cmd, map[string][]string{traceFileFlag: {"json", "jsonl"}})
+
+		cmd.Flags().IntVar(
+			&dbOptions.MaxConcurrentRequests,
+			maxConcurrentRequestsFlag,
+			0,
+			"The maximum number of requests that a step of the command sends to the hosts at once, 0 means no limit. "+
+				"Scrutinize downloads this many tarballs at once, or 4 if it is 0",
+		)
+		c.setTimeoutFlags(cmd)
 	}
 	if util.StringInArray(outputFileFlag, flags) {
 		cmd.Flags().StringVarP(
@@ -128,6 +176,55 @@ func (c *CmdBase) setCommonFlags(cmd *cobra.Command, flags []string) {
 			"The username for connecting to the database",
 		)
 	}
+	if util.StringInArray(dryRunFlag, flags) {
+		cmd.Flags().BoolVar(
+			&dbOptions.DryRun,
+			dryRunFlag,
+			false,
+			"Print the plan of the operations that change the database as JSON, instead of running them",
+		)
+	}
+	if util.StringInArray(resumeFlag, flags) {
+		cmd.Flags().StringVar(
+			&globals.resumeJournal,
+			resumeFlag,
+			"",
+			"Resume the command from the journal file of an earlier run that did not finish,"+
+				" skipping the steps that run completed",
+		)
+		markFlagsFileName(cmd, map[string][]string{resumeFlag: {"journal"}})
+	}
+}
+
+// setPortFlags sets the flags of the ports used to reach the NMA and
+// the Vertica HTTPS service
+func (c *CmdBase) setPortFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&dbOptions.NMAPort,
+		nmaPortFlag,
+		util.DefaultNMAPort,
+		"Port of the node management agent (NMA) on all hosts",
+	)
+	cmd.Flags().IntVar(
+		&dbOptions.HTTPSPort,
+		httpsPortFlag,
+		util.DefaultHTTPPort,
+		"Port of the Vertica HTTPS service on all hosts",
+	)
+	cmd.Flags().StringToIntVar(
+		&globals.hostNMAPorts,
+		hostNMAPortsFlag,
+		map[string]int{},
+		"Comma-separated list of HOST=PORT pairs of the NMA ports on specific hosts."+
+			" These override --"+nmaPortFlag+" and the ports in the config file",
+	)
+	cmd.Flags().StringToIntVar(
+		&globals.hostHTTPSPorts,
+		hostHTTPSPortsFlag,
+		map[string]int{},
+		"Comma-separated list of HOST=PORT pairs of the HTTPS service ports on specific hosts."+
+			" These override --"+httpsPortFlag+" and the ports in the config file",
+	)
 }
 
 // setConfigFlags sets the config flag as well as all the common flags that
@@ -326,6 +423,151 @@ func (c *CmdBase) writeCmdOutputToFile(f *os.File, output []byte, logger vlog.Pr
 	}
 }
 
+// addReport adds the execution report of a VClusterCommands call to the report
+// of the command. The ops of the commands that make several calls are merged
+// into one report.
+func (c *CmdBase) addReport(report *vclusterops.ExecutionReport) {
+	if report == nil {
+		return
+	}
+	if c.report == nil {
+		c.report = report
+		return
+	}
+	c.report.Ops = append(c.report.Ops, report.Ops...)
+	c.report.EndTime = report.EndTime
+	c.report.Duration = c.report.EndTime.Sub(c.report.StartTime)
+	c.report.Status = report.Status
+	c.report.Error = report.Error
+}
+
+// setJournal makes the command record its progress in a journal file in the
+// log directory, or resume from the journal given with --resume
+func (c *CmdBase) setJournal(opt *vclusterops.DatabaseOptions, subcommand string) {
+	switch {
+	case globals.resumeJournal != "":
+		opt.JournalPath = globals.resumeJournal
+		opt.ResumeFromJournal = true
+	case opt.DryRun:
+		// nothing is done that would need to be resumed
+		return
+	default:
+		opt.JournalPath = filepath.Join(filepath.Dir(opt.LogPath),
+			fmt.Sprintf("vcluster_%s_%s.journal", subcommand, opt.DBName))
+		opt.ResumeFromJournal = false
+	}
+	c.journalPath = opt.JournalPath
+}
+
+// finishJournal removes the journal of the command once it has succeeded,
+// or tells how to resume the command if it has failed
+func (c *CmdBase) finishJournal(runError error, logger vlog.Printer) {
+	if c.journalPath == "" {
+		return
+	}
+	var plan *vclusterops.DryRunPlan
+	switch {
+	case runError == nil:
+		err := os.Remove(c.journalPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.PrintWarning("Could not remove the journal file %s, details: %s", c.journalPath, err)
+		}
+	case errors.As(runError, &plan):
+		return
+	default:
+		if _, err := os.Stat(c.journalPath); err == nil {
+			logger.PrintInfo("The progress of the command is recorded in %s."+
+				" Run the command again with --%s %s to continue from there.", c.journalPath, resumeFlag, c.journalPath)
+		}
+	}
+}
+
+// setTimeoutFlags sets the flags of the timeouts that all the commands have.
+// A timeout of 0 means the default of the command, and a negative one means
+// no limit.
+func (c *CmdBase) setTimeoutFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(
+		&dbOptions.Timeouts.Command,
+		commandTimeoutFlag,
+		0,
+		"How long the command can run before it is canceled, such as 30m. 0 means no limit",
+	)
+	cmd.Flags().DurationVar(
+		&dbOptions.Timeouts.Request,
+		requestTimeoutFlag,
+		0,
+		"How long a request to a host can take, unless the step that sends it sets its own timeout. 0 means the default of 5m",
+	)
+	cmd.Flags().DurationVar(
+		&dbOptions.Timeouts.StatePolling,
+		pollingTimeoutFlag,
+		0,
+		"How long the command waits for the nodes to reach a state, such as UP. 0 means the default of the command",
+	)
+}
+
+// setLogFlags sets the flags of the format, the level, the rotation and the
+// sampling of the log, which all the subcommands need
+func (c *CmdBase) setLogFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&globals.logOptions.Format,
+		logFormatFlag,
+		vlog.ConsoleFormat,
+		fmt.Sprintf("The format of the log entries, %s or %s", vlog.ConsoleFormat, vlog.JSONFormat),
+	)
+	cmd.Flags().StringVar(
+		&globals.logOptions.Level,
+		logLevelFlag,
+		vlog.InfoLevel,
+		fmt.Sprintf("The log level, %s, %s or %s. The %s level also logs the request and response bodies, with the secrets masked",
+			vlog.DebugLevel, vlog.InfoLevel, vlog.ErrorLevel, vlog.DebugLevel),
+	)
+	cmd.Flags().IntVar(
+		&globals.logOptions.MaxSizeMB,
+		logMaxSizeFlag,
+		0,
+		"The size in MB that the log file is rotated at, 0 means it is not rotated",
+	)
+	cmd.Flags().IntVar(
+		&globals.logOptions.MaxBackups,
+		logMaxBackupsFlag,
+		defaultLogMaxBackups,
+		"The number of rotated log files that are kept",
+	)
+	cmd.Flags().StringVar(
+		&globals.logSampling,
+		logSamplingFlag,
+		fmt.Sprintf("%d:%d", vlog.DefaultSamplingInitial, vlog.DefaultSamplingThereafter),
+		"The sampling of the log entries as initial:thereafter. In each second, after the first initial entries"+
+			" with the same level and message, only every thereafter-th one is logged. off logs all the entries",
+	)
+}
+
+// setPollingTimeout sets the polling timeout of the command from the
+// timeout flag of the command, in seconds, unless --polling-timeout is given
+func (c *CmdBase) setPollingTimeout(opt *vclusterops.DatabaseOptions, timeoutSeconds int) {
+	if !c.parser.Changed(pollingTimeoutFlag) {
+		opt.Timeouts.StatePolling = time.Duration(timeoutSeconds) * time.Second
+	}
+}
+
+// writeReport writes the execution report of the command as JSON to the
+// report file, if one is given
+func (c *CmdBase) writeReport(logger vlog.Printer) {
+	if globals.reportFile == "" || c.report == nil {
+		return
+	}
+	reportBytes, err := json.MarshalIndent(c.report, "", "  ")
+	if err != nil {
+		logger.PrintWarning("Could not marshal the execution report, details: %s", err)
+		return
+	}
+	err = os.WriteFile(globals.reportFile, reportBytes, outputFilePerm)
+	if err != nil {
+		logger.PrintWarning("Could not write the execution report to file %s, details: %s", globals.reportFile, err)
+	}
+}
+
 // initCmdOutputFile returns the open file descriptor, that will
 // be used to write the command output, or stdout
 func (c *CmdBase) initCmdOutputFile() (*os.File, error) {
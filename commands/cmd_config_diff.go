@@ -0,0 +1,245 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdConfigDiff
+ *
+ * A subcommand comparing the YAML config file
+ * with the live database, and reconciling the
+ * chosen differences into the config file.
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdConfigDiff struct {
+	fetchDBOptions *vclusterops.VFetchCoordinationDatabaseOptions
+	// the kinds of differences to reconcile into the config file
+	reconcileKinds []string
+	jsonOutput     bool
+	CmdBase
+}
+
+// configDiffResult is the output of manage_config diff in JSON
+type configDiffResult struct {
+	Database    string        `json:"database"`
+	ConfigPath  string        `json:"config_path"`
+	Differences []*configDiff `json:"differences"`
+}
+
+func makeCmdConfigDiff() *cobra.Command {
+	newCmd := &CmdConfigDiff{}
+	opt := vclusterops.VRecoverConfigOptionsFactory()
+	newCmd.fetchDBOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		configDiffSubCmd,
+		"Compare the config file with the live database",
+		`This subcommand fetches the nodes of the live database, and prints how they
+differ from the ones in the config file, such as after the database has been
+changed with admintools or SQL. It shows the nodes that are added or removed,
+and the nodes whose address, subcluster, sandbox, primary or secondary type,
+or paths have changed.
+
+The kinds of differences given to --reconcile are written to the config file.
+They can be node_added, node_removed, address, subcluster, sandbox, primary,
+path, or all.
+
+The sandboxes of the nodes are only compared when the database is up.
+
+Examples:
+  # Show the differences of the database in the default config file
+  vcluster manage_config diff --password testpassword
+
+  # Show the differences as JSON
+  vcluster manage_config diff --password testpassword --json
+
+  # Update the addresses and subclusters of the nodes in the config file
+  vcluster manage_config diff --password testpassword \
+    --reconcile address,subcluster \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, hostsFlag, passwordFlag, catalogPathFlag, depotPathFlag, configFlag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdConfigDiff) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&c.reconcileKinds,
+		"reconcile",
+		[]string{},
+		"Comma-separated list of the kinds of differences to write to the config file: "+
+			strings.Join(configDiffKinds, ", ")+", or "+allDiffs,
+	)
+	cmd.Flags().BoolVar(
+		&c.jsonOutput,
+		"json",
+		false,
+		"Print the differences as JSON",
+	)
+}
+
+func (c *CmdConfigDiff) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.fetchDBOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdConfigDiff) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+	err := validateDiffKinds(c.reconcileKinds)
+	if err != nil {
+		return err
+	}
+
+	err = c.getCertFilesFromCertPaths(&c.fetchDBOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.fetchDBOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	return c.setDBPassword(&c.fetchDBOptions.DatabaseOptions)
+}
+
+func (c *CmdConfigDiff) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	config, err := readConfig()
+	if err != nil {
+		return err
+	}
+	configDB, err := config.getDatabase(c.fetchDBOptions.DBName)
+	if err != nil {
+		return err
+	}
+
+	liveDB, err := c.fetchLiveDatabase(ctx, vcc, configDB)
+	if err != nil {
+		return err
+	}
+
+	diffs := diffDatabaseConfigs(configDB, liveDB)
+	// the config file is only rewritten when there is something to reconcile,
+	// and then from its content under the lock
+	if reconcileDatabaseConfig(configDB, liveDB, diffs, c.reconcileKinds) > 0 {
+		diffs, err = reconcileConfigFile(configDB.Name, liveDB, c.reconcileKinds, vcc.GetLog())
+		if err != nil {
+			return fmt.Errorf("fail to write config file, details: %w", err)
+		}
+	}
+
+	output, err := c.formatDiffs(configDB.Name, diffs)
+	if err != nil {
+		return err
+	}
+	c.writeCmdOutputToFile(globals.file, output, vcc.GetLog())
+	vcc.LogInfo("Config file differences", "differences", len(diffs))
+
+	return nil
+}
+
+// fetchLiveDatabase fetches the nodes of the live database. Their states
+// give the topology of the database, and the fetched database gives their
+// data and depot paths.
+func (c *CmdConfigDiff) fetchLiveDatabase(ctx context.Context, vcc vclusterops.ClusterCommands,
+	configDB *DatabaseConfig) (*DatabaseConfig, error) {
+	nodeStateOptions := vclusterops.VFetchNodeStateOptionsFactory()
+	nodeStateOptions.DatabaseOptions = c.fetchDBOptions.DatabaseOptions
+	nodeStates, report, err := vcc.VFetchNodeState(ctx, &nodeStateOptions)
+	c.addReport(report)
+	if err != nil && len(nodeStates) == 0 {
+		vcc.LogError(err, "fail to fetch the node states")
+		return nil, err
+	}
+	// when the database is down, the node states are read from the catalog,
+	// which does not have the sandboxes
+	sandboxesKnown := err == nil
+
+	vdb, report, err := vcc.VFetchCoordinationDatabase(ctx, c.fetchDBOptions)
+	c.addReport(report)
+	if err != nil {
+		vcc.LogError(err, "fail to fetch the database")
+		vcc.PrintWarning("The data and depot paths are not compared, as the database could not be fetched: %s", err)
+		return makeLiveDatabaseConfig(configDB, nodeStates, sandboxesKnown, nil)
+	}
+
+	return makeLiveDatabaseConfig(configDB, nodeStates, sandboxesKnown, &vdb)
+}
+
+// formatDiffs returns the differences in JSON or as text
+func (c *CmdConfigDiff) formatDiffs(dbName string, diffs []*configDiff) ([]byte, error) {
+	if c.jsonOutput {
+		result := configDiffResult{Database: dbName, ConfigPath: dbOptions.ConfigPath, Differences: diffs}
+		if result.Differences == nil {
+			result.Differences = []*configDiff{}
+		}
+		output, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("fail to marshal the differences, details %w", err)
+		}
+		return append(output, '\n'), nil
+	}
+
+	if len(diffs) == 0 {
+		return []byte(fmt.Sprintf("Database %s in the config file %s matches the live database\n",
+			dbName, dbOptions.ConfigPath)), nil
+	}
+	var output strings.Builder
+	fmt.Fprintf(&output, "Database %s in the config file %s has %d difference(s) from the live database:\n",
+		dbName, dbOptions.ConfigPath, len(diffs))
+	for _, diff := range diffs {
+		fmt.Fprintf(&output, "  %s", diff.String())
+		if diff.Reconciled {
+			output.WriteString(" (reconciled)")
+		} else if diff.NotReconciledReason != "" {
+			fmt.Fprintf(&output, " (not reconciled: %s)", diff.NotReconciledReason)
+		}
+		output.WriteString("\n")
+	}
+	return []byte(output.String()), nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance
+func (c *CmdConfigDiff) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.fetchDBOptions.DatabaseOptions = *opt
+}
@@ -0,0 +1,109 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdConfigHistory
+ *
+ * A subcommand listing the backups of the YAML
+ * config file in the default or a specified
+ * directory.
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdConfigHistory struct {
+	hOptions vclusterops.DatabaseOptions
+	CmdBase
+}
+
+func makeCmdConfigHistory() *cobra.Command {
+	newCmd := &CmdConfigHistory{}
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		configHistorySubCmd,
+		"List the backups of the config file",
+		fmt.Sprintf(`This subcommand lists the backups of the config file, the latest first, with
+the databases in each of them. The config file is backed up every time a
+command updates it, and the latest %d backups are kept next to it.
+
+The ID of a backup can be given to manage_config rollback to restore it.
+
+Examples:
+  # List the backups of the cluster config file in the default location
+  vcluster manage_config history
+
+  # List the backups of the config file at /tmp/vertica_cluster.yaml
+  vcluster manage_config history --config /tmp/vertica_cluster.yaml
+`, configHistorySize),
+		[]string{configFlag},
+	)
+
+	return cmd
+}
+
+func (c *CmdConfigHistory) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	return nil
+}
+
+func (c *CmdConfigHistory) Run(_ context.Context, _ vclusterops.ClusterCommands) error {
+	if dbOptions.ConfigPath == "" {
+		return fmt.Errorf("configuration file path is empty")
+	}
+	backups, err := getConfigHistory(dbOptions.ConfigPath)
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Printf("The config file %s has no backup\n", dbOptions.ConfigPath)
+		return nil
+	}
+
+	fmt.Printf("%-24s %-24s %s\n", "ID", "TIME", "DATABASES")
+	for _, backup := range backups {
+		fmt.Printf("%-24s %-24s %s\n", backup.ID, backup.Time.Format("2006-01-02 15:04:05 MST"),
+			getBackupDatabases(backup))
+	}
+
+	return nil
+}
+
+// getBackupDatabases returns the names of the databases in the backup, or
+// why they cannot be read
+func getBackupDatabases(backup *configBackup) string {
+	config, err := readConfigBackup(backup)
+	if err != nil {
+		return "(invalid)"
+	}
+	return strings.Join(config.getDatabaseNames(), ", ")
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance
+func (c *CmdConfigHistory) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.hOptions = *opt
+}
@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -48,8 +49,10 @@ func makeCmdConfigRecover() *cobra.Command {
 
 You must provide all the hosts that participate in the database.
 
-If there is an existing file at the provided config file location, the recover function
-will not create a new config file unless you explicitly specify --overwrite.
+If there is an existing file at the provided config file location, the database is
+added to it, and its other databases are kept. The recover function will not replace
+a database that the file has already unless you explicitly specify --overwrite.
+With --overwrite, a config file that is invalid is replaced, and is kept in its history.
 
 Examples:
   # Recover the config file to the default location
@@ -81,7 +84,7 @@ func (c *CmdConfigRecover) setLocalFlags(cmd *cobra.Command) {
 		&c.recoverConfigOptions.Overwrite,
 		"overwrite",
 		false,
-		"overwrite the existing config file",
+		"overwrite the database in the existing config file",
 	)
 }
 
@@ -103,14 +106,27 @@ func (c *CmdConfigRecover) validateParse(logger vlog.Printer) error {
 	return nil
 }
 
-func (c *CmdConfigRecover) Run(vcc vclusterops.ClusterCommands) error {
-	vdb, err := vcc.VFetchCoordinationDatabase(c.recoverConfigOptions)
+func (c *CmdConfigRecover) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
+	// fail before fetching the database if it cannot be written to the file
+	config, err := readConfigOrMakeNew()
+	if err == nil {
+		err = config.checkRecover(c.recoverConfigOptions.DBName, c.recoverConfigOptions.Overwrite)
+		if err != nil {
+			return err
+		}
+	} else if !c.recoverConfigOptions.Overwrite {
+		return fmt.Errorf("config file exists at %s, but cannot be read, details: %w. "+
+			"You can use --overwrite to replace it", c.recoverConfigOptions.ConfigPath, err)
+	}
+
+	vdb, report, err := vcc.VFetchCoordinationDatabase(ctx, c.recoverConfigOptions)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "failed to recover the config file")
 		return err
 	}
 	// write db info to vcluster config file
-	err = writeConfig(&vdb, vcc.GetLog())
+	err = writeRecoveredConfig(&vdb, c.recoverConfigOptions.Overwrite, vcc.GetLog())
 	if err != nil {
 		return fmt.Errorf("fail to write config file, details: %s", err)
 	}
@@ -0,0 +1,107 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdConfigRollback
+ *
+ * A subcommand restoring the YAML config file
+ * in the default or a specified directory
+ * to one of its backups.
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdConfigRollback struct {
+	rOptions vclusterops.DatabaseOptions
+	// the ID of the backup to restore, the latest one if it is empty
+	backupID string
+	CmdBase
+}
+
+func makeCmdConfigRollback() *cobra.Command {
+	newCmd := &CmdConfigRollback{}
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		configRollbackSubCmd,
+		"Restore the config file to one of its backups",
+		`This subcommand restores the config file to one of its backups, which are
+listed by manage_config history. It restores the latest backup, unless the
+ID of another one is given with --to. A backup that is not valid is not
+restored.
+
+The config file is backed up before it is restored, so a rollback can be
+undone by another rollback.
+
+Examples:
+  # Restore the cluster config file in the default location to its latest backup
+  vcluster manage_config rollback
+
+  # Restore the config file at /tmp/vertica_cluster.yaml to a given backup
+  vcluster manage_config rollback --to 20240102T030405.000000Z \
+    --config /tmp/vertica_cluster.yaml
+`,
+		[]string{configFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdConfigRollback) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.backupID,
+		"to",
+		"",
+		"The ID of the backup to restore, as listed by manage_config history. The default is the latest backup",
+	)
+}
+
+func (c *CmdConfigRollback) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	return nil
+}
+
+func (c *CmdConfigRollback) Run(_ context.Context, vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	backup, err := rollbackConfigFile(c.backupID, vcc.GetLog())
+	if err != nil {
+		vcc.LogError(err, "fail to roll back the config file")
+		return err
+	}
+	fmt.Printf("Restored the config file %s to its backup %s\n", dbOptions.ConfigPath, backup.ID)
+
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance
+func (c *CmdConfigRollback) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.rOptions = *opt
+}
@@ -16,18 +16,22 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"gopkg.in/yaml.v3"
 )
 
 /* CmdConfigShow
  *
  * A subcommand printing the YAML config file
- * in the default or a specified directory.
+ * in the default or a specified directory,
+ * with all the databases in it.
  *
  * Implements ClusterCommand interface
  */
@@ -43,7 +47,10 @@ func makeCmdConfigShow() *cobra.Command {
 		newCmd,
 		configShowSubCmd,
 		"Show the content of the config file",
-		`This subcommand prints the content of the config file.
+		`This subcommand prints the content of the config file, which lists all the
+databases in it and the default one, used when --db-name is not given.
+A config file of an older version is shown in the current format. A config
+file that is invalid is shown as it is, followed by its problems.
 
 Examples:
   # Show the cluster config file in the default location
@@ -65,16 +72,46 @@ func (c *CmdConfigShow) Parse(inputArgv []string, logger vlog.Printer) error {
 	return nil
 }
 
-func (c *CmdConfigShow) Run(_ vclusterops.ClusterCommands) error {
-	fileBytes, err := os.ReadFile(dbOptions.ConfigPath)
+func (c *CmdConfigShow) Run(_ context.Context, _ vclusterops.ClusterCommands) error {
+	config, err := readConfig()
 	if err != nil {
-		return fmt.Errorf("fail to read config file, details: %w", err)
+		return showInvalidConfig(err)
 	}
-	fmt.Printf("%s", string(fileBytes))
+	configBytes, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("fail to marshal config file, details: %w", err)
+	}
+	fmt.Printf("%s", string(configBytes))
 
 	return nil
 }
 
+// showInvalidConfig prints a config file that could not be read as it is,
+// followed by its problems, so that it can still be looked at and fixed
+func showInvalidConfig(readErr error) error {
+	if isConfigFileMissing(readErr) {
+		return readErr
+	}
+	configBytes, err := os.ReadFile(dbOptions.ConfigPath)
+	if err != nil {
+		return readErr
+	}
+	fmt.Printf("%s", string(configBytes))
+	if len(configBytes) > 0 && configBytes[len(configBytes)-1] != '\n' {
+		fmt.Println()
+	}
+
+	var problems configProblems
+	if !errors.As(readErr, &problems) {
+		return readErr
+	}
+	fmt.Println()
+	for _, problem := range problems {
+		fmt.Println(problem.String())
+	}
+	return fmt.Errorf("the config file %s has %d problem(s)", dbOptions.ConfigPath, len(problems))
+}
+
 // SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance
 func (c *CmdConfigShow) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
 	c.sOptions = *opt
@@ -0,0 +1,98 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdConfigValidate
+ *
+ * A subcommand validating the YAML config file
+ * in the default or a specified directory.
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdConfigValidate struct {
+	vOptions vclusterops.DatabaseOptions
+	CmdBase
+}
+
+func makeCmdConfigValidate() *cobra.Command {
+	newCmd := &CmdConfigValidate{}
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		configValidateSubCmd,
+		"Validate the content of the config file",
+		`This subcommand checks the content of the config file, and prints every
+problem found in it with its line. It checks for unknown keys, duplicate node
+names or addresses, paths that are not absolute, invalid subcluster names, and
+Eon fields in a database without a communal storage location.
+
+A config file of an older version is checked after it is migrated to the
+current version, so its problems are printed without lines.
+
+The commands that change a database check the config file in the same way,
+and fail if it is invalid.
+
+Examples:
+  # Validate the cluster config file in the default location
+  vcluster manage_config validate
+
+  # Validate the config file at /tmp/vertica_cluster.yaml
+  vcluster manage_config validate --config /tmp/vertica_cluster.yaml
+`,
+		[]string{configFlag},
+	)
+
+	return cmd
+}
+
+func (c *CmdConfigValidate) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	return nil
+}
+
+func (c *CmdConfigValidate) Run(_ context.Context, _ vclusterops.ClusterCommands) error {
+	_, err := readConfig()
+	var problems configProblems
+	if errors.As(err, &problems) {
+		for _, problem := range problems {
+			fmt.Println(problem.String())
+		}
+		return fmt.Errorf("the config file %s has %d problem(s)", dbOptions.ConfigPath, len(problems))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The config file %s is valid\n", dbOptions.ConfigPath)
+
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance
+func (c *CmdConfigValidate) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.vOptions = *opt
+}
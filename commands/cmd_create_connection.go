@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -104,7 +105,7 @@ func (c *CmdCreateConnection) Parse(inputArgv []string, logger vlog.Printer) err
 	return nil
 }
 
-func (c *CmdCreateConnection) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdCreateConnection) Run(_ context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	// write target db info to vcluster connection file
@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -33,6 +35,8 @@ import (
 type CmdCreateDB struct {
 	createDBOptions *vclusterops.VCreateDatabaseOptions
 	CmdBase
+	// the timeout, in seconds, to wait for the nodes to start
+	startupTimeoutSeconds int
 }
 
 func makeCmdCreateDB() *cobra.Command {
@@ -99,7 +103,7 @@ Examples:
     --password 12345678
 `,
 		[]string{dbNameFlag, hostsFlag, catalogPathFlag, dataPathFlag, depotPathFlag,
-			communalStorageLocationFlag, passwordFlag, configFlag, ipv6Flag, configParamFlag},
+			communalStorageLocationFlag, passwordFlag, configFlag, ipv6Flag, configParamFlag, dryRunFlag},
 	)
 	// local flags
 	newCmd.setLocalFlags(cmd)
@@ -206,10 +210,10 @@ func (c *CmdCreateDB) setLocalFlags(cmd *cobra.Command) {
 		"Skip the installation of packages from /opt/vertica/packages.",
 	)
 	cmd.Flags().IntVar(
-		&c.createDBOptions.TimeoutNodeStartupSeconds,
+		&c.startupTimeoutSeconds,
 		"startup-timeout",
 		util.DefaultTimeoutSeconds,
-		"The timeout to wait for the nodes to start",
+		"The timeout (in seconds) to wait for the nodes to start, --"+pollingTimeoutFlag+" replaces it if it is given",
 	)
 }
 
@@ -240,6 +244,7 @@ func (c *CmdCreateDB) Parse(inputArgv []string, logger vlog.Printer) error {
 	} else {
 		c.createDBOptions.IsEon = true
 	}
+	c.setPollingTimeout(&c.createDBOptions.DatabaseOptions, c.startupTimeoutSeconds)
 
 	return c.validateParse(logger)
 }
@@ -260,9 +265,10 @@ func (c *CmdCreateDB) validateParse(logger vlog.Printer) error {
 	return c.setDBPassword(&c.createDBOptions.DatabaseOptions)
 }
 
-func (c *CmdCreateDB) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdCreateDB) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
-	vdb, createError := vcc.VCreateDatabase(c.createDBOptions)
+	vdb, report, createError := vcc.VCreateDatabase(ctx, c.createDBOptions)
+	c.addReport(report)
 	if createError != nil {
 		return createError
 	}
@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -59,7 +61,7 @@ Examples:
   vcluster drop_db --db-name test_db \
     --config /opt/vertica/config/vertica_cluster.yaml
 `,
-		[]string{dbNameFlag, configFlag, hostsFlag, catalogPathFlag, dataPathFlag, depotPathFlag},
+		[]string{dbNameFlag, configFlag, hostsFlag, catalogPathFlag, dataPathFlag, depotPathFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -97,10 +99,11 @@ func (c *CmdDropDB) validateParse(logger vlog.Printer) error {
 	return c.ValidateParseBaseOptions(&c.dropDBOptions.DatabaseOptions)
 }
 
-func (c *CmdDropDB) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdDropDB) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
-	err := vcc.VDropDatabase(c.dropDBOptions)
+	report, err := vcc.VDropDatabase(ctx, c.dropDBOptions)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "failed do drop the database")
 		return err
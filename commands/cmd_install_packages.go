@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/spf13/cobra"
@@ -60,7 +61,7 @@ Examples:
   vcluster install_packages --db-name test_db --force-reinstall \
     --config /opt/vertica/config/vertica_cluster.yaml
 `,
-		[]string{dbNameFlag, configFlag, hostsFlag, passwordFlag, outputFileFlag},
+		[]string{dbNameFlag, configFlag, hostsFlag, passwordFlag, outputFileFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -109,10 +110,11 @@ func (c *CmdInstallPackages) Analyze(_ vlog.Printer) error {
 	return nil
 }
 
-func (c *CmdInstallPackages) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdInstallPackages) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	options := c.installPkgOpts
 
-	status, err := vcc.VInstallPackages(options)
+	status, report, err := vcc.VInstallPackages(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "failed to install the packages")
 		return err
@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -94,10 +95,11 @@ func (c *CmdListAllNodes) validateParse(logger vlog.Printer) error {
 	return c.setDBPassword(&c.fetchNodeStateOptions.DatabaseOptions)
 }
 
-func (c *CmdListAllNodes) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdListAllNodes) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
-	nodeStates, err := vcc.VFetchNodeState(c.fetchNodeStateOptions)
+	nodeStates, report, err := vcc.VFetchNodeState(ctx, c.fetchNodeStateOptions)
+	c.addReport(report)
 	if err != nil {
 		// if all nodes are down, the nodeStates list is not empty
 		// for this case, we don't want to show errors but show DOWN for the nodes
@@ -30,10 +30,15 @@ import (
 func makeCmdManageConfig() *cobra.Command {
 	cmd := makeSimpleCobraCmd(
 		manageConfigSubCmd,
-		"Show or recover the content of the config file",
-		`This subcommand is used to print or recover the content of the config file.`)
+		"Show, validate, compare, roll back or recover the content of the config file",
+		`This subcommand is used to print, validate, compare with the live database, list the backups,
+roll back or recover the content of the config file.`)
 
 	cmd.AddCommand(makeCmdConfigShow())
+	cmd.AddCommand(makeCmdConfigValidate())
+	cmd.AddCommand(makeCmdConfigDiff())
+	cmd.AddCommand(makeCmdConfigHistory())
+	cmd.AddCommand(makeCmdConfigRollback())
 	cmd.AddCommand(makeCmdConfigRecover())
 
 	return cmd
@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -66,7 +67,7 @@ Examples:
   vcluster re_ip --db-name test_db --re-ip-file /data/re_ip_map.json \
     --config /opt/vertica/config/vertica_cluster.yaml
 `,
-		[]string{dbNameFlag, hostsFlag, catalogPathFlag, configParamFlag, configFlag},
+		[]string{dbNameFlag, hostsFlag, catalogPathFlag, configParamFlag, configFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -111,20 +112,24 @@ func (c *CmdReIP) validateParse(logger vlog.Printer) error {
 	return c.reIPOptions.ReadReIPFile(c.reIPFilePath)
 }
 
-func (c *CmdReIP) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdReIP) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	options := c.reIPOptions
 
 	// load config info from the YAML config file
 	canUpdateConfig := true
-	dbConfig, err := readConfig()
+	config, err := readConfig()
+	if err == nil {
+		_, err = config.getDatabase(options.DBName)
+	}
 	if err != nil {
 		vcc.LogInfo("fail to read config file: %v", err)
 		canUpdateConfig = false
 	}
 
-	err = vcc.VReIP(options)
+	report, err := vcc.VReIP(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "fail to re-ip")
 		return err
@@ -134,8 +139,15 @@ func (c *CmdReIP) Run(vcc vclusterops.ClusterCommands) error {
 
 	// update config file after running re_ip
 	if canUpdateConfig {
-		c.UpdateConfig(dbConfig)
-		err = dbConfig.write(options.ConfigPath)
+		// the file is read again, as it may have been updated during re_ip
+		err = updateConfigFile(vcc.GetLog(), func(latestConfig *Config) error {
+			dbConfig, dbErr := latestConfig.getDatabase(options.DBName)
+			if dbErr != nil {
+				return dbErr
+			}
+			c.UpdateConfig(dbConfig)
+			return nil
+		})
 		if err != nil {
 			fmt.Printf("Warning: fail to update config file, details %v\n", err)
 		}
@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -61,7 +62,7 @@ Examples:
   vcluster db_remove_node --db-name test_db --remove 10.20.30.42 \
     --hosts 10.20.30.40 --data-path /data
 `,
-		[]string{dbNameFlag, configFlag, hostsFlag, catalogPathFlag, dataPathFlag, depotPathFlag, passwordFlag},
+		[]string{dbNameFlag, configFlag, hostsFlag, catalogPathFlag, dataPathFlag, depotPathFlag, passwordFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -133,12 +134,13 @@ func (c *CmdRemoveNode) parseHostToRemoveList() error {
 	return nil
 }
 
-func (c *CmdRemoveNode) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdRemoveNode) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	options := c.removeNodeOptions
 
-	vdb, err := vcc.VRemoveNode(options)
+	vdb, report, err := vcc.VRemoveNode(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/vertica/vcluster/vclusterops"
@@ -59,7 +61,7 @@ Examples:
     --hosts 10.20.30.40,10.20.30.41,10.20.30.42 --subcluster sc1 \
     --data-path /data --depot-path /data
 `,
-		[]string{dbNameFlag, configFlag, hostsFlag, eonModeFlag, dataPathFlag, depotPathFlag, passwordFlag},
+		[]string{dbNameFlag, configFlag, hostsFlag, eonModeFlag, dataPathFlag, depotPathFlag, passwordFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -123,12 +125,13 @@ func (c *CmdRemoveSubcluster) Analyze(_ vlog.Printer) error {
 	return nil
 }
 
-func (c *CmdRemoveSubcluster) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdRemoveSubcluster) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
 	options := c.removeScOptions
 
-	vdb, err := vcc.VRemoveSubcluster(options)
+	vdb, report, err := vcc.VRemoveSubcluster(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		return err
 	}
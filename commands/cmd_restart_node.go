@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -32,6 +34,8 @@ type CmdRestartNodes struct {
 
 	// Comma-separated list of vnode=host
 	vnodeListStr map[string]string
+	// the timeout, in seconds, to wait for the nodes to come up
+	pollingTimeoutSeconds int
 }
 
 func makeCmdRestartNodes() *cobra.Command {
@@ -70,7 +74,7 @@ Examples:
     --restart v_test_db_node0003=10.20.30.42,v_test_db_node0004=10.20.30.43 \
     --password testpassword --config /opt/vertica/config/vertica_cluster.yaml	
 `,
-		[]string{dbNameFlag, hostsFlag, configFlag, passwordFlag},
+		[]string{dbNameFlag, hostsFlag, configFlag, passwordFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -91,10 +95,10 @@ func (c *CmdRestartNodes) setLocalFlags(cmd *cobra.Command) {
 		"Comma-separated list of <node_name=re_ip_host> pairs part of the database nodes that need to be restarted",
 	)
 	cmd.Flags().IntVar(
-		&c.restartNodesOptions.StatePollingTimeout,
+		&c.pollingTimeoutSeconds,
 		"timeout",
 		util.DefaultTimeoutSeconds,
-		"The timeout (in seconds) to wait for polling node state operation",
+		"The timeout (in seconds) to wait for polling node state operation, --"+pollingTimeoutFlag+" replaces it if it is given",
 	)
 }
 
@@ -106,6 +110,7 @@ func (c *CmdRestartNodes) Parse(inputArgv []string, logger vlog.Printer) error {
 	// if they are not provided in cli,
 	// reset the value of those options to nil
 	c.ResetUserInputOptions(&c.restartNodesOptions.DatabaseOptions)
+	c.setPollingTimeout(&c.restartNodesOptions.DatabaseOptions, c.pollingTimeoutSeconds)
 
 	return c.validateParse(logger)
 }
@@ -129,13 +134,14 @@ func (c *CmdRestartNodes) validateParse(logger vlog.Printer) error {
 	return c.setDBPassword(&c.restartNodesOptions.DatabaseOptions)
 }
 
-func (c *CmdRestartNodes) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdRestartNodes) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
 	options := c.restartNodesOptions
 
 	// this is the instruction that will be used by both CLI and operator
-	err := vcc.VStartNodes(options)
+	report, err := vcc.VStartNodes(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		return err
 	}
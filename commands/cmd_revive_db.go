@@ -16,7 +16,9 @@
 package commands
 
 import (
+	"context"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
@@ -31,6 +33,8 @@ import (
 type CmdReviveDB struct {
 	CmdBase
 	reviveDBOptions *vclusterops.VReviveDatabaseOptions
+	// the timeout, in seconds, of loading the remote catalog
+	loadCatalogTimeoutSeconds uint
 }
 
 func makeCmdReviveDB() *cobra.Command {
@@ -80,7 +84,7 @@ Examples:
     --ignore-cluster-lease --restore-point-archive db --restore-point-index 1
 
 `,
-		[]string{dbNameFlag, hostsFlag, communalStorageLocationFlag, configFlag, outputFileFlag, configParamFlag},
+		[]string{dbNameFlag, hostsFlag, communalStorageLocationFlag, configFlag, outputFileFlag, configParamFlag, dryRunFlag, resumeFlag},
 	)
 
 	// local flags
@@ -95,7 +99,7 @@ Examples:
 // setLocalFlags will set the local flags the command has
 func (c *CmdReviveDB) setLocalFlags(cmd *cobra.Command) {
 	cmd.Flags().UintVar(
-		&c.reviveDBOptions.LoadCatalogTimeout,
+		&c.loadCatalogTimeoutSeconds,
 		"load-catalog-timeout",
 		util.DefaultLoadCatalogTimeoutSeconds,
 		"Set a timeout (in seconds) for loading remote catalog operation, default timeout is "+
@@ -146,6 +150,7 @@ func (c *CmdReviveDB) setLocalFlags(cmd *cobra.Command) {
 func (c *CmdReviveDB) Parse(inputArgv []string, logger vlog.Printer) error {
 	c.argv = inputArgv
 	logger.LogArgParse(&c.argv)
+	c.reviveDBOptions.Timeouts.LoadCatalog = time.Duration(c.loadCatalogTimeoutSeconds) * time.Second
 
 	return c.validateParse(logger)
 }
@@ -163,12 +168,18 @@ func (c *CmdReviveDB) validateParse(logger vlog.Printer) error {
 		return nil
 	}
 
-	return c.ValidateParseBaseOptions(&c.reviveDBOptions.DatabaseOptions)
+	err = c.ValidateParseBaseOptions(&c.reviveDBOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	c.setJournal(&c.reviveDBOptions.DatabaseOptions, reviveDBSubCmd)
+	return nil
 }
 
-func (c *CmdReviveDB) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdReviveDB) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
-	dbInfo, vdb, err := vcc.VReviveDatabase(c.reviveDBOptions)
+	dbInfo, vdb, report, err := vcc.VReviveDatabase(ctx, c.reviveDBOptions)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "fail to revive database", "DBName", c.reviveDBOptions.DBName)
 		return err
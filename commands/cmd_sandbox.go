@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -69,7 +71,7 @@ Examples:
   vcluster sandbox_subcluster --subcluster sc1 --sandbox sand \
     --hosts 10.20.30.40,10.20.30.41,10.20.30.42 --db-name test_db
 `,
-		[]string{dbNameFlag, configFlag, hostsFlag, passwordFlag},
+		[]string{dbNameFlag, configFlag, hostsFlag, passwordFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -124,13 +126,14 @@ func (c *CmdSandboxSubcluster) Analyze(logger vlog.Printer) error {
 	return nil
 }
 
-func (c *CmdSandboxSubcluster) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdSandboxSubcluster) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.PrintInfo("Running sandbox subcluster")
 	vcc.LogInfo("Calling method Run() for command " + sandboxSubCmd)
 
 	options := c.sbOptions
 
-	err := vcc.VSandbox(&options)
+	report, err := vcc.VSandbox(ctx, &options)
+	c.addReport(report)
 	vcc.PrintInfo("Completed method Run() for command " + sandboxSubCmd)
 	return err
 }
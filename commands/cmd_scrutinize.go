@@ -225,7 +225,7 @@ func (c *CmdScrutinize) validateParse(logger vlog.Printer) error {
 	return c.setDBPassword(&c.sOptions.DatabaseOptions)
 }
 
-func (c *CmdScrutinize) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdScrutinize) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.PrintInfo("Running scrutinize") // TODO remove when no longer needed for tests
 	vcc.LogInfo("Calling method Run()")
 
@@ -247,7 +247,8 @@ func (c *CmdScrutinize) Run(vcc vclusterops.ClusterCommands) error {
 		return err
 	}
 
-	err = vcc.VScrutinize(&c.sOptions)
+	report, err := vcc.VScrutinize(ctx, &c.sOptions)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "scrutinize run failed")
 		return err
@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -153,12 +155,13 @@ func (c *CmdShowRestorePoints) Analyze(logger vlog.Printer) error {
 	return nil
 }
 
-func (c *CmdShowRestorePoints) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdShowRestorePoints) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
 	options := c.showRestorePointsOptions
 
-	restorePoints, err := vcc.VShowRestorePoints(options)
+	restorePoints, report, err := vcc.VShowRestorePoints(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "fail to show restore points", "DBName", options.DBName)
 		return err
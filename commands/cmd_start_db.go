@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -35,6 +37,8 @@ type CmdStartDB struct {
 	IgnoreClusterLease  bool // ignore the cluster lease in communal storage
 	Unsafe              bool // Start database unsafely, skipping recovery.
 	Fast                bool // Attempt fast startup database
+	// the timeout, in seconds, to wait for the nodes to come up
+	pollingTimeoutSeconds int
 }
 
 func makeCmdStartDB() *cobra.Command {
@@ -67,7 +71,7 @@ Examples:
     --config /opt/vertica/config/vertica_cluster.yaml
 `,
 		[]string{dbNameFlag, hostsFlag, communalStorageLocationFlag,
-			configFlag, catalogPathFlag, passwordFlag, eonModeFlag, configParamFlag},
+			configFlag, catalogPathFlag, passwordFlag, eonModeFlag, configParamFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -83,10 +87,10 @@ Examples:
 // setLocalFlags will set the local flags the command has
 func (c *CmdStartDB) setLocalFlags(cmd *cobra.Command) {
 	cmd.Flags().IntVar(
-		&c.startDBOptions.StatePollingTimeout,
+		&c.pollingTimeoutSeconds,
 		"timeout",
 		util.DefaultTimeoutSeconds,
-		"The timeout (in seconds) to wait for polling node state operation",
+		"The timeout (in seconds) to wait for polling node state operation, --"+pollingTimeoutFlag+" replaces it if it is given",
 	)
 }
 
@@ -137,6 +141,7 @@ func (c *CmdStartDB) Parse(inputArgv []string, logger vlog.Printer) error {
 	logger.LogMaskedArgParse(c.argv)
 
 	c.ResetUserInputOptions(&c.startDBOptions.DatabaseOptions)
+	c.setPollingTimeout(&c.startDBOptions.DatabaseOptions, c.pollingTimeoutSeconds)
 	return c.validateParse(logger)
 }
 
@@ -155,12 +160,13 @@ func (c *CmdStartDB) validateParse(logger vlog.Printer) error {
 	return c.setDBPassword(&c.startDBOptions.DatabaseOptions)
 }
 
-func (c *CmdStartDB) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdStartDB) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
 	options := c.startDBOptions
 
-	vdb, err := vcc.VStartDatabase(options)
+	vdb, report, err := vcc.VStartDatabase(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "failed to start the database")
 		return err
@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -88,7 +89,7 @@ Examples:
     --target-hosts 10.20.30.43 --password-file /path/to/password-file --target-db-user dbadmin \ 
     --target-password-file /path/to/password-file
 `,
-		[]string{dbNameFlag, hostsFlag, ipv6Flag, configFlag, passwordFlag, dbUserFlag, eonModeFlag, connFlag},
+		[]string{dbNameFlag, hostsFlag, ipv6Flag, configFlag, passwordFlag, dbUserFlag, eonModeFlag, connFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -222,12 +223,13 @@ func (c *CmdStartReplication) parseTargetPassword() error {
 	return nil
 }
 
-func (c *CmdStartReplication) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdStartReplication) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	options := c.startRepOptions
 
-	err := vcc.VReplicateDatabase(options)
+	report, err := vcc.VReplicateDatabase(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "fail to replicate to database", "targetDB", options.TargetDB)
 		return err
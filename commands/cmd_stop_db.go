@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -55,7 +56,7 @@ Examples:
   vcluster stop_db --password testpassword \
     --config /opt/vertica/config/vertica_cluster.yaml
 `,
-		[]string{dbNameFlag, hostsFlag, ipv6Flag, eonModeFlag, configFlag, passwordFlag},
+		[]string{dbNameFlag, hostsFlag, ipv6Flag, eonModeFlag, configFlag, passwordFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -140,12 +141,13 @@ func (c *CmdStopDB) validateParse(logger vlog.Printer) error {
 	return c.setDBPassword(&c.stopDBOptions.DatabaseOptions)
 }
 
-func (c *CmdStopDB) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdStopDB) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	options := c.stopDBOptions
 
-	err := vcc.VStopDatabase(options)
+	report, err := vcc.VStopDatabase(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "failed to stop the database")
 		return err
@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -53,7 +55,7 @@ Examples:
   vcluster stop_node --db-name test_db --stop-hosts 10.20.30.40,10.20.30.41 \
     --hosts 10.20.30.40,10.20.30.41,10.20.30.42 
 `,
-		[]string{dbNameFlag, hostsFlag, configFlag, passwordFlag},
+		[]string{dbNameFlag, hostsFlag, configFlag, passwordFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -97,12 +99,13 @@ func (c *CmdStopNode) validateParse(logger vlog.Printer) error {
 	return c.setDBPassword(&c.stopNodeOptions.DatabaseOptions)
 }
 
-func (c *CmdStopNode) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdStopNode) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	options := c.stopNodeOptions
 
-	err := vcc.VStopNode(options)
+	report, err := vcc.VStopNode(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "failed to stop the nodes", "Nodes", c.stopNodeOptions.StopHosts)
 		return err
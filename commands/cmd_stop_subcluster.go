@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -71,7 +72,7 @@ Examples:
   vcluster stop_subcluster --db-name test_db --subcluster sc1 \
     --hosts 10.20.30.40,10.20.30.41,10.20.30.42 --force
 `,
-		[]string{dbNameFlag, hostsFlag, ipv6Flag, eonModeFlag, configFlag, passwordFlag},
+		[]string{dbNameFlag, hostsFlag, ipv6Flag, eonModeFlag, configFlag, passwordFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -143,12 +144,13 @@ func (c *CmdStopSubcluster) validateParse(logger vlog.Printer) error {
 	return c.setDBPassword(&c.stopSCOptions.DatabaseOptions)
 }
 
-func (c *CmdStopSubcluster) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdStopSubcluster) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	options := c.stopSCOptions
 
-	err := vcc.VStopSubcluster(options)
+	report, err := vcc.VStopSubcluster(ctx, options)
+	c.addReport(report)
 	if err != nil {
 		vcc.LogError(err, "failed to stop the subcluster", "Subcluster", options.SCName)
 		return err
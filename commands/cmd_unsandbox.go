@@ -16,6 +16,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -73,7 +75,7 @@ Examples:
   vcluster unsandbox_subcluster --subcluster sc1 \
     --hosts 10.20.30.40,10.20.30.41,10.20.30.42 --db-name test_db
 `,
-		[]string{dbNameFlag, configFlag, passwordFlag, hostsFlag},
+		[]string{dbNameFlag, configFlag, passwordFlag, hostsFlag, dryRunFlag},
 	)
 
 	// local flags
@@ -124,13 +126,14 @@ func (c *CmdUnsandboxSubcluster) Analyze(logger vlog.Printer) error {
 	return nil
 }
 
-func (c *CmdUnsandboxSubcluster) Run(vcc vclusterops.ClusterCommands) error {
+func (c *CmdUnsandboxSubcluster) Run(ctx context.Context, vcc vclusterops.ClusterCommands) error {
 	vcc.PrintInfo("Running unsandbox subcluster")
 	vcc.LogInfo("Calling method Run() for command " + unsandboxSubCmd)
 
 	options := c.usOptions
 
-	err := vcc.VUnsandbox(&options)
+	report, err := vcc.VUnsandbox(ctx, &options)
+	c.addReport(report)
 	vcc.PrintInfo("Completed method Run() for command " + unsandboxSubCmd)
 	return err
 }
@@ -16,6 +16,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"os"
 	"testing"
@@ -79,7 +80,7 @@ func TestScrutinCmd(t *testing.T) {
 	c := &CmdScrutinize{}
 	c.sOptions = vclusterops.VScrutinizeOptionsFactory()
 	c.SetParser(&pflag.FlagSet{})
-	err := c.Run(vclusterops.VClusterCommands{})
+	err := c.Run(context.Background(), vclusterops.VClusterCommands{})
 	assert.ErrorContains(t, err, "must specify a host or host list")
 	assert.Equal(t, dbName, c.sOptions.DBName)
 	assert.Equal(t, catalogPath, c.sOptions.CatalogPrefix)
@@ -89,7 +90,7 @@ func TestScrutinCmd(t *testing.T) {
 	c = &CmdScrutinize{}
 	c.sOptions = vclusterops.VScrutinizeOptionsFactory()
 	c.SetParser(&pflag.FlagSet{})
-	err = c.Run(vclusterops.VClusterCommands{})
+	err = c.Run(context.Background(), vclusterops.VClusterCommands{})
 	assert.ErrorContains(t, err, "unable to get catalog path from environment variable")
 
 	// Database Name not provided
@@ -98,7 +99,7 @@ func TestScrutinCmd(t *testing.T) {
 	c = &CmdScrutinize{}
 	c.sOptions = vclusterops.VScrutinizeOptionsFactory()
 	c.SetParser(&pflag.FlagSet{})
-	err = c.Run(vclusterops.VClusterCommands{})
+	err = c.Run(context.Background(), vclusterops.VClusterCommands{})
 	assert.ErrorContains(t, err, "unable to get database name from environment variable")
 }
 
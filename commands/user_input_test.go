@@ -58,14 +58,24 @@ func TestConfigRecover(t *testing.T) {
 		"--hosts 192.168.1.101")
 	assert.ErrorContains(t, err, `required flag(s) "catalog-path" not set`)
 
-	tempConfig, _ := os.Create(tempConfigFilePath)
-	tempConfig.Close()
+	// the database of an existing config file is not overwritten by default
+	config := Config{}
+	config.setDatabase(makeTestDatabaseConfig("test_db"))
+	assert.NoError(t, config.write(tempConfigFilePath))
 	defer os.Remove(tempConfigFilePath)
 
 	err = simulateVClusterCli("vcluster manage_config recover --db-name test_db " +
 		"--hosts 192.168.1.101 --catalog-path /data " +
 		"--config " + tempConfigFilePath)
 	assert.ErrorContains(t, err, "config file exists at "+tempConfigFilePath)
+
+	// nor is an invalid config file replaced
+	err = os.WriteFile(tempConfigFilePath, []byte("dbName: test_db\nfoo: bar\n"), configFilePerm)
+	assert.NoError(t, err)
+	err = simulateVClusterCli("vcluster manage_config recover --db-name test_db " +
+		"--hosts 192.168.1.101 --catalog-path /data " +
+		"--config " + tempConfigFilePath)
+	assert.ErrorContains(t, err, "You can use --overwrite to replace it")
 }
 
 func TestManageConfig(t *testing.T) {
@@ -75,6 +85,27 @@ func TestManageConfig(t *testing.T) {
 
 	err = simulateVClusterCli("vcluster manage_config show recover")
 	assert.ErrorContains(t, err, `unknown command "recover" for "vcluster manage_config show"`)
+
+	// the problems of an invalid config file are reported
+	err = os.WriteFile(tempConfigFilePath, []byte("dbName: test_db\nfoo: bar\n"), configFilePerm)
+	assert.NoError(t, err)
+	defer os.Remove(tempConfigFilePath)
+	err = simulateVClusterCli("vcluster manage_config validate --config " + tempConfigFilePath)
+	assert.ErrorContains(t, err, "has 2 problem(s)")
+	// and the file is still shown, as it is
+	err = simulateVClusterCli("vcluster manage_config show --config " + tempConfigFilePath)
+	assert.ErrorContains(t, err, "has 2 problem(s)")
+
+	// the config file can only be rolled back to one of its backups
+	err = simulateVClusterCli("vcluster manage_config history --config " + tempConfigFilePath)
+	assert.NoError(t, err)
+	err = simulateVClusterCli("vcluster manage_config rollback --config " + tempConfigFilePath)
+	assert.ErrorContains(t, err, "has no backup")
+	os.Remove(tempConfigFilePath + configLockSuffix)
+
+	// only the known kinds of differences can be reconciled
+	err = simulateVClusterCli("vcluster manage_config diff --db-name test_db --hosts 192.168.1.101 --reconcile nodes")
+	assert.ErrorContains(t, err, `invalid kind of difference "nodes"`)
 }
 
 func TestManageReplication(t *testing.T) {
@@ -16,15 +16,20 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/slices"
 	"gopkg.in/yaml.v3"
 )
 
@@ -33,15 +38,18 @@ const (
 	// If no config file was provided, we will pick a default one. This is the
 	// default file name that we'll use.
 	defConfigFileName        = "vertica_cluster.yaml"
-	currentConfigFileVersion = "1.0"
-	configBackupName         = "vertica_cluster.yaml.backup"
+	currentConfigFileVersion = "3.0"
 	configFilePerm           = 0600
+	configFileVersionKey     = "configFileVersion"
+	catalogSubdir            = "Catalog"
 )
 
-// Config is the struct of vertica_cluster.yaml
+// Config is the struct of vertica_cluster.yaml. It holds the databases that
+// vcluster manages, and the one used when --db-name is not given.
 type Config struct {
-	Version  string         `yaml:"configFileVersion"`
-	Database DatabaseConfig `yaml:",inline"`
+	Version         string            `yaml:"configFileVersion"`
+	DefaultDatabase string            `yaml:"defaultDatabase"`
+	Databases       []*DatabaseConfig `yaml:"databases"`
 }
 
 // DatabaseConfig contains basic information for operating a database
@@ -51,16 +59,34 @@ type DatabaseConfig struct {
 	IsEon                   bool          `yaml:"eonMode" mapstructure:"eonMode"`
 	CommunalStorageLocation string        `yaml:"communalStorageLocation" mapstructure:"communalStorageLocation"`
 	Ipv6                    bool          `yaml:"ipv6" mapstructure:"ipv6"`
+	// the size of the depot of each node, such as 50% or 20G
+	DepotSize string `yaml:"depotSize,omitempty" mapstructure:"depotSize"`
 }
 
 // NodeConfig contains node information in the database
 type NodeConfig struct {
-	Name        string `yaml:"name" mapstructure:"name"`
-	Address     string `yaml:"address" mapstructure:"address"`
-	Subcluster  string `yaml:"subcluster" mapstructure:"subcluster"`
+	Name       string `yaml:"name" mapstructure:"name"`
+	Address    string `yaml:"address" mapstructure:"address"`
+	Subcluster string `yaml:"subcluster" mapstructure:"subcluster"`
+	// the commands get whether the node is primary, and its sandbox, from
+	// the database. They are kept in the file to describe the cluster, and
+	// manage_config diff tells when they are out of date.
+	IsPrimary bool `yaml:"isPrimary" mapstructure:"isPrimary"`
+	// empty if the node is not in a sandbox
+	Sandbox string `yaml:"sandbox,omitempty" mapstructure:"sandbox"`
+	// the address family of the control address, ipv4 or ipv6
+	ControlAddressFamily string `yaml:"controlAddressFamily,omitempty" mapstructure:"controlAddressFamily"`
+	// complete paths of the node, not just prefixes. The commands look for
+	// the catalog and the depot of the node in its own paths, and use the
+	// data path and the storage locations that the database reports.
 	CatalogPath string `yaml:"catalogPath" mapstructure:"catalogPath"`
 	DataPath    string `yaml:"dataPath" mapstructure:"dataPath"`
 	DepotPath   string `yaml:"depotPath" mapstructure:"depotPath"`
+	// the storage locations of the node other than its data path
+	StorageLocations []string `yaml:"storageLocations,omitempty" mapstructure:"storageLocations"`
+	// ports are only stored if they differ from the default ones
+	NMAPort   int `yaml:"nmaPort,omitempty" mapstructure:"nmaPort"`
+	HTTPSPort int `yaml:"httpsPort,omitempty" mapstructure:"httpsPort"`
 }
 
 // MakeDatabaseConfig() can create an instance of DatabaseConfig
@@ -145,27 +171,40 @@ func initConfigImpl(vclusterExePath string, ensureOptVerticaConfigExists, ensure
 	dbOptions.ConfigPath = fmt.Sprintf("%s/%s", path, defConfigFileName)
 }
 
-// loadConfigToViper can fill viper keys using vertica_cluster.yaml
-func loadConfigToViper() error {
+// loadConfigToViper can fill viper keys using vertica_cluster.yaml.
+// An invalid config file is an error if mustBeValid is true, and is
+// ignored with a warning otherwise.
+func loadConfigToViper(mustBeValid bool) error {
 	// read config file
-	viper.SetConfigFile(dbOptions.ConfigPath)
-	err := viper.ReadInConfig()
+	config, err := readConfig()
 	if err != nil {
+		if mustBeValid && !isConfigFileMissing(err) {
+			return fmt.Errorf("invalid configuration file %q: %w", dbOptions.ConfigPath, err)
+		}
 		fmt.Printf("Warning: fail to read configuration file %q for viper: %v\n", dbOptions.ConfigPath, err)
 		return nil
 	}
 
-	// retrieve db info in viper
-	dbConfig := MakeDatabaseConfig()
-	err = viper.Unmarshal(&dbConfig)
+	// the database in the config file is the one in user input, or the default one
+	var dbName string
+	if viper.IsSet(dbNameKey) {
+		dbName = viper.GetString(dbNameKey)
+	}
+	dbConfig, err := config.getDatabase(dbName)
 	if err != nil {
-		fmt.Printf("Warning: fail to unmarshal configuration file into DatabaseConfig: %v\n", err)
-		return nil
+		return err
 	}
 
-	// if we can read config file, check if dbName in user input matches the one in config file
-	if viper.IsSet(dbNameKey) && dbConfig.Name != viper.GetString(dbNameKey) {
-		return fmt.Errorf("database %q does not match name found in the configuration file %q", dbConfig.Name, viper.GetString(dbNameKey))
+	// retrieve db info in viper. The user input takes precedence over it,
+	// as viper reads it as a config file.
+	dbConfigBytes, err := yaml.Marshal(dbConfig)
+	if err != nil {
+		return fmt.Errorf("fail to marshal database %q of the configuration file, details: %w", dbConfig.Name, err)
+	}
+	viper.SetConfigType("yaml")
+	err = viper.ReadConfig(bytes.NewReader(dbConfigBytes))
+	if err != nil {
+		return fmt.Errorf("fail to load database %q of the configuration file, details: %w", dbConfig.Name, err)
 	}
 
 	// hosts, catalogPrefix, dataPrefix, depotPrefix are special in config file,
@@ -173,6 +212,15 @@ func loadConfigToViper() error {
 	if !viper.IsSet(hostsKey) {
 		viper.Set(hostsKey, dbConfig.getHosts())
 	}
+	// the catalog of each node is looked for in its own path, unless the
+	// user has given the catalog path of all hosts in the cli
+	if !viper.IsSet(catalogPathKey) {
+		dbOptions.HostCatalogPrefixes = dbConfig.getHostCatalogPrefixes()
+	}
+	// so is the depot of each node that the database does not report
+	if !viper.IsSet(depotPathKey) {
+		dbOptions.HostDepotPrefixes = dbConfig.getHostDepotPrefixes()
+	}
 	catalogPrefix, dataPrefix, depotPrefix := dbConfig.getPathPrefixes()
 	if !viper.IsSet(catalogPathKey) {
 		viper.Set(catalogPathKey, catalogPrefix)
@@ -183,51 +231,67 @@ func loadConfigToViper() error {
 	if !viper.IsSet(depotPathKey) {
 		viper.Set(depotPathKey, depotPrefix)
 	}
+	// ports are also stored in each node. The ones in the config file are
+	// ignored if the user has given the ports of all hosts in the cli.
+	nmaPorts, httpsPorts := dbConfig.getHostPorts()
+	if len(nmaPorts) > 0 && !viper.IsSet(hostNMAPortsKey) && !viper.IsSet(nmaPortKey) {
+		viper.Set(hostNMAPortsKey, nmaPorts)
+	}
+	if len(httpsPorts) > 0 && !viper.IsSet(hostHTTPSPortsKey) && !viper.IsSet(httpsPortKey) {
+		viper.Set(hostHTTPSPortsKey, httpsPorts)
+	}
 	return nil
 }
 
 // writeConfig can write database information to vertica_cluster.yaml.
 // It will be called in the end of some subcommands that will change the db state.
+// The other databases in the config file are kept.
 func writeConfig(vdb *vclusterops.VCoordinationDatabase, logger vlog.Printer) error {
-	if dbOptions.ConfigPath == "" {
-		return fmt.Errorf("configuration file path is empty")
-	}
-
 	dbConfig, err := readVDBToDBConfig(vdb)
 	if err != nil {
 		return err
 	}
 
-	// if the config file exists already,
-	// create its backup before overwriting it
-	err = backupConfigFile(dbOptions.ConfigPath, logger)
-	if err != nil {
-		return err
-	}
+	return writeDatabaseConfig(&dbConfig, logger)
+}
 
-	// update db config with the given database info
-	err = dbConfig.write(dbOptions.ConfigPath)
+// writeRecoveredConfig writes the database recovered by manage_config recover
+// to vertica_cluster.yaml. The other databases in the file are kept, and the
+// database is only replaced if overwrite is true. A file that is invalid is
+// replaced as a whole if overwrite is true, as recover is how it is repaired.
+func writeRecoveredConfig(vdb *vclusterops.VCoordinationDatabase, overwrite bool, logger vlog.Printer) error {
+	dbConfig, err := readVDBToDBConfig(vdb)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return updateConfigFileImpl(logger, overwrite, func(config *Config) error {
+		err := config.checkRecover(dbConfig.Name, overwrite)
+		if err != nil {
+			return err
+		}
+		config.setDatabase(&dbConfig)
+		return nil
+	})
+}
+
+// writeDatabaseConfig replaces the database of the same name in
+// vertica_cluster.yaml, or adds it if it is new
+func writeDatabaseConfig(dbConfig *DatabaseConfig, logger vlog.Printer) error {
+	return updateConfigFile(logger, func(config *Config) error {
+		config.setDatabase(dbConfig)
+		return nil
+	})
 }
 
-// removeConfig remove the config file vertica_cluster.yaml.
+// removeConfig removes the database from the config file vertica_cluster.yaml,
+// and removes the file if no database is left in it.
 // It will be called in the end of drop_db subcommands.
 func removeConfig(logger vlog.Printer) error {
-	if dbOptions.ConfigPath == "" {
-		return fmt.Errorf("configuration file path is empty")
-	}
-	// back up the old config file
-	err := backupConfigFile(dbOptions.ConfigPath, logger)
-	if err != nil {
-		return err
-	}
-
-	// remove the old db config
-	return os.Remove(dbOptions.ConfigPath)
+	return updateConfigFile(logger, func(config *Config) error {
+		config.removeDatabase(dbOptions.DBName)
+		return nil
+	})
 }
 
 // readVDBToDBConfig converts vdb to DatabaseConfig
@@ -243,55 +307,68 @@ func readVDBToDBConfig(vdb *vclusterops.VCoordinationDatabase) (DatabaseConfig,
 		nodeConfig.Name = vnode.Name
 		nodeConfig.Address = vnode.Address
 		nodeConfig.Subcluster = vnode.Subcluster
-
-		// VER-91869 will replace the path prefixes with full paths
-		if vdb.CatalogPrefix == "" {
-			nodeConfig.CatalogPath = util.GetPathPrefix(vnode.CatalogPath)
-		} else {
-			nodeConfig.CatalogPath = vdb.CatalogPrefix
+		nodeConfig.IsPrimary = vnode.IsPrimary
+		nodeConfig.Sandbox = vnode.Sandbox
+		nodeConfig.ControlAddressFamily = vnode.ControlAddressFamily
+
+		nodeConfig.CatalogPath = getCatalogDir(vnode.CatalogPath)
+		if len(vnode.StorageLocations) > 0 {
+			nodeConfig.DataPath = vnode.StorageLocations[0]
+			nodeConfig.StorageLocations = append(nodeConfig.StorageLocations, vnode.StorageLocations[1:]...)
 		}
-		if vdb.DataPrefix == "" && len(vnode.StorageLocations) > 0 {
-			nodeConfig.DataPath = util.GetPathPrefix(vnode.StorageLocations[0])
-		} else {
-			nodeConfig.DataPath = vdb.DataPrefix
+		for _, location := range vnode.UserStorageLocations {
+			if !slices.Contains(nodeConfig.StorageLocations, location) {
+				nodeConfig.StorageLocations = append(nodeConfig.StorageLocations, location)
+			}
 		}
-		if vdb.IsEon && vdb.DepotPrefix == "" {
-			nodeConfig.DepotPath = util.GetPathPrefix(vnode.DepotPath)
-		} else {
-			nodeConfig.DepotPath = vdb.DepotPrefix
+		if vdb.IsEon {
+			nodeConfig.DepotPath = vnode.DepotPath
 		}
 
+		nodeConfig.NMAPort, nodeConfig.HTTPSPort = getNonDefaultPorts(vnode.Address)
+
 		dbConfig.Nodes = append(dbConfig.Nodes, &nodeConfig)
 	}
 	dbConfig.IsEon = vdb.IsEon
 	dbConfig.CommunalStorageLocation = vdb.CommunalStorageLocation
 	dbConfig.Ipv6 = vdb.Ipv6
 	dbConfig.Name = vdb.Name
+	dbConfig.DepotSize = vdb.DepotSize
 
 	return dbConfig, nil
 }
 
-// backupConfigFile backs up config file before we update it.
-// This function will add ".backup" suffix to previous config file.
-func backupConfigFile(configFilePath string, logger vlog.Printer) error {
-	if util.CanReadAccessDir(configFilePath) == nil {
-		// copy file to vertica_cluster.yaml.backup
-		configDirPath := filepath.Dir(configFilePath)
-		configFileBackup := filepath.Join(configDirPath, configBackupName)
-		logger.Info("Configuration file exists and, creating a backup", "config file", configFilePath,
-			"backup file", configFileBackup)
-		err := util.CopyFile(configFilePath, configFileBackup, configFilePerm)
-		if err != nil {
-			return err
+// getCatalogDir returns the catalog directory of a node, without the
+// Catalog subdirectory that the database reports as its catalog path,
+// such as /data/{db_name}/v_{db_name}_node0001_catalog
+func getCatalogDir(catalogPath string) string {
+	return strings.TrimSuffix(catalogPath, "/"+catalogSubdir)
+}
+
+// getNonDefaultPorts returns the NMA and HTTPS ports used for the given host.
+// A port is returned as zero if it is the default one.
+func getNonDefaultPorts(host string) (nmaPort, httpsPort int) {
+	nmaPort, httpsPort = dbOptions.NMAPort, dbOptions.HTTPSPort
+	if ports, ok := dbOptions.HostPorts[host]; ok {
+		if ports.NMAPort != 0 {
+			nmaPort = ports.NMAPort
+		}
+		if ports.HTTPSPort != 0 {
+			httpsPort = ports.HTTPSPort
 		}
 	}
-
-	return nil
+	if nmaPort == util.DefaultNMAPort {
+		nmaPort = 0
+	}
+	if httpsPort == util.DefaultHTTPPort {
+		httpsPort = 0
+	}
+	return nmaPort, httpsPort
 }
 
-// read reads information from configFilePath to a DatabaseConfig object.
-// It returns any read error encountered.
-func readConfig() (dbConfig *DatabaseConfig, err error) {
+// readConfig reads information from configFilePath to a Config object,
+// migrated to the current version. It returns any read error encountered.
+func readConfig() (config *Config, err error) {
 	configFilePath := dbOptions.ConfigPath
 
 	if configFilePath == "" {
@@ -302,29 +379,93 @@ func readConfig() (dbConfig *DatabaseConfig, err error) {
 		return nil, fmt.Errorf("fail to read configuration file, details: %w", err)
 	}
 
-	var config Config
-	err = yaml.Unmarshal(configBytes, &config)
+	return parseConfig(configBytes)
+}
+
+// validateConfigFile checks that the config file is valid, if there is one
+func validateConfigFile() error {
+	_, err := readConfig()
+	if err != nil && !isConfigFileMissing(err) {
+		return fmt.Errorf("invalid configuration file %q: %w", dbOptions.ConfigPath, err)
+	}
+	return nil
+}
+
+// isConfigFileMissing returns true if the config file could not be read
+// because there is none
+func isConfigFileMissing(err error) bool {
+	return dbOptions.ConfigPath == "" || errors.Is(err, fs.ErrNotExist)
+}
+
+// readConfigOrMakeNew reads the config file, or returns an empty Config
+// if the file does not exist yet
+func readConfigOrMakeNew() (*Config, error) {
+	config, err := readConfig()
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{Version: currentConfigFileVersion}, nil
+	}
+	return config, err
+}
+
+// parseConfig unmarshals the content of a config file. The older versions
+// of the file are migrated to the current one. The content is validated,
+// and the problems found are returned as configProblems.
+func parseConfig(configBytes []byte) (*Config, error) {
+	var configMap map[string]any
+	err := yaml.Unmarshal(configBytes, &configMap)
 	if err != nil {
 		return nil, fmt.Errorf("fail to unmarshal configuration file, details: %w", err)
 	}
+	version, err := getConfigFileVersion(configMap)
+	if err != nil {
+		return nil, err
+	}
+	configMap, err = migrateConfig(configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	// the file of the current version is validated as it is, so that the
+	// problems have its lines
+	withLines := version == currentConfigFileVersion
+	if !withLines {
+		configBytes, err = yaml.Marshal(configMap)
+		if err != nil {
+			return nil, fmt.Errorf("fail to marshal migrated configuration data, details: %w", err)
+		}
+	}
+	config, problems, err := validateConfig(configBytes, withLines)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
 
-	return &config.Database, nil
+	return config, nil
 }
 
 // write writes configuration information to configFilePath. It returns
 // any write error encountered. The viper in-built write function cannot
 // work well(the order of keys cannot be customized) so we used yaml.Marshal()
-// and os.WriteFile() to write the config file.
-func (c *DatabaseConfig) write(configFilePath string) error {
-	var config Config
-	config.Version = currentConfigFileVersion
-	config.Database = *c
+// and writeFileAtomic() to write the config file. The caller must hold the
+// lock of the file. An invalid config is not written, as the file would
+// block the commands that change the database.
+func (c *Config) write(configFilePath string) error {
+	c.Version = currentConfigFileVersion
 
-	configBytes, err := yaml.Marshal(&config)
+	configBytes, err := yaml.Marshal(c)
 	if err != nil {
 		return fmt.Errorf("fail to marshal configuration data, details: %w", err)
 	}
-	err = os.WriteFile(configFilePath, configBytes, configFilePerm)
+	_, problems, err := validateConfig(configBytes, false)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("the configuration data is invalid, the file is not written, details: %w", problems)
+	}
+	err = writeFileAtomic(configFilePath, configBytes, configFilePerm)
 	if err != nil {
 		return fmt.Errorf("fail to write configuration file, details: %w", err)
 	}
@@ -332,6 +473,83 @@ func (c *DatabaseConfig) write(configFilePath string) error {
 	return nil
 }
 
+// getDatabase returns the database with the given name, or the default
+// database if the name is empty
+func (c *Config) getDatabase(dbName string) (*DatabaseConfig, error) {
+	if dbName == "" {
+		dbName = c.DefaultDatabase
+	}
+	// without a default, a config file of one database selects it
+	if dbName == "" && len(c.Databases) == 1 {
+		return c.Databases[0], nil
+	}
+	if dbName == "" {
+		return nil, fmt.Errorf("no default database is set in the configuration file, please use --%s to select one of %v",
+			dbNameFlag, c.getDatabaseNames())
+	}
+	for _, dbConfig := range c.Databases {
+		if dbConfig.Name == dbName {
+			return dbConfig, nil
+		}
+	}
+	return nil, fmt.Errorf("database %q is not found in the configuration file, the databases in it are %v",
+		dbName, c.getDatabaseNames())
+}
+
+// getDatabaseNames returns the names of the databases in the config
+func (c *Config) getDatabaseNames() []string {
+	dbNames := make([]string, 0, len(c.Databases))
+	for _, dbConfig := range c.Databases {
+		dbNames = append(dbNames, dbConfig.Name)
+	}
+	return dbNames
+}
+
+// checkRecover checks that a database can be recovered into the config,
+// which it cannot if the config has it already and overwrite is false
+func (c *Config) checkRecover(dbName string, overwrite bool) error {
+	if !overwrite && slices.Contains(c.getDatabaseNames(), dbName) {
+		return fmt.Errorf("config file exists at %s and has the database %s. "+
+			"You can use --overwrite to overwrite this database in it", dbOptions.ConfigPath, dbName)
+	}
+	return nil
+}
+
+// setDatabase replaces the database of the same name in the config, or
+// adds it if it is new. The database becomes the default one if there is
+// no default yet.
+func (c *Config) setDatabase(dbConfig *DatabaseConfig) {
+	if c.DefaultDatabase == "" {
+		c.DefaultDatabase = dbConfig.Name
+	}
+	for i, existing := range c.Databases {
+		if existing.Name == dbConfig.Name {
+			c.Databases[i] = dbConfig
+			return
+		}
+	}
+	c.Databases = append(c.Databases, dbConfig)
+}
+
+// removeDatabase removes the database with the given name from the config.
+// If it was the default database, the first database left becomes the
+// default one.
+func (c *Config) removeDatabase(dbName string) {
+	for i, existing := range c.Databases {
+		if existing.Name == dbName {
+			c.Databases = append(c.Databases[:i], c.Databases[i+1:]...)
+			break
+		}
+	}
+	if c.DefaultDatabase != dbName {
+		return
+	}
+	c.DefaultDatabase = ""
+	if len(c.Databases) > 0 {
+		c.DefaultDatabase = c.Databases[0].Name
+	}
+}
+
 // getHosts returns host addresses of all nodes in database
 func (c *DatabaseConfig) getHosts() []string {
 	var hostList []string
@@ -343,12 +561,72 @@ func (c *DatabaseConfig) getHosts() []string {
 	return hostList
 }
 
-// getPathPrefix returns catalog, data, and depot prefixes
+// getHostPorts returns the NMA ports and HTTPS ports that are set
+// in the nodes, keyed by node address
+func (c *DatabaseConfig) getHostPorts() (nmaPorts, httpsPorts map[string]any) {
+	nmaPorts = make(map[string]any)
+	httpsPorts = make(map[string]any)
+	for _, vnode := range c.Nodes {
+		if vnode.NMAPort != 0 {
+			nmaPorts[vnode.Address] = vnode.NMAPort
+		}
+		if vnode.HTTPSPort != 0 {
+			httpsPorts[vnode.Address] = vnode.HTTPSPort
+		}
+	}
+
+	return nmaPorts, httpsPorts
+}
+
+// getPathPrefixes returns the catalog, data, and depot prefixes of the
+// first node. They are the paths of the nodes that the commands create.
+// The existing nodes use the catalog and depot prefixes of their own host,
+// and the data paths and storage locations that the database reports.
 func (c *DatabaseConfig) getPathPrefixes() (catalogPrefix string,
 	dataPrefix string, depotPrefix string) {
 	if len(c.Nodes) == 0 {
 		return "", "", ""
 	}
 
-	return c.Nodes[0].CatalogPath, c.Nodes[0].DataPath, c.Nodes[0].DepotPath
+	return c.Nodes[0].getPathPrefixes()
+}
+
+// getPathPrefixes returns the prefixes of the paths of the node, which are
+// the paths without the database and node directories
+func (n *NodeConfig) getPathPrefixes() (catalogPrefix string,
+	dataPrefix string, depotPrefix string) {
+	return getPathPrefix(n.CatalogPath), getPathPrefix(n.DataPath), getPathPrefix(n.DepotPath)
+}
+
+// getHostCatalogPrefixes returns the catalog prefix of each node, keyed by
+// node address
+func (c *DatabaseConfig) getHostCatalogPrefixes() map[string]string {
+	return c.getHostPathPrefixes(func(vnode *NodeConfig) string { return vnode.CatalogPath })
+}
+
+// getHostDepotPrefixes returns the depot prefix of each node, keyed by node
+// address
+func (c *DatabaseConfig) getHostDepotPrefixes() map[string]string {
+	return c.getHostPathPrefixes(func(vnode *NodeConfig) string { return vnode.DepotPath })
+}
+
+// getHostPathPrefixes returns the prefix of a path of each node that has the
+// path set, keyed by node address
+func (c *DatabaseConfig) getHostPathPrefixes(getPath func(vnode *NodeConfig) string) map[string]string {
+	pathPrefixes := make(map[string]string)
+	for _, vnode := range c.Nodes {
+		if pathPrefix := getPathPrefix(getPath(vnode)); pathPrefix != "" {
+			pathPrefixes[vnode.Address] = pathPrefix
+		}
+	}
+	return pathPrefixes
+}
+
+// getPathPrefix returns the prefix of a path of a node, or an empty string
+// if the path is not set
+func getPathPrefix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return util.GetPathPrefix(path)
 }
@@ -0,0 +1,271 @@
+/*
+ (c) Copyright [2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// the kinds of the differences of the live database from the config file
+const (
+	nodeAddedDiff   = "node_added"
+	nodeRemovedDiff = "node_removed"
+	addressDiff     = "address"
+	subclusterDiff  = "subcluster"
+	sandboxDiff     = "sandbox"
+	primaryDiff     = "primary"
+	pathDiff        = "path"
+	allDiffs        = "all"
+)
+
+// the separator of the storage locations of a node in a difference
+const storageLocationSep = ","
+
+var configDiffKinds = []string{nodeAddedDiff, nodeRemovedDiff, addressDiff, subclusterDiff, sandboxDiff, primaryDiff, pathDiff}
+
+// configDiff is a difference of the live database from the config file
+type configDiff struct {
+	Kind string `json:"kind"`
+	// the name of the node that differs
+	Node string `json:"node"`
+	// the key of the node in the config file that differs, empty if the
+	// whole node is added or removed
+	Field       string `json:"field,omitempty"`
+	ConfigValue string `json:"config_value,omitempty"`
+	LiveValue   string `json:"live_value,omitempty"`
+	// whether the config file has been updated to the live value
+	Reconciled bool `json:"reconciled"`
+	// why the difference was not reconciled, although it was selected
+	NotReconciledReason string `json:"not_reconciled_reason,omitempty"`
+}
+
+func (d *configDiff) String() string {
+	switch d.Kind {
+	case nodeAddedDiff:
+		return fmt.Sprintf("+ %s: in the database at %s, not in the config file", d.Node, d.LiveValue)
+	case nodeRemovedDiff:
+		return fmt.Sprintf("- %s: in the config file at %s, not in the database", d.Node, d.ConfigValue)
+	default:
+		return fmt.Sprintf("~ %s: %s %q in the config file, %q in the database", d.Node, d.Field, d.ConfigValue, d.LiveValue)
+	}
+}
+
+// nodeField is a key of a node in the config file that is compared with the
+// live database
+type nodeField struct {
+	kind  string
+	field string
+	get   func(n *NodeConfig) string
+	// set sets the field of the node of the config file to the live one
+	set func(configNode, liveNode *NodeConfig)
+}
+
+var comparedNodeFields = []nodeField{
+	{addressDiff, "address",
+		func(n *NodeConfig) string { return n.Address },
+		func(c, l *NodeConfig) { c.Address = l.Address }},
+	{subclusterDiff, "subcluster",
+		func(n *NodeConfig) string { return n.Subcluster },
+		func(c, l *NodeConfig) { c.Subcluster = l.Subcluster }},
+	{sandboxDiff, "sandbox",
+		func(n *NodeConfig) string { return n.Sandbox },
+		func(c, l *NodeConfig) { c.Sandbox = l.Sandbox }},
+	{primaryDiff, "isPrimary",
+		func(n *NodeConfig) string { return strconv.FormatBool(n.IsPrimary) },
+		func(c, l *NodeConfig) { c.IsPrimary = l.IsPrimary }},
+	{pathDiff, "catalogPath",
+		func(n *NodeConfig) string { return n.CatalogPath },
+		func(c, l *NodeConfig) { c.CatalogPath = l.CatalogPath }},
+	{pathDiff, "dataPath",
+		func(n *NodeConfig) string { return n.DataPath },
+		func(c, l *NodeConfig) { c.DataPath = l.DataPath }},
+	{pathDiff, "depotPath",
+		func(n *NodeConfig) string { return n.DepotPath },
+		func(c, l *NodeConfig) { c.DepotPath = l.DepotPath }},
+	{pathDiff, "storageLocations",
+		func(n *NodeConfig) string { return strings.Join(n.StorageLocations, storageLocationSep) },
+		func(c, l *NodeConfig) { c.StorageLocations = l.StorageLocations }},
+}
+
+// makeLiveDatabaseConfig builds the database as it runs, from the states of
+// its nodes. The data and depot paths are taken from the fetched vdb, which
+// is nil if it could not be fetched. The sandboxes are not known when the
+// node states had to be read from the catalog, and are then taken from the
+// config file, so that they are not compared.
+func makeLiveDatabaseConfig(configDB *DatabaseConfig, nodeStates []vclusterops.NodeInfo,
+	sandboxesKnown bool, vdb *vclusterops.VCoordinationDatabase) (*DatabaseConfig, error) {
+	liveDB := *configDB
+	liveDB.Nodes = nil
+
+	fetchedNodes := MakeDatabaseConfig()
+	if vdb != nil {
+		var err error
+		fetchedNodes, err = readVDBToDBConfig(vdb)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range nodeStates {
+		nodeState := &nodeStates[i]
+		liveNode := NodeConfig{
+			Name:        nodeState.Name,
+			Address:     nodeState.Address,
+			Subcluster:  nodeState.Subcluster,
+			Sandbox:     nodeState.Sandbox,
+			IsPrimary:   nodeState.IsPrimary,
+			CatalogPath: getCatalogDir(nodeState.CatalogPath),
+		}
+		liveNode.NMAPort, liveNode.HTTPSPort = getNonDefaultPorts(nodeState.Address)
+		if fetchedNode := fetchedNodes.getNode(nodeState.Name); fetchedNode != nil {
+			liveNode.DataPath = fetchedNode.DataPath
+			liveNode.DepotPath = fetchedNode.DepotPath
+			liveNode.StorageLocations = fetchedNode.StorageLocations
+		}
+		configNode := configDB.getNode(nodeState.Name)
+		if configNode != nil {
+			liveNode.ControlAddressFamily = configNode.ControlAddressFamily
+			if !sandboxesKnown {
+				liveNode.Sandbox = configNode.Sandbox
+			}
+		}
+		liveDB.Nodes = append(liveDB.Nodes, &liveNode)
+	}
+	return &liveDB, nil
+}
+
+// diffDatabaseConfigs returns the differences of the live database from the
+// database in the config file. The nodes are matched by name. A path that
+// is not known in the live database is not compared.
+func diffDatabaseConfigs(configDB, liveDB *DatabaseConfig) []*configDiff {
+	var diffs []*configDiff
+	for _, liveNode := range liveDB.Nodes {
+		configNode := configDB.getNode(liveNode.Name)
+		if configNode == nil {
+			diffs = append(diffs, &configDiff{Kind: nodeAddedDiff, Node: liveNode.Name, LiveValue: liveNode.Address})
+			continue
+		}
+		for _, f := range comparedNodeFields {
+			configValue, liveValue := f.get(configNode), f.get(liveNode)
+			if configValue == liveValue || (f.kind == pathDiff && liveValue == "") {
+				continue
+			}
+			diffs = append(diffs, &configDiff{Kind: f.kind, Node: liveNode.Name, Field: f.field,
+				ConfigValue: configValue, LiveValue: liveValue})
+		}
+	}
+	for _, configNode := range configDB.Nodes {
+		if liveDB.getNode(configNode.Name) == nil {
+			diffs = append(diffs, &configDiff{Kind: nodeRemovedDiff, Node: configNode.Name, ConfigValue: configNode.Address})
+		}
+	}
+	return diffs
+}
+
+// reconcileDatabaseConfig updates the database of the config file with the
+// differences of the given kinds, and marks them as reconciled. It returns
+// the number of reconciled differences. A node whose catalog or data path is
+// not known is not added, as the config file would be invalid without them.
+func reconcileDatabaseConfig(configDB, liveDB *DatabaseConfig, diffs []*configDiff, kinds []string) int {
+	reconciled := 0
+	for _, diff := range diffs {
+		if !isDiffKindSelected(diff.Kind, kinds) {
+			continue
+		}
+		switch diff.Kind {
+		case nodeAddedDiff:
+			liveNode := liveDB.getNode(diff.Node)
+			if liveNode.CatalogPath == "" || liveNode.DataPath == "" {
+				diff.NotReconciledReason = "the paths of the node are not known"
+				continue
+			}
+			configDB.Nodes = append(configDB.Nodes, liveNode)
+		case nodeRemovedDiff:
+			configDB.removeNode(diff.Node)
+		default:
+			for _, f := range comparedNodeFields {
+				if f.field == diff.Field {
+					f.set(configDB.getNode(diff.Node), liveDB.getNode(diff.Node))
+				}
+			}
+		}
+		diff.Reconciled = true
+		reconciled++
+	}
+	return reconciled
+}
+
+// reconcileConfigFile diffs the database of the config file, read under the
+// lock of the file, with the live database, and writes the differences of the
+// given kinds into the file. It returns the differences, so the updates that
+// other commands made to the file meanwhile are neither lost nor reported.
+func reconcileConfigFile(dbName string, liveDB *DatabaseConfig, kinds []string, logger vlog.Printer) ([]*configDiff, error) {
+	var diffs []*configDiff
+	err := updateConfigFile(logger, func(config *Config) error {
+		configDB, err := config.getDatabase(dbName)
+		if err != nil {
+			return err
+		}
+		diffs = diffDatabaseConfigs(configDB, liveDB)
+		reconcileDatabaseConfig(configDB, liveDB, diffs, kinds)
+		return nil
+	})
+	return diffs, err
+}
+
+// validateDiffKinds checks the kinds of differences given by the user
+func validateDiffKinds(kinds []string) error {
+	for _, kind := range kinds {
+		if kind != allDiffs && !isDiffKindSelected(kind, configDiffKinds) {
+			return fmt.Errorf("invalid kind of difference %q, it must be %s or one of %v", kind, allDiffs, configDiffKinds)
+		}
+	}
+	return nil
+}
+
+func isDiffKindSelected(kind string, kinds []string) bool {
+	for _, k := range kinds {
+		if k == kind || k == allDiffs {
+			return true
+		}
+	}
+	return false
+}
+
+// getNode returns the node with the given name, or nil if there is none
+func (c *DatabaseConfig) getNode(name string) *NodeConfig {
+	for _, n := range c.Nodes {
+		if n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// removeNode removes the node with the given name
+func (c *DatabaseConfig) removeNode(name string) {
+	for i, n := range c.Nodes {
+		if n.Name == name {
+			c.Nodes = append(c.Nodes[:i], c.Nodes[i+1:]...)
+			return
+		}
+	}
+}
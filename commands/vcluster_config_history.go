@@ -0,0 +1,331 @@
+/*
+ (c) Copyright [2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// the number of backups of the config file that are kept
+	configHistorySize = 10
+	// the backups of vertica_cluster.yaml are named
+	// vertica_cluster.yaml.<timestamp>.backup, in the same directory
+	configBackupSuffix    = ".backup"
+	configBackupTimestamp = "20060102T150405.000000Z"
+	// the config file is locked through vertica_cluster.yaml.lock, so that
+	// the lock is kept while the file is replaced
+	configLockSuffix       = ".lock"
+	configLockTimeout      = 30 * time.Second
+	configLockPollInterval = 100 * time.Millisecond
+)
+
+// configFileLock is an advisory lock of the config file, held by the
+// command that updates it
+type configFileLock struct {
+	file *os.File
+}
+
+// lockConfigFile locks the config file, and waits for the timeout if
+// another command holds the lock
+func lockConfigFile(configFilePath string, timeout time.Duration) (*configFileLock, error) {
+	lockPath := configFilePath + configLockSuffix
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, configFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open the lock file of the configuration file, details: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err = unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+		if err == nil {
+			return &configFileLock{file: file}, nil
+		}
+		if !errors.Is(err, unix.EWOULDBLOCK) || time.Now().After(deadline) {
+			file.Close()
+			if errors.Is(err, unix.EWOULDBLOCK) {
+				return nil, fmt.Errorf("the configuration file %s is locked by another vcluster command, "+
+					"could not lock it in %s", configFilePath, timeout)
+			}
+			return nil, fmt.Errorf("fail to lock the configuration file, details: %w", err)
+		}
+		time.Sleep(configLockPollInterval)
+	}
+}
+
+// unlock releases the lock. The lock file is kept, as another command may
+// be waiting on it.
+func (l *configFileLock) unlock() {
+	// closing the file releases the lock
+	l.file.Close()
+}
+
+// updateConfigFile reads the config file, or makes a new one, lets update
+// change it, and writes it, while the file is locked. The commands that
+// update the file at the same time then do not lose each other's changes.
+// The file is removed if no database is left in it. The previous content
+// is kept in the history of the file.
+func updateConfigFile(logger vlog.Printer, update func(config *Config) error) error {
+	return updateConfigFileImpl(logger, false, update)
+}
+
+// updateConfigFileImpl updates the config file like updateConfigFile. If
+// replaceInvalid is true, a config file that is invalid is replaced by a new
+// one instead of failing, and is kept in the history of the file.
+func updateConfigFileImpl(logger vlog.Printer, replaceInvalid bool, update func(config *Config) error) error {
+	if dbOptions.ConfigPath == "" {
+		return fmt.Errorf("configuration file path is empty")
+	}
+	lock, err := lockConfigFile(dbOptions.ConfigPath, configLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.unlock()
+
+	config, err := readConfigOrMakeNew()
+	if err != nil {
+		// a file that cannot be read is not replaced
+		var pathErr *fs.PathError
+		if !replaceInvalid || errors.As(err, &pathErr) {
+			return err
+		}
+		logger.PrintWarning("Replacing the invalid configuration file %s, details: %s", dbOptions.ConfigPath, err)
+		config = &Config{Version: currentConfigFileVersion}
+	}
+	err = update(config)
+	if err != nil {
+		return err
+	}
+
+	// if the config file exists already,
+	// create its backup before overwriting it
+	err = backupConfigFile(dbOptions.ConfigPath, logger)
+	if err != nil {
+		return err
+	}
+
+	if len(config.Databases) > 0 {
+		return config.write(dbOptions.ConfigPath)
+	}
+	// remove the old db config
+	err = os.Remove(dbOptions.ConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// writeFileAtomic writes the file through a temporary file in the same
+// directory, which is synced and then renamed to it. The file then has
+// either its old or its new content, even if vcluster is killed.
+func writeFileAtomic(filePath string, content []byte, perm fs.FileMode) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	// the temporary file is left only if the rename has failed
+	defer os.Remove(tempPath)
+
+	_, err = tempFile.Write(content)
+	if err == nil {
+		err = tempFile.Chmod(perm)
+	}
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	closeErr := tempFile.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	err = os.Rename(tempPath, filePath)
+	if err != nil {
+		return err
+	}
+
+	// sync the directory so that the rename is durable. Some file systems
+	// cannot sync a directory, which is not an error.
+	dir, err := os.Open(filepath.Dir(filePath))
+	if err == nil {
+		_ = dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// configBackup is a backup of the config file in its history
+type configBackup struct {
+	// the timestamp of the backup, which identifies it
+	ID   string
+	Time time.Time
+	Path string
+}
+
+// backupConfigFile backs up the config file before we update it, if it
+// exists. The backups are timestamped, and only the latest
+// configHistorySize of them are kept.
+func backupConfigFile(configFilePath string, logger vlog.Printer) error {
+	configBytes, err := os.ReadFile(configFilePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fail to read configuration file for its backup, details: %w", err)
+	}
+
+	backupTime := time.Now().UTC()
+	backupPath := getConfigBackupPath(configFilePath, backupTime.Format(configBackupTimestamp))
+	logger.Info("Configuration file exists and, creating a backup", "config file", configFilePath,
+		"backup file", backupPath)
+	err = writeFileAtomic(backupPath, configBytes, configFilePerm)
+	if err != nil {
+		return fmt.Errorf("fail to create backup of configuration file at %s, details: %w", backupPath, err)
+	}
+
+	return pruneConfigHistory(configFilePath, configHistorySize, logger)
+}
+
+// pruneConfigHistory removes the oldest backups of the config file, so that
+// at most historySize of them are kept
+func pruneConfigHistory(configFilePath string, historySize int, logger vlog.Printer) error {
+	backups, err := getConfigHistory(configFilePath)
+	if err != nil {
+		return err
+	}
+	for i := historySize; i < len(backups); i++ {
+		logger.Info("Removing old backup of configuration file", "backup file", backups[i].Path)
+		err = os.Remove(backups[i].Path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("fail to remove old backup of configuration file, details: %w", err)
+		}
+	}
+	return nil
+}
+
+func getConfigBackupPath(configFilePath, id string) string {
+	return configFilePath + "." + id + configBackupSuffix
+}
+
+// getConfigHistory returns the backups of the config file, the latest first
+func getConfigHistory(configFilePath string) ([]*configBackup, error) {
+	entries, err := os.ReadDir(filepath.Dir(configFilePath))
+	if err != nil {
+		return nil, fmt.Errorf("fail to read the directory of the configuration file, details: %w", err)
+	}
+	prefix := filepath.Base(configFilePath) + "."
+	var backups []*configBackup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, configBackupSuffix) {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(name, prefix), configBackupSuffix)
+		backupTime, err := time.Parse(configBackupTimestamp, id)
+		if err != nil {
+			// not a backup of the history, such as the .backup file of older vclusters
+			continue
+		}
+		backups = append(backups, &configBackup{ID: id, Time: backupTime, Path: getConfigBackupPath(configFilePath, id)})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// readConfigBackup reads a backup of the config file, migrated to the
+// current version
+func readConfigBackup(backup *configBackup) (*Config, error) {
+	configBytes, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read backup of configuration file, details: %w", err)
+	}
+	return parseConfig(configBytes)
+}
+
+// rollbackConfigFile restores the config file to the backup with the given
+// ID, or to the latest backup if the ID is empty. The current content is
+// backed up first, so the rollback can be undone by another rollback.
+// It returns the restored backup.
+func rollbackConfigFile(id string, logger vlog.Printer) (*configBackup, error) {
+	configFilePath := dbOptions.ConfigPath
+	if configFilePath == "" {
+		return nil, fmt.Errorf("configuration file path is empty")
+	}
+	lock, err := lockConfigFile(configFilePath, configLockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.unlock()
+
+	backups, err := getConfigHistory(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	backup, err := findConfigBackup(backups, id)
+	if err != nil {
+		return nil, err
+	}
+	configBytes, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read backup of configuration file, details: %w", err)
+	}
+	// an invalid backup is not restored
+	_, err = parseConfig(configBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup %s of configuration file: %w", backup.ID, err)
+	}
+
+	err = backupConfigFile(configFilePath, logger)
+	if err != nil {
+		return nil, err
+	}
+	err = writeFileAtomic(configFilePath, configBytes, configFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("fail to write configuration file, details: %w", err)
+	}
+	return backup, nil
+}
+
+// findConfigBackup returns the backup with the given ID, or the latest
+// one if the ID is empty
+func findConfigBackup(backups []*configBackup, id string) (*configBackup, error) {
+	if len(backups) == 0 {
+		return nil, fmt.Errorf("the configuration file %s has no backup", dbOptions.ConfigPath)
+	}
+	if id == "" {
+		return backups[0], nil
+	}
+	for _, backup := range backups {
+		if backup.ID == id {
+			return backup, nil
+		}
+	}
+	return nil, fmt.Errorf("the configuration file %s has no backup %q", dbOptions.ConfigPath, id)
+}
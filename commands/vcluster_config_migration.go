@@ -0,0 +1,141 @@
+/*
+ (c) Copyright [2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// the version of the config files written before the version was checked
+const firstConfigFileVersion = "1.0"
+
+// configMigration migrates the content of a config file from a version to
+// the next one
+type configMigration struct {
+	fromVersion string
+	toVersion   string
+	migrate     func(configMap map[string]any) (map[string]any, error)
+}
+
+// configMigrations are applied in order to a config file of an older
+// version, until it has the current version
+var configMigrations = []configMigration{
+	{fromVersion: "1.0", toVersion: "2.0", migrate: migrateConfigFrom1To2},
+	{fromVersion: "2.0", toVersion: "3.0", migrate: migrateConfigFrom2To3},
+}
+
+// migrateConfig migrates the content of a config file to the current version
+func migrateConfig(configMap map[string]any) (map[string]any, error) {
+	// an empty config file has no database
+	if len(configMap) == 0 {
+		return map[string]any{configFileVersionKey: currentConfigFileVersion}, nil
+	}
+
+	version, err := getConfigFileVersion(configMap)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range configMigrations {
+		if version != migration.fromVersion {
+			continue
+		}
+		configMap, err = migration.migrate(configMap)
+		if err != nil {
+			return nil, fmt.Errorf("fail to migrate configuration file from version %s to %s, details: %w",
+				migration.fromVersion, migration.toVersion, err)
+		}
+		version = migration.toVersion
+		configMap[configFileVersionKey] = version
+	}
+
+	if version != currentConfigFileVersion {
+		return nil, fmt.Errorf("unsupported configuration file version %q, the supported version is %q",
+			version, currentConfigFileVersion)
+	}
+	return configMap, nil
+}
+
+// getConfigFileVersion returns the version of a config file. The version can
+// be written as a number, and the files without a version have the first one.
+func getConfigFileVersion(configMap map[string]any) (string, error) {
+	switch version := configMap[configFileVersionKey].(type) {
+	case nil:
+		return firstConfigFileVersion, nil
+	case string:
+		return version, nil
+	case int:
+		return strconv.FormatFloat(float64(version), 'f', 1, 64), nil
+	case float64:
+		return strconv.FormatFloat(version, 'f', 1, 64), nil
+	default:
+		return "", fmt.Errorf("invalid configuration file version %v", version)
+	}
+}
+
+// migrateConfigFrom1To2 moves the only database of a config file of version
+// 1.0 to the list of databases, and makes it the default one
+func migrateConfigFrom1To2(configMap map[string]any) (map[string]any, error) {
+	dbConfigMap := make(map[string]any)
+	for key, value := range configMap {
+		if key != configFileVersionKey {
+			dbConfigMap[key] = value
+		}
+	}
+
+	migratedMap := map[string]any{"databases": []any{}}
+	if len(dbConfigMap) > 0 {
+		migratedMap["databases"] = []any{dbConfigMap}
+		migratedMap["defaultDatabase"] = dbConfigMap["dbName"]
+	}
+	return migratedMap, nil
+}
+
+// migrateConfigFrom2To3 replaces the path prefixes of the nodes of a config
+// file of version 2.0 with their complete paths. The nodes of an Enterprise
+// database are all primary. Whether the nodes of an Eon database are
+// primary is not known until the file is written again, such as by
+// manage_config recover.
+func migrateConfigFrom2To3(configMap map[string]any) (map[string]any, error) {
+	databases, _ := configMap["databases"].([]any)
+	for _, database := range databases {
+		dbConfigMap, ok := database.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid database %v", database)
+		}
+		dbName, _ := dbConfigMap["dbName"].(string)
+		isEon, _ := dbConfigMap["eonMode"].(bool)
+		nodes, _ := dbConfigMap["nodes"].([]any)
+		for _, node := range nodes {
+			nodeConfigMap, ok := node.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid node %v of database %s", node, dbName)
+			}
+			nodeName, _ := nodeConfigMap["name"].(string)
+			for key, suffix := range map[string]string{"catalogPath": "catalog", "dataPath": "data", "depotPath": "depot"} {
+				prefix, _ := nodeConfigMap[key].(string)
+				if prefix != "" {
+					nodeConfigMap[key] = filepath.Join(prefix, dbName, fmt.Sprintf("%s_%s", nodeName, suffix))
+				}
+			}
+			if !isEon {
+				nodeConfigMap["isPrimary"] = true
+			}
+		}
+	}
+	return configMap, nil
+}
@@ -0,0 +1,626 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestReadVDBToDBConfigPaths(t *testing.T) {
+	vdb := vclusterops.VCoordinationDatabase{
+		Name:      "test_db",
+		IsEon:     true,
+		DepotSize: "50%",
+		HostList:  []string{"192.168.1.101", "192.168.1.102"},
+		HostNodeMap: map[string]*vclusterops.VCoordinationNode{
+			"192.168.1.101": {Name: "v_test_db_node0001", Address: "192.168.1.101", Subcluster: "sc1", IsPrimary: true,
+				CatalogPath:      "/catalog/test_db/v_test_db_node0001_catalog/Catalog",
+				StorageLocations: []string{"/data/test_db/v_test_db_node0001_data", "/ssd/test_db"},
+				DepotPath:        "/depot/test_db/v_test_db_node0001_depot", ControlAddressFamily: "ipv4"},
+			"192.168.1.102": {Name: "v_test_db_node0002", Address: "192.168.1.102", Subcluster: "sc2", Sandbox: "sand",
+				CatalogPath:          "/data2/test_db/v_test_db_node0002_catalog",
+				StorageLocations:     []string{"/data2/test_db/v_test_db_node0002_data"},
+				UserStorageLocations: []string{"/user/test_db"},
+				DepotPath:            "/data2/test_db/v_test_db_node0002_depot"},
+		},
+	}
+
+	dbConfig, err := readVDBToDBConfig(&vdb)
+	assert.NoError(t, err)
+	assert.Equal(t, "50%", dbConfig.DepotSize)
+	assert.Equal(t, &NodeConfig{Name: "v_test_db_node0001", Address: "192.168.1.101", Subcluster: "sc1", IsPrimary: true,
+		ControlAddressFamily: "ipv4", CatalogPath: "/catalog/test_db/v_test_db_node0001_catalog",
+		DataPath: "/data/test_db/v_test_db_node0001_data", DepotPath: "/depot/test_db/v_test_db_node0001_depot",
+		StorageLocations: []string{"/ssd/test_db"}}, dbConfig.Nodes[0])
+	assert.Equal(t, &NodeConfig{Name: "v_test_db_node0002", Address: "192.168.1.102", Subcluster: "sc2", Sandbox: "sand",
+		CatalogPath: "/data2/test_db/v_test_db_node0002_catalog", DataPath: "/data2/test_db/v_test_db_node0002_data",
+		DepotPath: "/data2/test_db/v_test_db_node0002_depot", StorageLocations: []string{"/user/test_db"}}, dbConfig.Nodes[1])
+
+	// the catalog of each node is looked for in its own path
+	assert.Equal(t, map[string]string{"192.168.1.101": "/catalog", "192.168.1.102": "/data2"},
+		dbConfig.getHostCatalogPrefixes())
+	// and so is its depot, if the database does not report it
+	assert.Equal(t, map[string]string{"192.168.1.101": "/depot", "192.168.1.102": "/data2"},
+		dbConfig.getHostDepotPrefixes())
+	catalogPrefix, dataPrefix, depotPrefix := dbConfig.getPathPrefixes()
+	assert.Equal(t, []string{"/catalog", "/data", "/depot"}, []string{catalogPrefix, dataPrefix, depotPrefix})
+}
+
+func TestReadVDBToDBConfigPorts(t *testing.T) {
+	savedOptions := dbOptions
+	defer func() { dbOptions = savedOptions }()
+
+	vdb := vclusterops.VCoordinationDatabase{
+		Name:          "test_db",
+		CatalogPrefix: "/data",
+		DataPrefix:    "/data",
+		HostList:      []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"},
+		HostNodeMap: map[string]*vclusterops.VCoordinationNode{
+			"192.168.1.101": {Name: "v_test_db_node0001", Address: "192.168.1.101"},
+			"192.168.1.102": {Name: "v_test_db_node0002", Address: "192.168.1.102"},
+			"192.168.1.103": {Name: "v_test_db_node0003", Address: "192.168.1.103"},
+		},
+	}
+	dbOptions.NMAPort = util.DefaultNMAPort
+	dbOptions.HTTPSPort = 9443
+	dbOptions.HostPorts = map[string]vclusterops.HostPorts{
+		"192.168.1.101": {NMAPort: 6554},
+		"192.168.1.102": {NMAPort: 7554, HTTPSPort: util.DefaultHTTPPort},
+	}
+
+	dbConfig, err := readVDBToDBConfig(&vdb)
+	assert.NoError(t, err)
+	assert.Len(t, dbConfig.Nodes, 3)
+	// default ports are not stored
+	assert.Equal(t, 6554, dbConfig.Nodes[0].NMAPort)
+	assert.Equal(t, 9443, dbConfig.Nodes[0].HTTPSPort)
+	assert.Equal(t, 7554, dbConfig.Nodes[1].NMAPort)
+	assert.Equal(t, 0, dbConfig.Nodes[1].HTTPSPort)
+	assert.Equal(t, 0, dbConfig.Nodes[2].NMAPort)
+	assert.Equal(t, 9443, dbConfig.Nodes[2].HTTPSPort)
+
+	// the ports can be read back by host
+	nmaPorts, httpsPorts := dbConfig.getHostPorts()
+	assert.Equal(t, map[string]any{"192.168.1.101": 6554, "192.168.1.102": 7554}, nmaPorts)
+	assert.Equal(t, map[string]any{"192.168.1.101": 9443, "192.168.1.103": 9443}, httpsPorts)
+}
+
+const configOfVersion1 = `configFileVersion: "1.0"
+dbName: practice_db
+nodes:
+    - name: v_practice_db_node0001
+      address: 192.168.1.101
+      subcluster: default_subcluster
+      catalogPath: /data
+      dataPath: /data
+      depotPath: /depot
+      nmaPort: 6554
+eonMode: true
+communalStorageLocation: s3://bucket/practice_db
+ipv6: false
+`
+
+func TestMigrateConfig(t *testing.T) {
+	config, err := parseConfig([]byte(configOfVersion1))
+	assert.NoError(t, err)
+	assert.Equal(t, currentConfigFileVersion, config.Version)
+	assert.Equal(t, "practice_db", config.DefaultDatabase)
+	assert.Len(t, config.Databases, 1)
+	dbConfig := config.Databases[0]
+	assert.Equal(t, "practice_db", dbConfig.Name)
+	assert.True(t, dbConfig.IsEon)
+	assert.Equal(t, "s3://bucket/practice_db", dbConfig.CommunalStorageLocation)
+	// the path prefixes are replaced by the complete paths
+	assert.Equal(t, []*NodeConfig{{Name: "v_practice_db_node0001", Address: "192.168.1.101",
+		Subcluster: "default_subcluster", CatalogPath: "/data/practice_db/v_practice_db_node0001_catalog",
+		DataPath: "/data/practice_db/v_practice_db_node0001_data", DepotPath: "/depot/practice_db/v_practice_db_node0001_depot",
+		NMAPort: 6554}}, dbConfig.Nodes)
+	catalogPrefix, dataPrefix, depotPrefix := dbConfig.getPathPrefixes()
+	assert.Equal(t, []string{"/data", "/data", "/depot"}, []string{catalogPrefix, dataPrefix, depotPrefix})
+
+	// the version can be a number, or be missing in the oldest files
+	const nodesOfVersion1 = "nodes:\n  - name: v_test_db_node0001\n    address: 192.168.1.101\n" +
+		"    catalogPath: /data\n    dataPath: /data\n"
+	config, err = parseConfig([]byte("configFileVersion: 1.0\ndbName: test_db\n" + nodesOfVersion1))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test_db"}, config.getDatabaseNames())
+	config, err = parseConfig([]byte("dbName: test_db\n" + nodesOfVersion1))
+	assert.NoError(t, err)
+	assert.Equal(t, "test_db", config.DefaultDatabase)
+
+	// the nodes of an Enterprise database are primary
+	config, err = parseConfig([]byte(`configFileVersion: "2.0"
+defaultDatabase: db1
+databases:
+  - dbName: db1
+    nodes:
+      - name: v_db1_node0001
+        address: 192.168.1.101
+        catalogPath: /data
+        dataPath: /data
+`))
+	assert.NoError(t, err)
+	assert.True(t, config.Databases[0].Nodes[0].IsPrimary)
+	assert.Equal(t, "/data/db1/v_db1_node0001_catalog", config.Databases[0].Nodes[0].CatalogPath)
+	assert.Empty(t, config.Databases[0].Nodes[0].DepotPath)
+
+	// a config file of the current version is not changed
+	config, err = parseConfig([]byte(`configFileVersion: "3.0"
+defaultDatabase: db2
+databases:
+  - dbName: db1
+    nodes:
+      - {name: v_db1_node0001, address: 192.168.1.101, catalogPath: /data/db1/c, dataPath: /data/db1/d}
+  - dbName: db2
+    nodes:
+      - {name: v_db2_node0001, address: 192.168.1.101, catalogPath: /data/db2/c, dataPath: /data/db2/d}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "db2", config.DefaultDatabase)
+	assert.Equal(t, []string{"db1", "db2"}, config.getDatabaseNames())
+
+	// an empty config file has no database
+	config, err = parseConfig([]byte{})
+	assert.NoError(t, err)
+	assert.Empty(t, config.Databases)
+
+	_, err = parseConfig([]byte("configFileVersion: \"9.0\"\n"))
+	assert.ErrorContains(t, err, `unsupported configuration file version "9.0"`)
+}
+
+func TestConfigDatabases(t *testing.T) {
+	config := Config{Version: currentConfigFileVersion}
+	_, err := config.getDatabase("")
+	assert.ErrorContains(t, err, "no default database")
+
+	// the first database becomes the default one
+	config.setDatabase(&DatabaseConfig{Name: "db1"})
+	config.setDatabase(&DatabaseConfig{Name: "db2"})
+	assert.Equal(t, "db1", config.DefaultDatabase)
+	dbConfig, err := config.getDatabase("")
+	assert.NoError(t, err)
+	assert.Equal(t, "db1", dbConfig.Name)
+	dbConfig, err = config.getDatabase("db2")
+	assert.NoError(t, err)
+	assert.Equal(t, "db2", dbConfig.Name)
+	_, err = config.getDatabase("db3")
+	assert.ErrorContains(t, err, `database "db3" is not found in the configuration file`)
+
+	// a database of the same name is replaced
+	config.setDatabase(&DatabaseConfig{Name: "db2", IsEon: true})
+	assert.Equal(t, []string{"db1", "db2"}, config.getDatabaseNames())
+	assert.True(t, config.Databases[1].IsEon)
+
+	// removing the default database makes the next one the default
+	config.removeDatabase("db1")
+	assert.Equal(t, "db2", config.DefaultDatabase)
+	config.removeDatabase("db2")
+	assert.Empty(t, config.DefaultDatabase)
+	assert.Empty(t, config.Databases)
+}
+
+func TestWriteAndRemoveConfig(t *testing.T) {
+	savedOptions := dbOptions
+	defer func() { dbOptions = savedOptions }()
+
+	configDir := t.TempDir()
+	dbOptions.ConfigPath = filepath.Join(configDir, defConfigFileName)
+	err := os.WriteFile(dbOptions.ConfigPath, []byte(configOfVersion1), configFilePerm)
+	assert.NoError(t, err)
+
+	// writing a new database keeps the one in the migrated file
+	vdb := vclusterops.VCoordinationDatabase{
+		Name:          "test_db",
+		CatalogPrefix: "/data",
+		DataPrefix:    "/data",
+		HostList:      []string{"192.168.1.102"},
+		HostNodeMap: map[string]*vclusterops.VCoordinationNode{
+			"192.168.1.102": {Name: "v_test_db_node0001", Address: "192.168.1.102",
+				CatalogPath:      "/data/test_db/v_test_db_node0001_catalog",
+				StorageLocations: []string{"/data/test_db/v_test_db_node0001_data"}},
+		},
+	}
+	err = writeConfig(&vdb, vlog.Printer{})
+	assert.NoError(t, err)
+	config, err := readConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "practice_db", config.DefaultDatabase)
+	assert.Equal(t, []string{"practice_db", "test_db"}, config.getDatabaseNames())
+	backups, err := getConfigHistory(dbOptions.ConfigPath)
+	assert.NoError(t, err)
+	assert.Len(t, backups, 1)
+	backupBytes, err := os.ReadFile(backups[0].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, configOfVersion1, string(backupBytes))
+
+	// dropping a database removes it from the file, and the file is removed
+	// with the last database
+	dbOptions.DBName = "practice_db"
+	err = removeConfig(vlog.Printer{})
+	assert.NoError(t, err)
+	config, err = readConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "test_db", config.DefaultDatabase)
+	assert.Equal(t, []string{"test_db"}, config.getDatabaseNames())
+	dbOptions.DBName = "test_db"
+	err = removeConfig(vlog.Printer{})
+	assert.NoError(t, err)
+	assert.NoFileExists(t, dbOptions.ConfigPath)
+
+	// a database that would make the file invalid is not written
+	vdb.HostNodeMap["192.168.1.102"].StorageLocations = nil
+	err = writeConfig(&vdb, vlog.Printer{})
+	assert.ErrorContains(t, err, "the configuration data is invalid")
+	assert.ErrorContains(t, err, "dataPath is required")
+	assert.NoFileExists(t, dbOptions.ConfigPath)
+}
+
+func TestWriteRecoveredConfig(t *testing.T) {
+	savedOptions := dbOptions
+	defer func() { dbOptions = savedOptions }()
+	dbOptions.ConfigPath = filepath.Join(t.TempDir(), defConfigFileName)
+
+	vdb := vclusterops.VCoordinationDatabase{
+		Name:     "db2",
+		HostList: []string{"192.168.1.101"},
+		HostNodeMap: map[string]*vclusterops.VCoordinationNode{
+			"192.168.1.101": {Name: "v_db2_node0001", Address: "192.168.1.101", Subcluster: "default_subcluster",
+				IsPrimary: true, CatalogPath: "/data/db2/v_db2_node0001_catalog/Catalog",
+				StorageLocations: []string{"/data/db2/v_db2_node0001_data"}},
+		},
+	}
+
+	// a database is recovered into the file of another one
+	assert.NoError(t, writeDatabaseConfig(makeTestDatabaseConfig("db1"), vlog.Printer{}))
+	assert.NoError(t, writeRecoveredConfig(&vdb, false, vlog.Printer{}))
+	config, err := readConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db1", "db2"}, config.getDatabaseNames())
+
+	// but it is only replaced with --overwrite
+	assert.ErrorContains(t, config.checkRecover("db2", false), "has the database db2")
+	assert.NoError(t, config.checkRecover("db2", true))
+	assert.ErrorContains(t, writeRecoveredConfig(&vdb, false, vlog.Printer{}), "use --overwrite")
+	assert.NoError(t, writeRecoveredConfig(&vdb, true, vlog.Printer{}))
+
+	// an invalid file is replaced with --overwrite, and kept in the history
+	invalidConfig := []byte("dbName: db1\nfoo: bar\n")
+	assert.NoError(t, os.WriteFile(dbOptions.ConfigPath, invalidConfig, configFilePerm))
+	assert.Error(t, writeRecoveredConfig(&vdb, false, vlog.Printer{}))
+	assert.NoError(t, writeRecoveredConfig(&vdb, true, vlog.Printer{}))
+	config, err = readConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db2"}, config.getDatabaseNames())
+	backups, err := getConfigHistory(dbOptions.ConfigPath)
+	assert.NoError(t, err)
+	backupBytes, err := os.ReadFile(backups[0].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, invalidConfig, backupBytes)
+}
+
+func TestValidateConfig(t *testing.T) {
+	_, err := parseConfig([]byte(`configFileVersion: "3.0"
+defaultDatabase: db3
+databases:
+  - dbName: db1
+    eonMode: true
+    depotSize: 50%
+    replicas: 3
+    nodes:
+      - name: v_db1_node0001
+        address: 192.168.1.101
+        subcluster: sc-1
+        catalogPath: data/db1/v_db1_node0001_catalog
+        dataPath: /data/db1/v_db1_node0001_data
+        depotPath: /depot/db1/v_db1_node0001_depot
+        storageLocations: [/ssd, ssd2]
+      - name: v_db1_node0001
+        address: 192.168.1.101
+        dataPath: /data/db1/v_db1_node0002_data
+        nmaPort: 70000
+  - dbName: db1
+    nodes: []
+    ipv6: maybe
+`))
+	var problems configProblems
+	assert.ErrorAs(t, err, &problems)
+	messages := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		messages = append(messages, problem.String())
+	}
+	// the problems are sorted by line
+	assert.Equal(t, []string{
+		`line 2: defaultDatabase: database "db3" is not defined in databases`,
+		`line 5: databases[0].eonMode: an Eon database requires communalStorageLocation`,
+		`line 6: databases[0].depotSize: depotSize is only valid for an Eon database with communalStorageLocation`,
+		`line 7: databases[0]: unknown key "replicas"`,
+		`line 11: databases[0].nodes[0].subcluster: invalid character in subcluster name: -`,
+		`line 12: databases[0].nodes[0].catalogPath: catalogPath "data/db1/v_db1_node0001_catalog" must be an absolute path`,
+		`line 14: databases[0].nodes[0].depotPath: depotPath is only valid for an Eon database with communalStorageLocation`,
+		`line 15: databases[0].nodes[0].storageLocations[1]: storage location "ssd2" must be an absolute path`,
+		`line 16: databases[0].nodes[1].catalogPath: catalogPath is required`,
+		`line 16: databases[0].nodes[1].name: node name "v_db1_node0001" is used by another node`,
+		`line 17: databases[0].nodes[1].address: address "192.168.1.101" is used by another node`,
+		`line 19: databases[0].nodes[1].nmaPort: nmaPort 70000 is out of range, must be between 1 and 65535`,
+		`line 20: databases[1]: database "db1" is defined more than once`,
+		"line 21: databases[1].nodes: database \"db1\" has no nodes",
+		"line 22: configuration file: cannot unmarshal !!str `maybe` into bool",
+	}, messages)
+
+	// the problems of a migrated config file have no lines
+	_, err = parseConfig([]byte("configFileVersion: \"1.0\"\ndbName: test_db\nfoo: bar\n"))
+	assert.ErrorAs(t, err, &problems)
+	assert.Equal(t, `databases[0]: unknown key "foo"`, problems[0].String())
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	savedOptions := dbOptions
+	defer func() { dbOptions = savedOptions }()
+
+	// a missing config file is not an error
+	dbOptions.ConfigPath = filepath.Join(t.TempDir(), defConfigFileName)
+	assert.NoError(t, validateConfigFile())
+	assert.NoError(t, loadConfigToViper(true))
+
+	err := os.WriteFile(dbOptions.ConfigPath, []byte("dbName: test_db\n"), configFilePerm)
+	assert.NoError(t, err)
+	err = validateConfigFile()
+	assert.ErrorContains(t, err, "invalid configuration file")
+	assert.ErrorContains(t, err, `database "test_db" has no nodes`)
+	assert.ErrorContains(t, loadConfigToViper(true), "invalid configuration file")
+	// an invalid config file is ignored by the commands that do not change
+	// the database
+	assert.NoError(t, loadConfigToViper(false))
+}
+
+func TestDiffDatabaseConfigs(t *testing.T) {
+	configDB := &DatabaseConfig{Name: "test_db", Nodes: []*NodeConfig{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101", Subcluster: "sc1", IsPrimary: true,
+			CatalogPath: "/data/test_db/v_test_db_node0001_catalog", DataPath: "/data/test_db/v_test_db_node0001_data"},
+		{Name: "v_test_db_node0002", Address: "192.168.1.102", Subcluster: "sc1", IsPrimary: true,
+			CatalogPath: "/data/test_db/v_test_db_node0002_catalog", DataPath: "/data/test_db/v_test_db_node0002_data"},
+	}}
+	liveDB := &DatabaseConfig{Name: "test_db", Nodes: []*NodeConfig{
+		{Name: "v_test_db_node0001", Address: "192.168.1.111", Subcluster: "sc2", Sandbox: "sand", IsPrimary: true,
+			CatalogPath: "/data/test_db/v_test_db_node0001_catalog"},
+		{Name: "v_test_db_node0003", Address: "192.168.1.103", Subcluster: "sc1",
+			CatalogPath: "/data/test_db/v_test_db_node0003_catalog", DataPath: "/data/test_db/v_test_db_node0003_data"},
+	}}
+
+	// the data path of node0001 is not known in the live database
+	diffs := diffDatabaseConfigs(configDB, liveDB)
+	assert.Equal(t, []*configDiff{
+		{Kind: addressDiff, Node: "v_test_db_node0001", Field: "address", ConfigValue: "192.168.1.101", LiveValue: "192.168.1.111"},
+		{Kind: subclusterDiff, Node: "v_test_db_node0001", Field: "subcluster", ConfigValue: "sc1", LiveValue: "sc2"},
+		{Kind: sandboxDiff, Node: "v_test_db_node0001", Field: "sandbox", LiveValue: "sand"},
+		{Kind: nodeAddedDiff, Node: "v_test_db_node0003", LiveValue: "192.168.1.103"},
+		{Kind: nodeRemovedDiff, Node: "v_test_db_node0002", ConfigValue: "192.168.1.102"},
+	}, diffs)
+	assert.Equal(t, `~ v_test_db_node0001: subcluster "sc1" in the config file, "sc2" in the database`, diffs[1].String())
+	assert.Equal(t, "+ v_test_db_node0003: in the database at 192.168.1.103, not in the config file", diffs[3].String())
+
+	// only the chosen kinds are reconciled
+	assert.Equal(t, 2, reconcileDatabaseConfig(configDB, liveDB, diffs, []string{addressDiff, nodeAddedDiff}))
+	assert.Equal(t, []bool{true, false, false, true, false},
+		[]bool{diffs[0].Reconciled, diffs[1].Reconciled, diffs[2].Reconciled, diffs[3].Reconciled, diffs[4].Reconciled})
+	assert.Len(t, configDB.Nodes, 3)
+	assert.Equal(t, "192.168.1.111", configDB.Nodes[0].Address)
+	assert.Equal(t, "sc1", configDB.Nodes[0].Subcluster)
+	assert.Equal(t, "/data/test_db/v_test_db_node0001_data", configDB.Nodes[0].DataPath)
+	assert.Equal(t, liveDB.Nodes[1], configDB.Nodes[2])
+
+	// all kinds are reconciled, and then nothing differs
+	diffs = diffDatabaseConfigs(configDB, liveDB)
+	assert.Equal(t, 3, reconcileDatabaseConfig(configDB, liveDB, diffs, []string{allDiffs}))
+	assert.Empty(t, diffDatabaseConfigs(configDB, liveDB))
+	assert.Nil(t, configDB.getNode("v_test_db_node0002"))
+
+	assert.NoError(t, validateDiffKinds([]string{pathDiff, allDiffs}))
+	assert.ErrorContains(t, validateDiffKinds([]string{"paths"}), `invalid kind of difference "paths"`)
+}
+
+func TestMakeLiveDatabaseConfig(t *testing.T) {
+	configDB := &DatabaseConfig{Name: "test_db", IsEon: true, Nodes: []*NodeConfig{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101", Subcluster: "sc1", Sandbox: "sand", IsPrimary: true,
+			ControlAddressFamily: "ipv4", CatalogPath: "/data/test_db/v_test_db_node0001_catalog"},
+	}}
+	nodeStates := []vclusterops.NodeInfo{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101", Subcluster: "sc1", IsPrimary: true,
+			CatalogPath: "/data/test_db/v_test_db_node0001_catalog/Catalog"},
+		{Name: "v_test_db_node0002", Address: "192.168.1.102", Subcluster: "sc2", Sandbox: "sand2",
+			CatalogPath: "/data/test_db/v_test_db_node0002_catalog/Catalog"},
+	}
+	vdb := vclusterops.VCoordinationDatabase{
+		Name:     "test_db",
+		IsEon:    true,
+		HostList: []string{"192.168.1.101"},
+		HostNodeMap: map[string]*vclusterops.VCoordinationNode{
+			"192.168.1.101": {Name: "v_test_db_node0001", Address: "192.168.1.101", Subcluster: "sc1", IsPrimary: true,
+				CatalogPath:      "/data/test_db/v_test_db_node0001_catalog/Catalog",
+				StorageLocations: []string{"/data/test_db/v_test_db_node0001_data"},
+				DepotPath:        "/depot/test_db/v_test_db_node0001_depot"},
+		},
+	}
+
+	// the paths come from the fetched database, and the sandboxes from the
+	// node states
+	liveDB, err := makeLiveDatabaseConfig(configDB, nodeStates, true, &vdb)
+	assert.NoError(t, err)
+	assert.True(t, liveDB.IsEon)
+	assert.Len(t, configDB.Nodes, 1)
+	assert.Equal(t, &NodeConfig{Name: "v_test_db_node0001", Address: "192.168.1.101", Subcluster: "sc1", IsPrimary: true,
+		ControlAddressFamily: "ipv4", CatalogPath: "/data/test_db/v_test_db_node0001_catalog",
+		DataPath: "/data/test_db/v_test_db_node0001_data", DepotPath: "/depot/test_db/v_test_db_node0001_depot"}, liveDB.Nodes[0])
+	assert.Equal(t, &NodeConfig{Name: "v_test_db_node0002", Address: "192.168.1.102", Subcluster: "sc2", Sandbox: "sand2",
+		CatalogPath: "/data/test_db/v_test_db_node0002_catalog"}, liveDB.Nodes[1])
+
+	// the sandboxes are kept from the config file when they are not known,
+	// and the paths that are not fetched are not compared
+	nodeStates[1].Sandbox = ""
+	liveDB, err = makeLiveDatabaseConfig(configDB, nodeStates, false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "sand", liveDB.Nodes[0].Sandbox)
+	diffs := diffDatabaseConfigs(configDB, liveDB)
+	assert.Equal(t, []*configDiff{{Kind: nodeAddedDiff, Node: "v_test_db_node0002", LiveValue: "192.168.1.102"}}, diffs)
+
+	// and a node whose data path is not known is not added
+	assert.Equal(t, 0, reconcileDatabaseConfig(configDB, liveDB, diffs, []string{allDiffs}))
+	assert.False(t, diffs[0].Reconciled)
+	assert.Equal(t, "the paths of the node are not known", diffs[0].NotReconciledReason)
+	assert.Len(t, configDB.Nodes, 1)
+}
+
+func makeTestDatabaseConfig(dbName string) *DatabaseConfig {
+	nodeName := "v_" + dbName + "_node0001"
+	return &DatabaseConfig{Name: dbName, Nodes: []*NodeConfig{
+		{Name: nodeName, Address: "192.168.1.101", Subcluster: "default_subcluster", IsPrimary: true,
+			CatalogPath: "/data/" + dbName + "/" + nodeName + "_catalog", DataPath: "/data/" + dbName + "/" + nodeName + "_data"},
+	}}
+}
+
+func TestReconcileConfigFile(t *testing.T) {
+	savedOptions := dbOptions
+	defer func() { dbOptions = savedOptions }()
+	dbOptions.ConfigPath = filepath.Join(t.TempDir(), defConfigFileName)
+
+	assert.NoError(t, writeDatabaseConfig(makeTestDatabaseConfig("db1"), vlog.Printer{}))
+	liveDB := makeTestDatabaseConfig("db1")
+	liveDB.Nodes = append(liveDB.Nodes, &NodeConfig{Name: "v_db1_node0002", Address: "192.168.1.102",
+		Subcluster: "default_subcluster", IsPrimary: true, CatalogPath: "/data/db1/v_db1_node0002_catalog",
+		DataPath: "/data/db1/v_db1_node0002_data"})
+	// another command updates the file after the live database is fetched
+	assert.NoError(t, writeDatabaseConfig(makeTestDatabaseConfig("db2"), vlog.Printer{}))
+
+	diffs, err := reconcileConfigFile("db1", liveDB, []string{allDiffs}, vlog.Printer{})
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 1)
+	assert.True(t, diffs[0].Reconciled)
+	config, err := readConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db1", "db2"}, config.getDatabaseNames())
+	db1, err := config.getDatabase("db1")
+	assert.NoError(t, err)
+	assert.Len(t, db1.Nodes, 2)
+
+	_, err = reconcileConfigFile("db3", liveDB, []string{allDiffs}, vlog.Printer{})
+	assert.Error(t, err)
+}
+
+func TestConfigHistory(t *testing.T) {
+	savedOptions := dbOptions
+	defer func() { dbOptions = savedOptions }()
+	dbOptions.ConfigPath = filepath.Join(t.TempDir(), defConfigFileName)
+
+	// every update but the first one backs up the file
+	for i := 1; i <= configHistorySize+2; i++ {
+		err := writeDatabaseConfig(makeTestDatabaseConfig(fmt.Sprintf("db%d", i)), vlog.Printer{})
+		assert.NoError(t, err)
+	}
+	backups, err := getConfigHistory(dbOptions.ConfigPath)
+	assert.NoError(t, err)
+	assert.Len(t, backups, configHistorySize)
+	for i := 1; i < len(backups); i++ {
+		assert.True(t, backups[i-1].Time.After(backups[i].Time))
+	}
+	// the files that are not timestamped backups are not in the history
+	err = os.WriteFile(dbOptions.ConfigPath+configBackupSuffix, []byte("foo"), configFilePerm)
+	assert.NoError(t, err)
+	backups, err = getConfigHistory(dbOptions.ConfigPath)
+	assert.NoError(t, err)
+	assert.Len(t, backups, configHistorySize)
+
+	// a rollback restores the latest backup, and can be undone
+	backup, err := rollbackConfigFile("", vlog.Printer{})
+	assert.NoError(t, err)
+	assert.Equal(t, backups[0].ID, backup.ID)
+	config, err := readConfig()
+	assert.NoError(t, err)
+	assert.Len(t, config.Databases, configHistorySize+1)
+	_, err = rollbackConfigFile("", vlog.Printer{})
+	assert.NoError(t, err)
+	config, err = readConfig()
+	assert.NoError(t, err)
+	assert.Len(t, config.Databases, configHistorySize+2)
+
+	// a rollback to a given backup. The oldest backups have been removed by
+	// the ones made by the rollbacks.
+	backups, err = getConfigHistory(dbOptions.ConfigPath)
+	assert.NoError(t, err)
+	_, err = rollbackConfigFile(backups[len(backups)-1].ID, vlog.Printer{})
+	assert.NoError(t, err)
+	config, err = readConfig()
+	assert.NoError(t, err)
+	assert.Len(t, config.Databases, 4)
+	_, err = rollbackConfigFile("20240101T000000.000000Z", vlog.Printer{})
+	assert.ErrorContains(t, err, `has no backup "20240101T000000.000000Z"`)
+
+	// an invalid backup is not restored
+	err = os.WriteFile(getConfigBackupPath(dbOptions.ConfigPath, "20991231T000000.000000Z"), []byte("foo: bar\n"), configFilePerm)
+	assert.NoError(t, err)
+	_, err = rollbackConfigFile("", vlog.Printer{})
+	assert.ErrorContains(t, err, "invalid backup 20991231T000000.000000Z")
+	config, err = readConfig()
+	assert.NoError(t, err)
+	assert.Len(t, config.Databases, 4)
+}
+
+func TestLockConfigFile(t *testing.T) {
+	savedOptions := dbOptions
+	defer func() { dbOptions = savedOptions }()
+	dbOptions.ConfigPath = filepath.Join(t.TempDir(), defConfigFileName)
+
+	// the lock is not given to another command while it is held
+	lock, err := lockConfigFile(dbOptions.ConfigPath, configLockTimeout)
+	assert.NoError(t, err)
+	_, err = lockConfigFile(dbOptions.ConfigPath, configLockPollInterval)
+	assert.ErrorContains(t, err, "is locked by another vcluster command")
+	lock.unlock()
+	lock, err = lockConfigFile(dbOptions.ConfigPath, 0)
+	assert.NoError(t, err)
+	lock.unlock()
+
+	// the concurrent updates of the file are serialized, so none is lost
+	const dbCount = 5
+	var wg sync.WaitGroup
+	errs := make([]error, dbCount)
+	for i := 0; i < dbCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = writeDatabaseConfig(makeTestDatabaseConfig(fmt.Sprintf("db%d", i)), vlog.Printer{})
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	config, err := readConfig()
+	assert.NoError(t, err)
+	assert.Len(t, config.Databases, dbCount)
+	// no temporary file is left
+	matches, err := filepath.Glob(dbOptions.ConfigPath + ".tmp*")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
@@ -0,0 +1,323 @@
+/*
+ (c) Copyright [2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"gopkg.in/yaml.v3"
+)
+
+// configProblem is a problem found in a config file
+type configProblem struct {
+	// the line of the problem in the file, 0 if it is not known
+	line int
+	// the path of the key with the problem, such as databases[0].nodes[1].catalogPath
+	path    string
+	message string
+}
+
+func (p *configProblem) String() string {
+	if p.line == 0 {
+		return fmt.Sprintf("%s: %s", p.path, p.message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", p.line, p.path, p.message)
+}
+
+// configProblems are all the problems found in a config file. It is the
+// error of reading an invalid config file.
+type configProblems []*configProblem
+
+func (problems configProblems) Error() string {
+	lines := make([]string, 0, len(problems)+1)
+	lines = append(lines, fmt.Sprintf("the configuration file has %d problem(s):", len(problems)))
+	for _, problem := range problems {
+		lines = append(lines, "  "+problem.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// the keys of each part of a config file, from the yaml tags of its struct
+var (
+	configKeys         = getYAMLKeys(Config{})
+	databaseConfigKeys = getYAMLKeys(DatabaseConfig{})
+	nodeConfigKeys     = getYAMLKeys(NodeConfig{})
+)
+
+// a type error of yaml, such as "line 5: cannot unmarshal !!str `abc` into bool"
+var yamlTypeErrorRegexp = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// configValidator collects the problems of a config file while it checks
+// the yaml nodes of the file and the config decoded from them
+type configValidator struct {
+	problems configProblems
+	// whether the lines of the yaml nodes are the ones of the file. They are
+	// not when the file has been migrated from an older version.
+	withLines bool
+}
+
+// validateConfig checks the content of a config file, of the current
+// version, against the schema of the config file. It returns the decoded
+// config and the problems found.
+func validateConfig(content []byte, withLines bool) (*Config, configProblems, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(content, &doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail to unmarshal configuration file, details: %w", err)
+	}
+
+	v := configValidator{withLines: withLines}
+	var config Config
+	if len(doc.Content) == 0 {
+		return &config, nil, nil
+	}
+	root := doc.Content[0]
+	err = root.Decode(&config)
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		v.addTypeErrors(typeErr)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("fail to unmarshal configuration file, details: %w", err)
+	}
+
+	v.validateConfig(root, &config)
+	if withLines {
+		sort.SliceStable(v.problems, func(i, j int) bool { return v.problems[i].line < v.problems[j].line })
+	}
+	return &config, v.problems, nil
+}
+
+func (v *configValidator) addProblem(node *yaml.Node, path, format string, args ...any) {
+	problem := configProblem{path: path, message: fmt.Sprintf(format, args...)}
+	if v.withLines {
+		problem.line = node.Line
+	}
+	v.problems = append(v.problems, &problem)
+}
+
+func (v *configValidator) addTypeErrors(typeErr *yaml.TypeError) {
+	for _, message := range typeErr.Errors {
+		problem := configProblem{path: "configuration file", message: message}
+		if matches := yamlTypeErrorRegexp.FindStringSubmatch(message); matches != nil {
+			problem.message = matches[2]
+			if v.withLines {
+				problem.line, _ = strconv.Atoi(matches[1])
+			}
+		}
+		v.problems = append(v.problems, &problem)
+	}
+}
+
+func (v *configValidator) validateConfig(root *yaml.Node, config *Config) {
+	if !v.validateKeys(root, "configuration file", configKeys) {
+		return
+	}
+
+	_, databasesNode := getMappingValue(root, "databases")
+	dbNames := make(map[string]bool)
+	for i, dbConfig := range config.Databases {
+		dbNode := getSequenceItem(databasesNode, i)
+		path := fmt.Sprintf("databases[%d]", i)
+		if dbConfig == nil || !v.validateKeys(dbNode, path, databaseConfigKeys) {
+			continue
+		}
+		v.validateDatabase(dbNode, path, dbConfig)
+		if dbNames[dbConfig.Name] {
+			v.addProblem(dbNode, path, "database %q is defined more than once", dbConfig.Name)
+		}
+		dbNames[dbConfig.Name] = true
+	}
+
+	if config.DefaultDatabase != "" && !dbNames[config.DefaultDatabase] {
+		_, defaultNode := getMappingValue(root, "defaultDatabase")
+		v.addProblem(defaultNode, "defaultDatabase", "database %q is not defined in databases", config.DefaultDatabase)
+	}
+}
+
+func (v *configValidator) validateDatabase(dbNode *yaml.Node, path string, dbConfig *DatabaseConfig) {
+	nameNode := v.getValueNode(dbNode, "dbName")
+	if dbConfig.Name == "" {
+		v.addProblem(nameNode, path+".dbName", "database name is required")
+	} else if err := util.ValidateDBName(dbConfig.Name); err != nil {
+		v.addProblem(nameNode, path+".dbName", "%v", err)
+	}
+
+	// the Eon fields require a communal storage location
+	if dbConfig.CommunalStorageLocation == "" {
+		if dbConfig.IsEon {
+			v.addProblem(v.getValueNode(dbNode, "eonMode"), path+".eonMode",
+				"an Eon database requires communalStorageLocation")
+		}
+		if dbConfig.DepotSize != "" {
+			v.addProblem(v.getValueNode(dbNode, "depotSize"), path+".depotSize",
+				"depotSize is only valid for an Eon database with communalStorageLocation")
+		}
+	}
+
+	nodesNode := v.getValueNode(dbNode, "nodes")
+	if len(dbConfig.Nodes) == 0 {
+		v.addProblem(nodesNode, path+".nodes", "database %q has no nodes", dbConfig.Name)
+	}
+	nodeNames := make(map[string]bool)
+	addresses := make(map[string]bool)
+	for i, nodeConfig := range dbConfig.Nodes {
+		nodeNode := getSequenceItem(nodesNode, i)
+		nodePath := fmt.Sprintf("%s.nodes[%d]", path, i)
+		if nodeConfig == nil || !v.validateKeys(nodeNode, nodePath, nodeConfigKeys) {
+			continue
+		}
+		v.validateNode(nodeNode, nodePath, nodeConfig, dbConfig)
+		if nodeConfig.Name != "" && nodeNames[nodeConfig.Name] {
+			v.addProblem(v.getValueNode(nodeNode, "name"), nodePath+".name",
+				"node name %q is used by another node", nodeConfig.Name)
+		}
+		if nodeConfig.Address != "" && addresses[nodeConfig.Address] {
+			v.addProblem(v.getValueNode(nodeNode, "address"), nodePath+".address",
+				"address %q is used by another node", nodeConfig.Address)
+		}
+		nodeNames[nodeConfig.Name] = true
+		addresses[nodeConfig.Address] = true
+	}
+}
+
+func (v *configValidator) validateNode(nodeNode *yaml.Node, path string, nodeConfig *NodeConfig, dbConfig *DatabaseConfig) {
+	if nodeConfig.Name == "" {
+		v.addProblem(nodeNode, path+".name", "node name is required")
+	}
+	if nodeConfig.Address == "" {
+		v.addProblem(nodeNode, path+".address", "node address is required")
+	}
+	v.validateName(nodeNode, path, "subcluster", nodeConfig.Subcluster)
+	v.validateName(nodeNode, path, "sandbox", nodeConfig.Sandbox)
+
+	v.validatePath(nodeNode, path, "catalogPath", nodeConfig.CatalogPath, true)
+	v.validatePath(nodeNode, path, "dataPath", nodeConfig.DataPath, true)
+	v.validatePath(nodeNode, path, "depotPath", nodeConfig.DepotPath, false)
+	if nodeConfig.DepotPath != "" && dbConfig.CommunalStorageLocation == "" {
+		v.addProblem(v.getValueNode(nodeNode, "depotPath"), path+".depotPath",
+			"depotPath is only valid for an Eon database with communalStorageLocation")
+	}
+	locationsNode := v.getValueNode(nodeNode, "storageLocations")
+	for i, location := range nodeConfig.StorageLocations {
+		if !filepath.IsAbs(location) {
+			v.addProblem(getSequenceItem(locationsNode, i), fmt.Sprintf("%s.storageLocations[%d]", path, i),
+				"storage location %q must be an absolute path", location)
+		}
+	}
+
+	v.validatePort(nodeNode, path, "nmaPort", nodeConfig.NMAPort)
+	v.validatePort(nodeNode, path, "httpsPort", nodeConfig.HTTPSPort)
+}
+
+// validateName checks that a name, such as the subcluster of a node, has no
+// special characters
+func (v *configValidator) validateName(nodeNode *yaml.Node, path, key, name string) {
+	if err := util.ValidateName(name, key); err != nil {
+		v.addProblem(v.getValueNode(nodeNode, key), path+"."+key, "%v", err)
+	}
+}
+
+func (v *configValidator) validatePort(nodeNode *yaml.Node, path, key string, port int) {
+	if err := util.ValidatePort(port, key); err != nil {
+		v.addProblem(v.getValueNode(nodeNode, key), path+"."+key, "%v", err)
+	}
+}
+
+// validatePath checks that a path of a node is absolute, and that it is set
+// if it is required
+func (v *configValidator) validatePath(nodeNode *yaml.Node, path, key, value string, required bool) {
+	if value == "" {
+		if required {
+			v.addProblem(nodeNode, path+"."+key, "%s is required", key)
+		}
+		return
+	}
+	if !filepath.IsAbs(value) {
+		v.addProblem(v.getValueNode(nodeNode, key), path+"."+key, "%s %q must be an absolute path", key, value)
+	}
+}
+
+// validateKeys checks that a node is a mapping whose keys are all known. It
+// returns false if the node is not a mapping.
+func (v *configValidator) validateKeys(node *yaml.Node, path string, knownKeys map[string]bool) bool {
+	if node == nil {
+		return false
+	}
+	if node.Kind != yaml.MappingNode {
+		v.addProblem(node, path, "must be a mapping")
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if !knownKeys[keyNode.Value] {
+			v.addProblem(keyNode, path, "unknown key %q", keyNode.Value)
+		}
+	}
+	return true
+}
+
+// getValueNode returns the value node of a key of a mapping node, or the
+// mapping node if the key is not in it, so that a problem of the key is
+// reported at the mapping
+func (v *configValidator) getValueNode(node *yaml.Node, key string) *yaml.Node {
+	if _, valueNode := getMappingValue(node, key); valueNode != nil {
+		return valueNode
+	}
+	return node
+}
+
+// getMappingValue returns the key and value nodes of a key of a mapping node
+func getMappingValue(node *yaml.Node, key string) (keyNode, valueNode *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// getSequenceItem returns an item of a sequence node, or nil if there is no
+// such item
+func getSequenceItem(node *yaml.Node, i int) *yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+		return nil
+	}
+	return node.Content[i]
+}
+
+// getYAMLKeys returns the keys of the yaml tags of the fields of a struct
+func getYAMLKeys(schema any) map[string]bool {
+	keys := make(map[string]bool)
+	schemaType := reflect.TypeOf(schema)
+	for i := 0; i < schemaType.NumField(); i++ {
+		key, _, _ := strings.Cut(schemaType.Field(i).Tag.Get("yaml"), ",")
+		if key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
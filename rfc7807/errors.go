@@ -208,3 +208,40 @@ var (
 		http.StatusInternalServerError,
 	)
 )
+
+// List of the problems that vcluster raises itself, for failures that are
+// found on the client side rather than reported by a server. The errors that
+// the VClusterCommands APIs return wrap them, so they can be found with
+// errors.As, or with errors.Is(err, New(id)).
+var (
+	ValidationError = newProblemID(
+		path.Join(errorEndpointsPrefix, "validation-error"),
+		"The options of the command are not valid",
+		http.StatusBadRequest,
+	)
+	HostUnreachable = newProblemID(
+		path.Join(errorEndpointsPrefix, "host-unreachable"),
+		"Host is unreachable",
+		http.StatusServiceUnavailable,
+	)
+	VersionMismatch = newProblemID(
+		path.Join(errorEndpointsPrefix, "version-mismatch"),
+		"Vertica versions do not match",
+		http.StatusConflict,
+	)
+	PollingTimeout = newProblemID(
+		path.Join(errorEndpointsPrefix, "polling-timeout"),
+		"Timed out while waiting for the expected state",
+		http.StatusGatewayTimeout,
+	)
+	WrongCredentials = newProblemID(
+		path.Join(errorEndpointsPrefix, "wrong-credentials"),
+		"Wrong password or certificate",
+		http.StatusUnauthorized,
+	)
+	PartialFailure = newProblemID(
+		path.Join(errorEndpointsPrefix, "partial-failure"),
+		"Operation failed on some of the hosts",
+		http.StatusInternalServerError,
+	)
+)
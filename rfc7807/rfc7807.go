@@ -59,14 +59,38 @@ type VProblem struct {
 
 	// Host is the vertica host name of IP where the problem occurred.
 	Host string `json:"host,omitempty"`
+
+	// cause is the error that the problem was raised for, when vcluster
+	// raises the problem itself rather than getting it from a server.
+	cause error
 }
 
 // Error implement this function so that VProblem can be passed around with Go's
 // error interface.
 func (v *VProblem) Error() string {
+	if v.Host == "" {
+		return fmt.Sprintf("%s, detail: %s", v.Title, v.Detail)
+	}
 	return fmt.Sprintf("%s on host %s, detail: %s", v.Title, v.Host, v.Detail)
 }
 
+// Unwrap returns the cause of the problem, so that errors.Is and errors.As
+// can find the errors that the problem was raised for.
+func (v *VProblem) Unwrap() error {
+	return v.cause
+}
+
+// Is returns true if the target is a VProblem of the same problem ID that has
+// no detail or host. This lets errors.Is(err, New(id)) find an occurrence of
+// the problem anywhere in the chain of err.
+func (v *VProblem) Is(target error) bool {
+	problem, ok := target.(*VProblem)
+	if !ok || problem.Detail != "" || problem.Host != "" {
+		return false
+	}
+	return v.IsInstanceOf(problem.ProblemID)
+}
+
 // New will return a new VProblem object. Each occurrence must have the
 // type and title, which is why those two are parameters here. The other fields
 // in the VProblem struct can be added after this call (see the With* helpers in
@@ -105,6 +129,16 @@ func (v *VProblem) WithDetail(d string) *VProblem {
 	return v
 }
 
+// WithCause will set the error that the problem was raised for. The detail is
+// set to the message of the error if it is not set yet.
+func (v *VProblem) WithCause(err error) *VProblem {
+	v.cause = err
+	if v.Detail == "" && err != nil {
+		v.Detail = err.Error()
+	}
+	return v
+}
+
 // WithHost will set the originating host in the VPrbolem. h can be a host name
 // or IP.
 func (v *VProblem) WithHost(h string) *VProblem {
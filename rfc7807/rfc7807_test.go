@@ -110,3 +110,23 @@ func TestJSONExtractFailure(t *testing.T) {
 	assert.False(t, ok)
 	assert.Contains(t, err.Error(), "failed to unmarshal the rfc7807 response")
 }
+
+func TestProblemWithCause(t *testing.T) {
+	cause := errors.New("connection refused")
+	p := New(HostUnreachable).WithHost("pod-4").WithCause(cause)
+	assert.Equal(t, cause.Error(), p.Detail)
+	assert.Equal(t, fmt.Sprintf("%s on host pod-4, detail: connection refused", HostUnreachable.Title), p.Error())
+
+	// the cause and the problem can both be found in the chain of an error
+	// that wraps the problem
+	err := fmt.Errorf("fail to send request: %w", p)
+	assert.ErrorIs(t, err, cause)
+	assert.ErrorIs(t, err, New(HostUnreachable))
+	assert.False(t, errors.Is(err, New(PollingTimeout)))
+	// only an occurrence without detail or host matches any occurrence
+	assert.False(t, errors.Is(err, New(HostUnreachable).WithHost("pod-5")))
+
+	// a problem without host does not tell where it occurred
+	p = New(ValidationError).WithDetail("must specify a host or host list")
+	assert.Equal(t, fmt.Sprintf("%s, detail: must specify a host or host list", ValidationError.Title), p.Error())
+}
@@ -25,36 +25,43 @@ import (
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
+// adapterPool holds the adapters that the ops of an engine run send their
+// requests with. Each engine run has its own pool, through the dispatcher
+// of its exec context, so concurrent VClusterCommands calls do not share any
+// adapter. The lock guards the connections, in case an op sends requests
+// from several goroutines.
 type adapterPool struct {
 	logger vlog.Printer
+	mu     sync.Mutex
 	// map from host to HTTPAdapter
 	connections map[string]adapter
+	// the maximum number of requests sent at once, 0 means no limit
+	maxConcurrency int
+	// the results of the hosts are sent to it as HostResultEvents
+	progress *progressReporter
 }
 
-var (
-	poolInstance adapterPool
-	once         sync.Once
-)
-
-// return a singleton instance of the AdapterPool
-func getPoolInstance(logger vlog.Printer) adapterPool {
-	/* if once.Do(f) is called multiple times,
-	 * only the first call will invoke f,
-	 * even if f has a different value in each invocation.
-	 * Reference: https://pkg.go.dev/sync#Once
-	 */
-	once.Do(func() {
-		poolInstance = makeAdapterPool(logger)
-	})
-
-	return poolInstance
-}
-
-func makeAdapterPool(logger vlog.Printer) adapterPool {
+func makeAdapterPool(logger vlog.Printer) *adapterPool {
 	newAdapterPool := adapterPool{}
 	newAdapterPool.connections = make(map[string]adapter)
 	newAdapterPool.logger = logger.WithName("AdapterPool")
-	return newAdapterPool
+	return &newAdapterPool
+}
+
+// setConnections replaces the adapters of the pool
+func (pool *adapterPool) setConnections(connections map[string]adapter) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.connections = connections
+}
+
+// addConnections adds adapters to the pool, replacing the ones of the same hosts
+func (pool *adapterPool) addConnections(connections map[string]adapter) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	for host, adpt := range connections {
+		pool.connections[host] = adpt
+	}
 }
 
 type adapterToRequest struct {
@@ -62,20 +69,26 @@ type adapterToRequest struct {
 	request hostHTTPRequest
 }
 
-func (pool *adapterPool) sendRequest(httpRequest *clusterHTTPRequest, spinner *yacspin.Spinner) error {
+func (pool *adapterPool) sendRequest(ctx context.Context, httpRequest *clusterHTTPRequest, spinner *yacspin.Spinner) error {
 	// build a collection of adapter to request
 	// we need this step as a host may not be in the pool
 	// in that case, we should not proceed
 	var adapterToRequestCollection []adapterToRequest
+	pool.mu.Lock()
 	for host := range httpRequest.RequestCollection {
 		request := httpRequest.RequestCollection[host]
+		if request.RetryPolicy == nil {
+			request.RetryPolicy = httpRequest.RetryPolicy
+		}
 		adpt, ok := pool.connections[host]
 		if !ok {
+			pool.mu.Unlock()
 			return fmt.Errorf("host %s is not found in the adapter pool", host)
 		}
 		ar := adapterToRequest{adapter: adpt, request: request}
 		adapterToRequestCollection = append(adapterToRequestCollection, ar)
 	}
+	pool.mu.Unlock()
 
 	hostCount := len(adapterToRequestCollection)
 
@@ -85,18 +98,26 @@ func (pool *adapterPool) sendRequest(httpRequest *clusterHTTPRequest, spinner *y
 	// only track the progress of HTTP requests for vcluster CLI
 	if pool.logger.ForCli {
 		// use context to check whether a step has completed
-		ctx, cancelCtx := context.WithCancel(context.Background())
-		go progressCheck(ctx, httpRequest.Name, pool.logger, spinner)
+		progressCtx, cancelCtx := context.WithCancel(ctx)
+		go progressCheck(progressCtx, httpRequest.Name, pool.logger, spinner)
 		// cancel the progress check context when the result channel is closed
 		defer cancelCtx()
 	}
 
+	// the requests wait for a slot before being sent, so that no more than
+	// maxConcurrency of them are in flight. The results are still collected
+	// from all the hosts below.
+	slots := make(chan struct{}, pool.getMaxConcurrency(httpRequest, hostCount))
 	for i := 0; i < len(adapterToRequestCollection); i++ {
 		ar := adapterToRequestCollection[i]
 		// send request to the hosts
 		// each goroutine will handle one request for one host
 		request := ar.request
-		go ar.adapter.sendRequest(&request, resultChannel)
+		go func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			ar.adapter.sendRequest(ctx, &request, resultChannel)
+		}()
 	}
 
 	// handle results
@@ -107,6 +128,7 @@ func (pool *adapterPool) sendRequest(httpRequest *clusterHTTPRequest, spinner *y
 		result, ok := <-resultChannel
 		if ok {
 			httpRequest.ResultCollection[result.host] = result
+			pool.progress.hostResult(httpRequest.Name, &result)
 		}
 	}
 	close(resultChannel)
@@ -114,6 +136,19 @@ func (pool *adapterPool) sendRequest(httpRequest *clusterHTTPRequest, spinner *y
 	return nil
 }
 
+// getMaxConcurrency returns the number of requests of httpRequest that can be
+// sent at once: the lower of the limits of the request and of the pool, and
+// no more than the number of hosts
+func (pool *adapterPool) getMaxConcurrency(httpRequest *clusterHTTPRequest, hostCount int) int {
+	maxConcurrency := hostCount
+	for _, limit := range []int{httpRequest.MaxConcurrency, pool.maxConcurrency} {
+		if limit > 0 && limit < maxConcurrency {
+			maxConcurrency = limit
+		}
+	}
+	return maxConcurrency
+}
+
 // progressCheck checks whether a step (operation) has been completed.
 // Elapsed time of the step in seconds will be displayed.
 func progressCheck(ctx context.Context, name string, logger vlog.Printer, spinner *yacspin.Spinner) {
@@ -128,7 +163,8 @@ func progressCheck(ctx context.Context, name string, logger vlog.Printer, spinne
 		case <-ctx.Done():
 			// context is canceled
 			// - when the requests to each host are completed, or
-			// - when the timeout is reached
+			// - when the timeout is reached, or
+			// - when the caller of the operation cancels it
 			return
 		case tickTime := <-ticker.C:
 			elapsedTime := tickTime.Sub(startTime)
@@ -0,0 +1,171 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestAdapterPoolPerDispatcher(t *testing.T) {
+	dispatcher1 := makeHTTPRequestDispatcher(vlog.Printer{ForCli: true})
+	dispatcher2 := makeHTTPRequestDispatcher(vlog.Printer{})
+	dispatcher1.setup([]string{"host1", "host2"})
+	dispatcher2.setup([]string{"host3"})
+
+	// each pool logs with the logger of its own caller
+	assert.True(t, dispatcher1.pool.logger.ForCli)
+	assert.False(t, dispatcher2.pool.logger.ForCli)
+
+	assert.Len(t, dispatcher1.pool.connections, 2)
+	assert.Len(t, dispatcher2.pool.connections, 1)
+	assert.Contains(t, dispatcher2.pool.connections, "host3")
+
+	// the download adapters are added to the ones of the pool
+	dispatcher2.setupForDownload([]string{"host4"}, map[string]string{"host4": "/tmp/file"})
+	assert.Len(t, dispatcher1.pool.connections, 2)
+	assert.Len(t, dispatcher2.pool.connections, 2)
+}
+
+func TestConcurrentDatabases(t *testing.T) {
+	dbNames := []string{"test_db1", "test_db2"}
+	var clusters []*fakecluster.Cluster
+	for range dbNames {
+		// the clusters have the same hosts, so that a request sent to
+		// the wrong cluster would be seen
+		cluster := fakecluster.New(fakeClusterHosts...)
+		defer cluster.Close()
+		clusters = append(clusters, cluster)
+	}
+
+	var wg sync.WaitGroup
+	for i := range dbNames {
+		wg.Add(1)
+		go func(dbName string, cluster *fakecluster.Cluster) {
+			defer wg.Done()
+			vcc := makeFakeClusterCommands(cluster)
+
+			options := VCreateDatabaseOptionsFactory()
+			setFakeClusterOptions(&options.DatabaseOptions, cluster, fakeClusterHosts)
+			options.DBName = dbName
+			options.CatalogPrefix = defaultPath
+			options.DataPrefix = defaultPath
+			_, _, err := vcc.VCreateDatabase(context.Background(), &options)
+			assert.NoError(t, err)
+
+			fetchOptions := VFetchNodeStateOptionsFactory()
+			setFakeClusterOptions(&fetchOptions.DatabaseOptions, cluster, fakeClusterHosts)
+			fetchOptions.DBName = dbName
+			nodeStates, _, err := vcc.VFetchNodeState(context.Background(), &fetchOptions)
+			assert.NoError(t, err)
+			assert.Len(t, nodeStates, len(fakeClusterHosts))
+
+			stopOptions := VStopDatabaseOptionsFactory()
+			setFakeClusterOptions(&stopOptions.DatabaseOptions, cluster, fakeClusterHosts)
+			stopOptions.DBName = dbName
+			_, err = vcc.VStopDatabase(context.Background(), &stopOptions)
+			assert.NoError(t, err)
+		}(dbNames[i], clusters[i])
+	}
+	wg.Wait()
+
+	for i, cluster := range clusters {
+		assert.Equal(t, dbNames[i], cluster.DatabaseName())
+		for _, node := range cluster.Nodes() {
+			assert.Equal(t, util.NodeDownState, node.State)
+		}
+	}
+}
+
+// countingAdapter records the highest number of requests it has had in flight
+type countingAdapter struct {
+	host        string
+	mu          *sync.Mutex
+	inFlight    *int
+	maxInFlight *int
+}
+
+func (adpt countingAdapter) sendRequest(_ context.Context, _ *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+	adpt.mu.Lock()
+	*adpt.inFlight++
+	if *adpt.inFlight > *adpt.maxInFlight {
+		*adpt.maxInFlight = *adpt.inFlight
+	}
+	adpt.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	adpt.mu.Lock()
+	*adpt.inFlight--
+	adpt.mu.Unlock()
+	resultChannel <- hostHTTPResult{host: adpt.host, status: SUCCESS, statusCode: SuccessCode}
+}
+
+func (adpt countingAdapter) generateResult(*http.Response) hostHTTPResult {
+	return hostHTTPResult{}
+}
+
+func TestAdapterPoolMaxConcurrency(t *testing.T) {
+	const hostCount = 20
+	var mu sync.Mutex
+	inFlight := 0
+	maxInFlight := 0
+	pool := makeAdapterPool(vlog.Printer{})
+	connections := make(map[string]adapter)
+	httpRequest := clusterHTTPRequest{RequestCollection: make(map[string]hostHTTPRequest)}
+	for i := 0; i < hostCount; i++ {
+		host := fmt.Sprintf("host%d", i)
+		connections[host] = countingAdapter{host: host, mu: &mu, inFlight: &inFlight, maxInFlight: &maxInFlight}
+		httpRequest.RequestCollection[host] = hostHTTPRequest{}
+	}
+	pool.setConnections(connections)
+
+	sendRequest := func() {
+		maxInFlight = 0
+		err := pool.sendRequest(context.Background(), &httpRequest, nil)
+		assert.NoError(t, err)
+		// the results of all the hosts are collected
+		assert.Len(t, httpRequest.ResultCollection, hostCount)
+	}
+
+	// no limit by default
+	sendRequest()
+	assert.Equal(t, hostCount, maxInFlight)
+
+	// the limit of the engine
+	pool.maxConcurrency = 5
+	sendRequest()
+	assert.Equal(t, 5, maxInFlight)
+
+	// the lower limit of the op
+	httpRequest.MaxConcurrency = 2
+	sendRequest()
+	assert.Equal(t, 2, maxInFlight)
+
+	// the lower limit of the engine
+	pool.maxConcurrency = 1
+	sendRequest()
+	assert.Equal(t, 1, maxInFlight)
+}
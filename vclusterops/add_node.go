@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -46,6 +47,11 @@ type VAddNodeOptions struct {
 	// Names of the existing nodes in the cluster. This option can be
 	// used to remove partially added nodes from catalog.
 	ExpectedNodeNames []string
+	// If true, the journal in JournalPath is the one of the add_subcluster
+	// that has created the subcluster of the nodes. The ops of add_node are
+	// recorded in it after the ones of add_subcluster, so that the nodes
+	// created already are kept when add_subcluster is resumed.
+	ContinueAddSubclusterJournal bool
 }
 
 func VAddNodeOptionsFactory() VAddNodeOptions {
@@ -119,15 +125,30 @@ func (o *VAddNodeOptions) validateAnalyzeOptions(logger vlog.Printer) error {
 
 // VAddNode adds one or more nodes to an existing database.
 // It returns a VCoordinationDatabase that contains catalog information and any error encountered.
-func (vcc VClusterCommands) VAddNode(options *VAddNodeOptions) (VCoordinationDatabase, error) {
+func (vcc VClusterCommands) VAddNode(ctx context.Context, options *VAddNodeOptions) (VCoordinationDatabase, *ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandAddNode, &options.DatabaseOptions)
+	vdb, err := vcc.addNode(ctx, options)
+	report := call.finish(err)
+	return vdb, report, err
+}
+
+func (vcc VClusterCommands) addNode(ctx context.Context, options *VAddNodeOptions) (VCoordinationDatabase, error) {
 	vdb := makeVCoordinationDatabase()
 
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return vdb, err
+		return vdb, makeValidationProblem(err)
+	}
+
+	call := getCall(ctx)
+	if options.ContinueAddSubclusterJournal {
+		err = call.continueJournal(&options.DatabaseOptions, commandAddNode, commandAddCluster)
+		if err != nil {
+			return vdb, err
+		}
 	}
 
-	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+	err = vcc.getVDBFromRunningDB(ctx, &vdb, &options.DatabaseOptions)
 	if err != nil {
 		return vdb, err
 	}
@@ -152,19 +173,19 @@ func (vcc VClusterCommands) VAddNode(options *VAddNodeOptions) (VCoordinationDat
 
 	// trim stale node information from catalog
 	// if NodeNames is provided
-	err = vcc.trimNodesInCatalog(&vdb, options)
+	err = vcc.trimNodesInCatalog(ctx, &vdb, options)
 	if err != nil {
 		return vdb, err
 	}
 
 	// add_node is aborted if requirements are not met.
 	// Here we check whether the nodes being added already exist
-	err = checkAddNodeRequirements(&vdb, options.NewHosts)
+	hostsToCreate, err := options.getHostsToCreate(&vdb, call.journal.isResuming())
 	if err != nil {
 		return vdb, err
 	}
 
-	err = vdb.addHosts(options.NewHosts, options.SCName)
+	err = vdb.addHosts(hostsToCreate, options.SCName)
 	if err != nil {
 		return vdb, err
 	}
@@ -174,9 +195,8 @@ func (vcc VClusterCommands) VAddNode(options *VAddNodeOptions) (VCoordinationDat
 		return vdb, fmt.Errorf("fail to produce add node instructions, %w", err)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	if runError := clusterOpEngine.run(vcc.Log); runError != nil {
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	if runError := clusterOpEngine.run(ctx, vcc.Log); runError != nil {
 		return vdb, fmt.Errorf("fail to complete add node operation, %w", runError)
 	}
 	return vdb, nil
@@ -193,6 +213,28 @@ func checkAddNodeRequirements(vdb *VCoordinationDatabase, hostsToAdd []string) e
 	return nil
 }
 
+// getHostsToCreate returns the new hosts whose nodes are not in the
+// database yet. When the command is resumed from a journal, the nodes that
+// the earlier attempt created in the subcluster are kept, and the ops that
+// created them are skipped. Otherwise, none of the nodes can exist.
+func (o *VAddNodeOptions) getHostsToCreate(vdb *VCoordinationDatabase, resuming bool) ([]string, error) {
+	if !resuming {
+		return o.NewHosts, checkAddNodeRequirements(vdb, o.NewHosts)
+	}
+	var hostsToCreate []string
+	for _, host := range o.NewHosts {
+		vnode, ok := vdb.HostNodeMap[host]
+		if !ok {
+			hostsToCreate = append(hostsToCreate, host)
+			continue
+		}
+		if vnode.Subcluster != o.SCName {
+			return nil, fmt.Errorf("%s already exist in the database", host)
+		}
+	}
+	return hostsToCreate, nil
+}
+
 // completeVDBSetting sets some VCoordinationDatabase fields we cannot get yet
 // from the https endpoints. We set those fields from options.
 func (o *VAddNodeOptions) completeVDBSetting(vdb *VCoordinationDatabase) error {
@@ -217,7 +259,7 @@ func (o *VAddNodeOptions) completeVDBSetting(vdb *VCoordinationDatabase) error {
 
 // trimNodesInCatalog removes failed node info from catalog
 // which can be used to remove partially added nodes
-func (vcc VClusterCommands) trimNodesInCatalog(vdb *VCoordinationDatabase,
+func (vcc VClusterCommands) trimNodesInCatalog(ctx context.Context, vdb *VCoordinationDatabase,
 	options *VAddNodeOptions) error {
 	if len(options.ExpectedNodeNames) == 0 {
 		vcc.Log.Info("ExpectedNodeNames is not set, skip trimming nodes", "ExpectedNodeNames", options.ExpectedNodeNames)
@@ -283,9 +325,8 @@ func (vcc VClusterCommands) trimNodesInCatalog(vdb *VCoordinationDatabase,
 		instructions = append(instructions, &httpsDropNodeOp)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err := clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err := clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		vcc.Log.Error(err, "fail to trim nodes from catalog, %v")
 		return err
@@ -358,6 +399,8 @@ func (vcc VClusterCommands) produceAddNodeInstructions(vdb *VCoordinationDatabas
 	if err != nil {
 		return instructions, err
 	}
+	// drop the new nodes from the catalog if they cannot be started
+	httpsCreateNodeOp.undoable = true
 	httpsReloadSpreadOp, err := makeHTTPSReloadSpreadOpWithInitiator(initiatorHost, usePassword, username, password)
 	if err != nil {
 		return instructions, err
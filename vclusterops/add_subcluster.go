@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -179,7 +180,14 @@ func (options *VAddSubclusterOptions) validateAnalyzeOptions(vcc VClusterCommand
 
 // VAddSubcluster adds to a running database a new subcluster with provided options.
 // It returns any error encountered.
-func (vcc VClusterCommands) VAddSubcluster(options *VAddSubclusterOptions) error {
+func (vcc VClusterCommands) VAddSubcluster(ctx context.Context, options *VAddSubclusterOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandAddCluster, &options.DatabaseOptions)
+	err := vcc.addSubcluster(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) addSubcluster(ctx context.Context, options *VAddSubclusterOptions) error {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -188,21 +196,26 @@ func (vcc VClusterCommands) VAddSubcluster(options *VAddSubclusterOptions) error
 
 	// validate and analyze all options
 	err := options.validateAnalyzeOptions(vcc)
+	if err != nil {
+		return makeValidationProblem(err)
+	}
+
+	// the subcluster is created only once if the command is resumed
+	err = getCall(ctx).startJournal(&options.DatabaseOptions, commandAddCluster)
 	if err != nil {
 		return err
 	}
 
-	instructions, err := vcc.produceAddSubclusterInstructions(options)
+	instructions, err := vcc.produceAddSubclusterInstructions(ctx, options)
 	if err != nil {
 		return fmt.Errorf("fail to produce instructions, %w", err)
 	}
 
 	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to add subcluster %s, %w", options.SCName, runError)
 	}
@@ -222,12 +235,12 @@ func (vcc VClusterCommands) VAddSubcluster(options *VAddSubclusterOptions) error
 //     if the subcluster name already exists
 //   - Check if the new subcluster is created in database through HTTPS call
 //   - TODO: add new nodes to the subcluster
-func (vcc *VClusterCommands) produceAddSubclusterInstructions(options *VAddSubclusterOptions) ([]clusterOp, error) {
+func (vcc *VClusterCommands) produceAddSubclusterInstructions(ctx context.Context, options *VAddSubclusterOptions) ([]clusterOp, error) {
 	var instructions []clusterOp
 	vdb := makeVCoordinationDatabase()
 
 	// get cluster info
-	err := vcc.getClusterInfoFromRunningDB(&vdb, &options.DatabaseOptions)
+	err := vcc.getClusterInfoFromRunningDB(ctx, &vdb, &options.DatabaseOptions)
 	if err != nil {
 		return instructions, err
 	}
@@ -21,6 +21,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -29,6 +30,8 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/theckman/yacspin"
+	"github.com/vertica/vcluster/vclusterops/metrics"
+	"github.com/vertica/vcluster/vclusterops/tracing"
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
@@ -84,6 +87,10 @@ type hostHTTPResult struct {
 	host       string
 	content    string
 	err        error // This is set if the http response ends in a failure scenario
+	// number of times the request was sent, which is more than 1 if it was retried
+	attempts int
+	// errors of the attempts that failed and were retried
+	retriedErrs []error
 }
 
 type httpsResponseStatus struct {
@@ -173,6 +180,7 @@ func (status resultStatus) getStatusString() string {
 // log* implemented by embedding OpBase, but overrideable
 type clusterOp interface {
 	getName() string
+	getDescription() string
 	setLogger(logger vlog.Printer)
 	setupSpinner()
 	startSpinner()
@@ -180,7 +188,7 @@ type clusterOp interface {
 	stopFailSpinner()
 	stopFailSpinnerWithMessage(errMsg string, v ...any)
 	prepare(execContext *opEngineExecContext) error
-	execute(execContext *opEngineExecContext) error
+	execute(ctx context.Context, execContext *opEngineExecContext) error
 	finalize(execContext *opEngineExecContext) error
 	processResult(execContext *opEngineExecContext) error
 	logResponse(host string, result hostHTTPResult)
@@ -190,6 +198,13 @@ type clusterOp interface {
 	setupBasicInfo()
 	loadCertsIfNeeded(certs *httpsCerts, findCertsInOptions bool) error
 	isSkipExecute() bool
+	isReadOnly() bool
+	getPlan() OpPlan
+	getReport() OpReport
+	getHostResults() []HostResultReport
+	makeClusterOpError(err error) error
+	getStageID() int64
+	setStageID(stageID int64)
 }
 
 /* Cluster ops basic fields and functions
@@ -204,7 +219,9 @@ type opBase struct {
 	hosts              []string
 	clusterHTTPRequest clusterHTTPRequest
 	skipExecute        bool // This can be set during prepare if we determine no work is needed
+	readsOnly          bool // This is set by the ops that only read, although they do not send GET requests
 	spinner            *yacspin.Spinner
+	stageID            int64 // The ops with the same stage ID run at the same time, 0 means no stage
 }
 
 type opResponseMap map[string]string
@@ -213,6 +230,10 @@ func (op *opBase) getName() string {
 	return op.name
 }
 
+func (op *opBase) getDescription() string {
+	return op.description
+}
+
 func (op *opBase) setLogger(logger vlog.Printer) {
 	op.logger = logger.WithName(op.name)
 }
@@ -249,9 +270,18 @@ func (op *opBase) setupBasicInfo() {
 	op.setVersionToSemVar()
 }
 
-// setupSpinner sets up the progress spinner
+func (op *opBase) getStageID() int64 {
+	return op.stageID
+}
+
+func (op *opBase) setStageID(stageID int64) {
+	op.stageID = stageID
+}
+
+// setupSpinner sets up the progress spinner. The ops of a concurrent stage
+// do not have one, as their spinners would write over each other.
 func (op *opBase) setupSpinner() {
-	if op.logger.ForCli {
+	if op.logger.ForCli && op.stageID == 0 {
 		cfg := yacspin.Config{
 			Frequency:         100 * time.Millisecond,
 			CharSet:           yacspin.CharSets[11],
@@ -348,8 +378,8 @@ func (op *opBase) logFinalize() {
 	op.logger.Info("Finalize() called", "name", op.name)
 }
 
-func (op *opBase) runExecute(execContext *opEngineExecContext) error {
-	err := execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
+func (op *opBase) runExecute(ctx context.Context, execContext *opEngineExecContext) error {
+	err := execContext.dispatcher.sendRequest(ctx, &op.clusterHTTPRequest, op.spinner)
 	if err != nil {
 		op.logger.Error(err, "Fail to dispatch request, detail", "dispatch request", op.clusterHTTPRequest)
 		return err
@@ -484,28 +514,32 @@ type ClusterCommands interface {
 	PrintWarning(msg string, v ...any)
 	PrintError(msg string, v ...any)
 
-	VAddNode(options *VAddNodeOptions) (VCoordinationDatabase, error)
-	VStopNode(options *VStopNodeOptions) error
-	VAddSubcluster(options *VAddSubclusterOptions) error
-	VCreateDatabase(options *VCreateDatabaseOptions) (VCoordinationDatabase, error)
-	VDropDatabase(options *VDropDatabaseOptions) error
-	VFetchNodeState(options *VFetchNodeStateOptions) ([]NodeInfo, error)
-	VInstallPackages(options *VInstallPackagesOptions) (*InstallPackageStatus, error)
-	VReIP(options *VReIPOptions) error
-	VRemoveNode(options *VRemoveNodeOptions) (VCoordinationDatabase, error)
-	VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VCoordinationDatabase, error)
-	VReviveDatabase(options *VReviveDatabaseOptions) (dbInfo string, vdbPtr *VCoordinationDatabase, err error)
-	VSandbox(options *VSandboxOptions) error
-	VScrutinize(options *VScrutinizeOptions) error
-	VShowRestorePoints(options *VShowRestorePointsOptions) (restorePoints []RestorePoint, err error)
-	VStartDatabase(options *VStartDatabaseOptions) (vdbPtr *VCoordinationDatabase, err error)
-	VStartNodes(options *VStartNodesOptions) error
-	VStopDatabase(options *VStopDatabaseOptions) error
-	VReplicateDatabase(options *VReplicationDatabaseOptions) error
-	VFetchCoordinationDatabase(options *VFetchCoordinationDatabaseOptions) (VCoordinationDatabase, error)
-	VUnsandbox(options *VUnsandboxOptions) error
-	VStopSubcluster(options *VStopSubclusterOptions) error
-	VFetchNodesDetails(options *VFetchNodesDetailsOptions) (NodesDetails, error)
+	VAddNode(ctx context.Context, options *VAddNodeOptions) (VCoordinationDatabase, *ExecutionReport, error)
+	VStopNode(ctx context.Context, options *VStopNodeOptions) (*ExecutionReport, error)
+	VAddSubcluster(ctx context.Context, options *VAddSubclusterOptions) (*ExecutionReport, error)
+	VCreateDatabase(ctx context.Context, options *VCreateDatabaseOptions) (VCoordinationDatabase, *ExecutionReport, error)
+	VDropDatabase(ctx context.Context, options *VDropDatabaseOptions) (*ExecutionReport, error)
+	VFetchNodeState(ctx context.Context, options *VFetchNodeStateOptions) ([]NodeInfo, *ExecutionReport, error)
+	VInstallPackages(ctx context.Context, options *VInstallPackagesOptions) (*InstallPackageStatus, *ExecutionReport, error)
+	VReIP(ctx context.Context, options *VReIPOptions) (*ExecutionReport, error)
+	VRemoveNode(ctx context.Context, options *VRemoveNodeOptions) (VCoordinationDatabase, *ExecutionReport, error)
+	VRemoveSubcluster(ctx context.Context, removeScOpt *VRemoveScOptions) (VCoordinationDatabase, *ExecutionReport, error)
+	VReviveDatabase(ctx context.Context,
+		options *VReviveDatabaseOptions) (dbInfo string, vdbPtr *VCoordinationDatabase, report *ExecutionReport, err error)
+	VSandbox(ctx context.Context, options *VSandboxOptions) (*ExecutionReport, error)
+	VScrutinize(ctx context.Context, options *VScrutinizeOptions) (*ExecutionReport, error)
+	VShowRestorePoints(ctx context.Context,
+		options *VShowRestorePointsOptions) (restorePoints []RestorePoint, report *ExecutionReport, err error)
+	VStartDatabase(ctx context.Context, options *VStartDatabaseOptions) (vdbPtr *VCoordinationDatabase, report *ExecutionReport, err error)
+	VStartNodes(ctx context.Context, options *VStartNodesOptions) (*ExecutionReport, error)
+	VStopDatabase(ctx context.Context, options *VStopDatabaseOptions) (*ExecutionReport, error)
+	VReplicateDatabase(ctx context.Context, options *VReplicationDatabaseOptions) (*ExecutionReport, error)
+	VFetchCoordinationDatabase(ctx context.Context,
+		options *VFetchCoordinationDatabaseOptions) (VCoordinationDatabase, *ExecutionReport, error)
+	VUnsandbox(ctx context.Context, options *VUnsandboxOptions) (*ExecutionReport, error)
+	VStopSubcluster(ctx context.Context, options *VStopSubclusterOptions) (*ExecutionReport, error)
+	VFetchNodesDetails(ctx context.Context,
+		options *VFetchNodesDetailsOptions) (nodesDetails NodesDetails, report *ExecutionReport, err error)
 }
 
 type VClusterCommandsLogger struct {
@@ -544,4 +578,13 @@ func (vcc VClusterCommandsLogger) PrintError(msg string, v ...any) {
 // (e.g. create db, add node, etc.).
 type VClusterCommands struct {
 	VClusterCommandsLogger
+	// the transport used to send requests to the hosts, nil means the network
+	Transport HTTPTransport
+	// OnProgress, if set, receives the progress events of the calls
+	OnProgress ProgressHandler
+	// Metrics, if set, is where the metrics of the calls are recorded
+	Metrics *metrics.Registry
+	// Tracer, if set, records a span for each call, and for each op and
+	// host request of the call
+	Tracer tracing.Tracer
 }
@@ -16,15 +16,38 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/vertica/vcluster/vclusterops/tracing"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 type VClusterOpEngine struct {
 	instructions []clusterOp
 	certs        *httpsCerts
+	ports        portConfig
 	execContext  *opEngineExecContext
+	// when dryRun is set, the ops that change the database, and the ones
+	// after them, are added to the plan instead of being run
+	dryRun bool
+	plan   *DryRunPlan
+	// the ops that are run are added to the report if it is set
+	report *ExecutionReport
+	// the transport used to send the requests, nil means the network
+	transport HTTPTransport
+	// the maximum number of requests that an op sends at once, 0 means no
+	// limit. An op can set its own limit in its clusterHTTPRequest.
+	maxConcurrency int
+	// the completed ops are recorded in the journal if it is set
+	journal *opJournal
+	// the progress events are sent to it if it is set
+	progress *progressReporter
+	// the metrics of the ops are recorded in it if it is set
+	metrics *opMetrics
+	// the timeouts of the requests and the polling of the ops
+	timeouts TimeoutPolicy
 }
 
 func makeClusterOpEngine(instructions []clusterOp, certs *httpsCerts) VClusterOpEngine {
@@ -38,27 +61,187 @@ func (opEngine *VClusterOpEngine) shouldGetCertsFromOptions() bool {
 	return (opEngine.certs.key != "" && opEngine.certs.cert != "")
 }
 
-func (opEngine *VClusterOpEngine) run(logger vlog.Printer) error {
+// OpCanceledError is returned by the VClusterCommands APIs when the context
+// passed in is canceled, or its deadline is exceeded, while an operation is
+// running. OpName is the name of the op that was interrupted. The context
+// error is wrapped, so errors.Is(err, context.Canceled) and
+// errors.Is(err, context.DeadlineExceeded) can be used on it.
+type OpCanceledError struct {
+	OpName string
+	Err    error
+}
+
+func (e *OpCanceledError) Error() string {
+	return fmt.Sprintf("[%s] was interrupted: %v", e.OpName, e.Err)
+}
+
+func (e *OpCanceledError) Unwrap() error {
+	return e.Err
+}
+
+// run runs the instructions. The engine uses the report, the transport, the
+// progress, the metrics and the journal of the call that ctx is the context of.
+func (opEngine *VClusterOpEngine) run(ctx context.Context, logger vlog.Printer) error {
+	if call := getCall(ctx); call != nil {
+		opEngine.report = call.report
+		opEngine.transport = call.transport
+		opEngine.progress = call.progress
+		opEngine.metrics = call.metrics
+		opEngine.journal = call.journal
+	}
 	execContext := makeOpEngineExecContext(logger)
 	opEngine.execContext = &execContext
 
-	return opEngine.runWithExecContext(logger, &execContext)
+	return opEngine.runWithExecContext(ctx, logger, &execContext)
 }
 
-func (opEngine *VClusterOpEngine) runWithExecContext(logger vlog.Printer, execContext *opEngineExecContext) error {
+func (opEngine *VClusterOpEngine) runWithExecContext(ctx context.Context, logger vlog.Printer,
+	execContext *opEngineExecContext) error {
 	findCertsInOptions := opEngine.shouldGetCertsFromOptions()
+	execContext.dispatcher.ports = opEngine.ports
+	execContext.dispatcher.transport = opEngine.transport
+	execContext.dispatcher.pool.maxConcurrency = opEngine.maxConcurrency
+	execContext.dispatcher.progress = opEngine.progress
+	execContext.dispatcher.pool.progress = opEngine.progress
+	execContext.dispatcher.metrics = opEngine.metrics
+	execContext.dispatcher.timeouts = opEngine.timeouts
+	// the connections kept alive for the ops are not needed after the run
+	defer execContext.dispatcher.transports.closeIdleConnections()
 
-	for _, op := range opEngine.instructions {
-		err := opEngine.runInstruction(logger, execContext, op, findCertsInOptions)
+	journalRunIndex := opEngine.journal.startRun()
+	for start := 0; start < len(opEngine.instructions); {
+		// stop before the next instruction if the caller has given up, and
+		// undo the changes of the ones before
+		if ctx.Err() != nil {
+			err := &OpCanceledError{OpName: opEngine.instructions[start].getName(), Err: ctx.Err()}
+			return opEngine.undo(logger, execContext, findCertsInOptions, journalRunIndex, err)
+		}
+		var err error
+		end := opEngine.getStageEnd(start)
+		if end-start == 1 {
+			err = opEngine.runOp(ctx, logger, execContext, findCertsInOptions, journalRunIndex, start)
+		} else {
+			err = opEngine.runConcurrentStage(ctx, logger, execContext, findCertsInOptions, journalRunIndex, start, end)
+		}
 		if err != nil {
-			return err
+			return opEngine.undo(logger, execContext, findCertsInOptions, journalRunIndex, err)
 		}
+		start = end
 	}
 
+	if opEngine.plan != nil {
+		return opEngine.plan
+	}
 	return nil
 }
 
+// runOp runs the instruction at opIndex, and adds it to the report and the
+// journal
+func (opEngine *VClusterOpEngine) runOp(ctx context.Context, logger vlog.Printer,
+	execContext *opEngineExecContext, findCertsInOptions bool, journalRunIndex, opIndex int) error {
+	op := opEngine.instructions[opIndex]
+	startTime := time.Now()
+	execContext.opIndex = opIndex
+	skipped, err := opEngine.resumeInstruction(logger, execContext, journalRunIndex, opIndex, op)
+	if skipped {
+		opEngine.finishOp(op, startTime, SkippedResult, nil)
+		return nil
+	}
+	if err == nil {
+		opEngine.progress.opStarted(op)
+		err = opEngine.runInstruction(ctx, logger, execContext, op, findCertsInOptions)
+	}
+	status := opEngine.getInstructionStatus(op, err)
+	opEngine.finishOp(op, startTime, status, err)
+	if err != nil {
+		return err
+	}
+	if status != PlannedResult {
+		journalOp := makeJournalOp(op, execContext)
+		opEngine.journal.complete(journalRunIndex, opIndex, &journalOp, logger)
+	}
+	return nil
+}
+
+// finishOp adds an op that has been run, or skipped, to the report, and
+// sends its OpFinishedEvent
+func (opEngine *VClusterOpEngine) finishOp(op clusterOp, startTime time.Time, status string, err error) {
+	opEngine.report.addOp(op, startTime, status, err)
+	opEngine.progress.opFinished(op, status, err)
+	opEngine.metrics.observeOp(op, startTime, status)
+}
+
+// getInstructionStatus returns the status of an op that has been run
+// for the execution report
+func (opEngine *VClusterOpEngine) getInstructionStatus(op clusterOp, err error) string {
+	switch {
+	case err != nil:
+		return FailureResult
+	case opEngine.plan != nil:
+		// once an op is planned, the ones after it are planned too
+		return PlannedResult
+	case op.isSkipExecute():
+		return SkippedResult
+	default:
+		return SuccessResult
+	}
+}
+
+// planInstruction adds the op to the plan if the engine is in dry run mode, and
+// either the op changes the database or an earlier op has been planned. It
+// returns true if the op has been planned, so it should not be run.
+func (opEngine *VClusterOpEngine) planInstruction(op clusterOp, prepareErr error) bool {
+	if !opEngine.dryRun {
+		return false
+	}
+	if opEngine.plan == nil {
+		if prepareErr != nil || op.isSkipExecute() || op.isReadOnly() {
+			return false
+		}
+		opEngine.plan = &DryRunPlan{}
+	}
+
+	opPlan := op.getPlan()
+	if prepareErr != nil {
+		opPlan.Requests = nil
+		opPlan.Note = fmt.Sprintf("the requests depend on the results of the planned operations before: %v", prepareErr)
+	} else if op.isSkipExecute() {
+		opPlan.Note = "no work is needed at this point, this may change after the planned operations before"
+	}
+	opEngine.plan.Ops = append(opEngine.plan.Ops, opPlan)
+	return true
+}
+
+// resumeInstruction skips the op if the command is resumed from a journal
+// where the op has completed and changed the cluster. The op is recorded
+// again in the journal, and the state that it had set is restored.
+func (opEngine *VClusterOpEngine) resumeInstruction(logger vlog.Printer, execContext *opEngineExecContext,
+	journalRunIndex, opIndex int, op clusterOp) (bool, error) {
+	resumedOp, err := opEngine.journal.getResumedOp(journalRunIndex, opIndex, op)
+	if err != nil || !resumedOp.canSkip() {
+		return false, err
+	}
+	logger.PrintInfo("[%s] was completed by an earlier run of the command, skipping it", op.getName())
+	resumedOp.State.restore(execContext)
+	opEngine.journal.complete(journalRunIndex, opIndex, resumedOp, logger)
+	return true, nil
+}
+
+// runInstruction runs the op in a span of its own, the child of the span of
+// the call
 func (opEngine *VClusterOpEngine) runInstruction(
+	ctx context.Context,
+	logger vlog.Printer, execContext *opEngineExecContext,
+	op clusterOp, findCertsInOptions bool) error {
+	ctx, span := tracing.StartChild(ctx, op.getName())
+	span.SetAttribute("vcluster.op.description", op.getDescription())
+	err := opEngine.runInstructionInSpan(ctx, logger, execContext, op, findCertsInOptions)
+	span.End(err)
+	return err
+}
+
+func (opEngine *VClusterOpEngine) runInstructionInSpan(
+	ctx context.Context,
 	logger vlog.Printer, execContext *opEngineExecContext,
 	op clusterOp, findCertsInOptions bool) error {
 	op.setLogger(logger)
@@ -68,6 +251,10 @@ func (opEngine *VClusterOpEngine) runInstruction(
 
 	op.logPrepare()
 	err := op.prepare(execContext)
+	if opEngine.planInstruction(op, err) {
+		logger.Info("Planned operation in dry run", "op", op.getName())
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("prepare %s failed, details: %w", op.getName(), err)
 	}
@@ -86,12 +273,17 @@ func (opEngine *VClusterOpEngine) runInstruction(
 
 		// execute an instruction
 		op.logExecute()
-		err = op.execute(execContext)
+		err = op.execute(ctx, execContext)
 		if err != nil {
 			// here we do not return an error as the spinner error does not
 			// affect the functionality
 			op.stopFailSpinner()
-			return fmt.Errorf("execute %s failed, details: %w", op.getName(), err)
+			// the op failed because its in-flight requests were aborted
+			if ctx.Err() != nil {
+				return &OpCanceledError{OpName: op.getName(), Err: ctx.Err()}
+			}
+			err = op.makeClusterOpError(fmt.Errorf("execute %s failed, details: %w", op.getName(), err))
+			return makePartialFailureProblem(op, err)
 		}
 	}
 
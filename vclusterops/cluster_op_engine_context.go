@@ -39,6 +39,12 @@ type opEngineExecContext struct {
 
 	// hosts on which the wrong authentication occurred
 	hostsWithWrongAuth []string
+
+	// the index of the op being run in the instructions of the engine
+	opIndex int
+	// the actions that revert the changes of the completed ops, in the
+	// order they were registered
+	undoActions []undoAction
 }
 
 func makeOpEngineExecContext(logger vlog.Printer) opEngineExecContext {
@@ -0,0 +1,313 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+const journalFilePerm = 0600
+
+// opJournal records the ops that a command has completed, in a local file,
+// so that the command can be resumed if it does not finish. A command can
+// run several op engines, and the journal has the completed ops of each of
+// them, in order.
+//
+// When a command is resumed, the completed ops that changed the cluster are
+// skipped. The read-only ops are run again, so that the state of the
+// cluster that the next ops rely on is validated and up to date.
+//
+// The journal of a command can be continued by the call of another command
+// that is a step of it, such as the add_node that adds the nodes of a new
+// subcluster. Each run records the command it belongs to.
+type opJournal struct {
+	Command string       `json:"command"`
+	DBName  string       `json:"db_name"`
+	Runs    []journalRun `json:"runs"`
+
+	// the file the journal is written to
+	path string
+	// whether the runs were loaded from the file of an earlier attempt
+	resuming bool
+	// the command of the call that records its runs in the journal
+	runCommand string
+	// the number of engine runs started by this attempt
+	runCount int
+}
+
+// journalRun is an op engine run of a command. The completed ops are the
+// first ones of the instructions of the engine.
+type journalRun struct {
+	// the command of the run, if it is not the one of the journal
+	Command string      `json:"command,omitempty"`
+	Ops     []journalOp `json:"ops"`
+}
+
+// journalOp is a completed op, with the state of the exec context after it
+type journalOp struct {
+	Name           string           `json:"name"`
+	ChangedCluster bool             `json:"changed_cluster"`
+	CompletedAt    time.Time        `json:"completed_at"`
+	State          journalExecState `json:"state"`
+}
+
+// journalExecState is the part of the exec context that the ops of a
+// command pass on to the next ones
+type journalExecState struct {
+	UpHosts                       []string                  `json:"up_hosts,omitempty"`
+	DefaultSCName                 string                    `json:"default_sc_name,omitempty"`
+	NetworkProfiles               map[string]networkProfile `json:"network_profiles,omitempty"`
+	HostsWithLatestCatalog        []string                  `json:"hosts_with_latest_catalog,omitempty"`
+	PrimaryHostsWithLatestCatalog []string                  `json:"primary_hosts_with_latest_catalog,omitempty"`
+	StartupCommandMap             map[string][]string       `json:"startup_command_map,omitempty"`
+}
+
+// startJournal makes the call record its completed ops in the journal file
+// of the options, if one is given. With ResumeFromJournal, the file must
+// have the journal of the same command on the same database, and the ops it
+// records are skipped.
+func (call *vclusterCall) startJournal(opt *DatabaseOptions, command string) error {
+	call.journal = nil
+	if opt.JournalPath == "" {
+		return nil
+	}
+
+	journal := &opJournal{Command: command, DBName: opt.DBName, path: opt.JournalPath, runCommand: command}
+	if opt.ResumeFromJournal {
+		resumedJournal, err := readJournal(opt.JournalPath)
+		if err != nil {
+			return fmt.Errorf("cannot resume from journal %s, %w", opt.JournalPath, err)
+		}
+		if resumedJournal.Command != command || resumedJournal.DBName != opt.DBName {
+			return fmt.Errorf("cannot resume from journal %s, it is the journal of %s on database %s",
+				opt.JournalPath, resumedJournal.Command, resumedJournal.DBName)
+		}
+		// the runs of the earlier attempt are kept until they are done again,
+		// in case this attempt stops before
+		journal.Runs = resumedJournal.Runs
+		journal.resuming = true
+	}
+	call.journal = journal
+	return journal.write()
+}
+
+// continueJournal makes the call of the command record its completed ops in
+// the journal that a call of journalCommand has started in the journal file
+// of the options. The runs of the command
+// are recorded after the ones of journalCommand. With ResumeFromJournal, the
+// ops that the runs of the command record are skipped.
+func (call *vclusterCall) continueJournal(opt *DatabaseOptions, command, journalCommand string) error {
+	call.journal = nil
+	if opt.JournalPath == "" {
+		return nil
+	}
+
+	journal, err := readJournal(opt.JournalPath)
+	if err != nil {
+		return fmt.Errorf("cannot continue journal %s, %w", opt.JournalPath, err)
+	}
+	if journal.Command != journalCommand || journal.DBName != opt.DBName {
+		return fmt.Errorf("cannot continue journal %s, it is the journal of %s on database %s",
+			opt.JournalPath, journal.Command, journal.DBName)
+	}
+	journal.path = opt.JournalPath
+	journal.resuming = opt.ResumeFromJournal
+	journal.runCommand = command
+	call.journal = journal
+	return nil
+}
+
+func readJournal(path string) (*opJournal, error) {
+	journalBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var journal opJournal
+	err = json.Unmarshal(journalBytes, &journal)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse the journal, details: %w", err)
+	}
+	return &journal, nil
+}
+
+// write replaces the journal file. The journal is written to a temporary
+// file first, so that the file is complete even if the command dies.
+func (journal *opJournal) write() error {
+	journalBytes, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal the journal, details: %w", err)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(journal.path), filepath.Base(journal.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("fail to create the journal file, details: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(journalBytes)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpFile.Name(), journalFilePerm)
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), journal.path)
+	}
+	if err != nil {
+		return fmt.Errorf("fail to write the journal file %s, details: %w", journal.path, err)
+	}
+	return nil
+}
+
+// isResuming returns true if the command is resumed from the journal of an
+// earlier attempt
+func (journal *opJournal) isResuming() bool {
+	return journal != nil && journal.resuming
+}
+
+// startRun returns the index of the run of an op engine that starts. It is
+// the next run of the command of the call, which is added if the journal
+// does not have it yet.
+func (journal *opJournal) startRun() int {
+	if journal == nil {
+		return 0
+	}
+	runCount := journal.runCount
+	journal.runCount++
+	for runIndex := range journal.Runs {
+		if journal.getRunCommand(runIndex) != journal.runCommand {
+			continue
+		}
+		if runCount == 0 {
+			return runIndex
+		}
+		runCount--
+	}
+	run := journalRun{}
+	if journal.runCommand != journal.Command {
+		run.Command = journal.runCommand
+	}
+	journal.Runs = append(journal.Runs, run)
+	return len(journal.Runs) - 1
+}
+
+// getRunCommand returns the command of the run at runIndex
+func (journal *opJournal) getRunCommand(runIndex int) string {
+	if journal.Runs[runIndex].Command == "" {
+		return journal.Command
+	}
+	return journal.Runs[runIndex].Command
+}
+
+// getResumedOp returns the op of the earlier attempt that completed the
+// instruction at opIndex, or nil if there is none. The instructions of the
+// command must be the ones recorded.
+func (journal *opJournal) getResumedOp(runIndex, opIndex int, op clusterOp) (*journalOp, error) {
+	if !journal.isResuming() {
+		return nil, nil
+	}
+	run := &journal.Runs[runIndex]
+	if opIndex >= len(run.Ops) {
+		return nil, nil
+	}
+	resumedOp := &run.Ops[opIndex]
+	if resumedOp.Name != op.getName() {
+		return nil, fmt.Errorf("cannot resume from journal %s, it has completed %s where the command runs %s",
+			journal.path, resumedOp.Name, op.getName())
+	}
+	return resumedOp, nil
+}
+
+// complete records that the instruction at opIndex has completed. A journal
+// that cannot be written does not fail the command, which can still finish.
+func (journal *opJournal) complete(runIndex, opIndex int, completedOp *journalOp, logger vlog.Printer) {
+	if journal == nil {
+		return
+	}
+	run := &journal.Runs[runIndex]
+	if opIndex < len(run.Ops) {
+		run.Ops[opIndex] = *completedOp
+	} else {
+		run.Ops = append(run.Ops, *completedOp)
+	}
+	err := journal.write()
+	if err != nil {
+		logger.PrintWarning("The progress of the command is not recorded, details: %s", err)
+	}
+}
+
+// undo records that the change of the op at opIndex has been reverted, so
+// that the op is run again if the command is resumed
+func (journal *opJournal) undo(runIndex, opIndex int, logger vlog.Printer) {
+	if journal == nil || opIndex >= len(journal.Runs[runIndex].Ops) {
+		return
+	}
+	journal.Runs[runIndex].Ops[opIndex].ChangedCluster = false
+	err := journal.write()
+	if err != nil {
+		logger.PrintWarning("The progress of the command is not recorded, details: %s", err)
+	}
+}
+
+// canSkip returns true if the op does not need to run again
+func (resumedOp *journalOp) canSkip() bool {
+	return resumedOp != nil && resumedOp.ChangedCluster
+}
+
+// makeJournalOp records the op and the state of the exec context after it
+func makeJournalOp(op clusterOp, execContext *opEngineExecContext) journalOp {
+	return journalOp{
+		Name:           op.getName(),
+		ChangedCluster: !op.isReadOnly(),
+		CompletedAt:    time.Now(),
+		State: journalExecState{
+			UpHosts:                       execContext.upHosts,
+			DefaultSCName:                 execContext.defaultSCName,
+			NetworkProfiles:               execContext.networkProfiles,
+			HostsWithLatestCatalog:        execContext.hostsWithLatestCatalog,
+			PrimaryHostsWithLatestCatalog: execContext.primaryHostsWithLatestCatalog,
+			StartupCommandMap:             execContext.startupCommandMap,
+		},
+	}
+}
+
+// restore sets the state that a skipped op would have set. The state that
+// the ops run again have already set is more recent, so it is kept.
+func (state *journalExecState) restore(execContext *opEngineExecContext) {
+	if len(execContext.upHosts) == 0 {
+		execContext.upHosts = state.UpHosts
+	}
+	if execContext.defaultSCName == "" {
+		execContext.defaultSCName = state.DefaultSCName
+	}
+	if len(execContext.networkProfiles) == 0 {
+		execContext.networkProfiles = state.NetworkProfiles
+	}
+	if len(execContext.hostsWithLatestCatalog) == 0 {
+		execContext.hostsWithLatestCatalog = state.HostsWithLatestCatalog
+	}
+	if len(execContext.primaryHostsWithLatestCatalog) == 0 {
+		execContext.primaryHostsWithLatestCatalog = state.PrimaryHostsWithLatestCatalog
+	}
+	if len(execContext.startupCommandMap) == 0 {
+		execContext.startupCommandMap = state.StartupCommandMap
+	}
+}
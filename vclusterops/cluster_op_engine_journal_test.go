@@ -0,0 +1,215 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// mockJournalOp is a mock op that sets the default subcluster name in the
+// exec context, and that fails if it is told to
+type mockJournalOp struct {
+	mockRequestOp
+	fail bool
+}
+
+func (m *mockJournalOp) execute(_ context.Context, execContext *opEngineExecContext) error {
+	m.calledExecute = true
+	if m.fail {
+		return fmt.Errorf("the host is unreachable")
+	}
+	if m.method != GetMethod {
+		execContext.defaultSCName = "default_subcluster"
+	}
+	return nil
+}
+
+func makeMockJournalOps(failLastOp bool) []*mockJournalOp {
+	ops := []*mockJournalOp{
+		{mockRequestOp: mockRequestOp{mockOp: makeMockOp(false), method: GetMethod}},
+		{mockRequestOp: mockRequestOp{mockOp: makeMockOp(false), method: PostMethod}},
+		{mockRequestOp: mockRequestOp{mockOp: makeMockOp(false), method: PostMethod}, fail: failLastOp},
+	}
+	for i, name := range []string{"GetUpNodesOp", "AddSubclusterOp", "CheckSubclusterOp"} {
+		ops[i].name = name
+	}
+	return ops
+}
+
+func runMockJournalOps(ctx context.Context, options *DatabaseOptions, ops []*mockJournalOp) (VClusterOpEngine, error) {
+	var instructions []clusterOp
+	for _, op := range ops {
+		instructions = append(instructions, op)
+	}
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err := clusterOpEngine.run(ctx, vlog.Printer{})
+	return clusterOpEngine, err
+}
+
+func TestResumeFromJournal(t *testing.T) {
+	options := DatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.JournalPath = filepath.Join(t.TempDir(), "add_subcluster.journal")
+
+	// the command stops at its last op
+	ctx, call := startTestCall(context.Background(), commandAddCluster, &options)
+	err := call.startJournal(&options, commandAddCluster)
+	assert.NoError(t, err)
+	_, err = runMockJournalOps(ctx, &options, makeMockJournalOps(true))
+	assert.ErrorContains(t, err, "the host is unreachable")
+	journal, err := readJournal(options.JournalPath)
+	assert.NoError(t, err)
+	assert.Equal(t, commandAddCluster, journal.Command)
+	assert.Len(t, journal.Runs, 1)
+	assert.Len(t, journal.Runs[0].Ops, 2)
+	assert.False(t, journal.Runs[0].Ops[0].ChangedCluster)
+	assert.True(t, journal.Runs[0].Ops[1].ChangedCluster)
+
+	// the resumed command runs the read-only op again, skips the op that
+	// changed the cluster, and restores the state that op had set
+	options.ResumeFromJournal = true
+	ctx, call = startTestCall(context.Background(), commandAddCluster, &options)
+	err = call.startJournal(&options, commandAddCluster)
+	assert.NoError(t, err)
+	ops := makeMockJournalOps(false)
+	clusterOpEngine, err := runMockJournalOps(ctx, &options, ops)
+	assert.NoError(t, err)
+	assert.True(t, ops[0].calledExecute)
+	assert.False(t, ops[1].calledPrepare)
+	assert.False(t, ops[1].calledExecute)
+	assert.True(t, ops[2].calledExecute)
+	assert.Equal(t, "default_subcluster", clusterOpEngine.execContext.defaultSCName)
+	assert.Equal(t, SkippedResult, call.report.Ops[1].Status)
+	journal, err = readJournal(options.JournalPath)
+	assert.NoError(t, err)
+	assert.Len(t, journal.Runs[0].Ops, 3)
+}
+
+func TestResumeFromMismatchedJournal(t *testing.T) {
+	options := DatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.JournalPath = filepath.Join(t.TempDir(), "add_subcluster.journal")
+	ctx, call := startTestCall(context.Background(), commandAddCluster, &options)
+	err := call.startJournal(&options, commandAddCluster)
+	assert.NoError(t, err)
+	_, err = runMockJournalOps(ctx, &options, makeMockJournalOps(true))
+	assert.Error(t, err)
+
+	// the journal of another command or database cannot be resumed
+	options.ResumeFromJournal = true
+	err = call.startJournal(&options, commandReviveDB)
+	assert.ErrorContains(t, err, "it is the journal of db_add_subcluster on database test_db")
+	options.DBName = "other_db"
+	err = call.startJournal(&options, commandAddCluster)
+	assert.ErrorContains(t, err, "it is the journal of db_add_subcluster on database test_db")
+
+	// nor can the journal of other instructions
+	options.DBName = "test_db"
+	err = call.startJournal(&options, commandAddCluster)
+	assert.NoError(t, err)
+	ops := makeMockJournalOps(false)
+	ops[1].name = "RemoveSubclusterOp"
+	_, err = runMockJournalOps(ctx, &options, ops)
+	assert.ErrorContains(t, err, "it has completed AddSubclusterOp where the command runs RemoveSubclusterOp")
+	assert.False(t, ops[1].calledExecute)
+
+	// a missing journal cannot be resumed either
+	options.JournalPath = filepath.Join(t.TempDir(), "missing.journal")
+	err = call.startJournal(&options, commandAddCluster)
+	assert.ErrorContains(t, err, "cannot resume from journal")
+}
+
+func TestJournalOfNextCall(t *testing.T) {
+	options := DatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.JournalPath = filepath.Join(t.TempDir(), "add_subcluster.journal")
+	ctx, call := startTestCall(context.Background(), commandAddCluster, &options)
+	err := call.startJournal(&options, commandAddCluster)
+	assert.NoError(t, err)
+	_, err = runMockJournalOps(ctx, &options, makeMockJournalOps(false))
+	assert.NoError(t, err)
+	call.finish(nil)
+
+	// the next call given the same options does not record its ops in the
+	// journal, unless it continues it
+	ctx, call = startTestCall(context.Background(), commandAddNode, &options)
+	assert.Nil(t, call.journal)
+	err = call.continueJournal(&options, commandAddNode, commandReviveDB)
+	assert.ErrorContains(t, err, "it is the journal of db_add_subcluster on database test_db")
+	err = call.continueJournal(&options, commandAddNode, commandAddCluster)
+	assert.NoError(t, err)
+	_, err = runMockJournalOps(ctx, &options, makeMockJournalOps(true))
+	assert.Error(t, err)
+	call.finish(err)
+	journal, err := readJournal(options.JournalPath)
+	assert.NoError(t, err)
+	assert.Len(t, journal.Runs, 2)
+	assert.Empty(t, journal.Runs[0].Command)
+	assert.Equal(t, commandAddNode, journal.Runs[1].Command)
+	assert.Len(t, journal.Runs[1].Ops, 2)
+
+	// when the command is resumed, each call skips the ops of its own runs
+	options.ResumeFromJournal = true
+	ctx, call = startTestCall(context.Background(), commandAddCluster, &options)
+	err = call.startJournal(&options, commandAddCluster)
+	assert.NoError(t, err)
+	_, err = runMockJournalOps(ctx, &options, makeMockJournalOps(false))
+	assert.NoError(t, err)
+	call.finish(nil)
+	ctx, call = startTestCall(context.Background(), commandAddNode, &options)
+	err = call.continueJournal(&options, commandAddNode, commandAddCluster)
+	assert.NoError(t, err)
+	assert.True(t, call.journal.isResuming())
+	ops := makeMockJournalOps(false)
+	_, err = runMockJournalOps(ctx, &options, ops)
+	assert.NoError(t, err)
+	assert.False(t, ops[1].calledExecute)
+	assert.True(t, ops[2].calledExecute)
+	journal, err = readJournal(options.JournalPath)
+	assert.NoError(t, err)
+	assert.Len(t, journal.Runs, 2)
+	assert.Len(t, journal.Runs[1].Ops, 3)
+}
+
+func TestGetHostsToCreate(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Name: "v_test_db_node0001", Address: "192.168.1.101",
+		Subcluster: "default_subcluster"}
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Name: "v_test_db_node0002", Address: "192.168.1.102",
+		Subcluster: "sc1"}
+	options := VAddNodeOptionsFactory()
+	options.SCName = "sc1"
+	options.NewHosts = []string{"192.168.1.102", "192.168.1.103"}
+
+	// the new nodes cannot exist
+	_, err := options.getHostsToCreate(&vdb, false)
+	assert.ErrorContains(t, err, "192.168.1.102 already exist in the database")
+
+	// unless they were created in the subcluster by the resumed attempt
+	hosts, err := options.getHostsToCreate(&vdb, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.103"}, hosts)
+	options.NewHosts = []string{"192.168.1.101"}
+	_, err = options.getHostsToCreate(&vdb, true)
+	assert.ErrorContains(t, err, "192.168.1.101 already exist in the database")
+}
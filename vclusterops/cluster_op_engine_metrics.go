@@ -0,0 +1,100 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/metrics"
+)
+
+// noStatusCode is the status code label of the requests that got no
+// response, such as the ones to a host that is unreachable
+const noStatusCode = "none"
+
+// opMetrics records the metrics of the VClusterCommands calls in the
+// registry of VClusterCommands. A nil opMetrics records nothing.
+type opMetrics struct {
+	commandDuration   *metrics.Histogram
+	opDuration        *metrics.Histogram
+	requests          *metrics.Counter
+	retries           *metrics.Counter
+	pollingIterations *metrics.Counter
+}
+
+func makeOpMetrics(registry *metrics.Registry) *opMetrics {
+	if registry == nil {
+		return nil
+	}
+	return &opMetrics{
+		commandDuration: registry.Histogram("vcluster_command_duration_seconds",
+			"Duration of the vcluster commands.", metrics.DefaultDurationBuckets, "command", "database", "status"),
+		opDuration: registry.Histogram("vcluster_op_duration_seconds",
+			"Duration of the operations run by the vcluster commands.", metrics.DefaultDurationBuckets, "op", "status"),
+		requests: registry.Counter("vcluster_http_requests_total",
+			"Requests sent to the hosts, by endpoint and status code.", "endpoint", "method", "code"),
+		retries: registry.Counter("vcluster_http_request_retries_total",
+			"Requests sent again to a host after a retryable failure.", "endpoint", "method"),
+		pollingIterations: registry.Counter("vcluster_polling_iterations_total",
+			"Times the operations have polled the state of the hosts.", "op"),
+	}
+}
+
+func (m *opMetrics) observeCommand(command, dbName string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	status := SuccessResult
+	if err != nil {
+		status = FailureResult
+	}
+	m.commandDuration.Observe(duration.Seconds(), command, dbName, status)
+}
+
+// observeOp records the duration of an op that has been run. The ops that
+// were skipped or planned are not recorded.
+func (m *opMetrics) observeOp(op clusterOp, startTime time.Time, status string) {
+	if m == nil || status == SkippedResult || status == PlannedResult {
+		return
+	}
+	m.opDuration.Observe(time.Since(startTime).Seconds(), op.getName(), status)
+}
+
+func (m *opMetrics) countRequest(request *hostHTTPRequest, result *hostHTTPResult) {
+	if m == nil {
+		return
+	}
+	code := noStatusCode
+	if result.statusCode != 0 {
+		code = strconv.Itoa(result.statusCode)
+	}
+	m.requests.Inc(request.getEndpointTemplate(), request.Method, code)
+}
+
+func (m *opMetrics) countRetry(request *hostHTTPRequest) {
+	if m == nil {
+		return
+	}
+	m.retries.Inc(request.getEndpointTemplate(), request.Method)
+}
+
+func (m *opMetrics) countPolling(poller statePoller) {
+	if m == nil {
+		return
+	}
+	m.pollingIterations.Inc(poller.getName())
+}
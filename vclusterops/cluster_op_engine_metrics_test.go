@@ -0,0 +1,71 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+	"github.com/vertica/vcluster/vclusterops/metrics"
+)
+
+func TestMetricsOnFakeCluster(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+	vcc.Metrics = metrics.NewRegistry()
+	createFakeClusterDatabase(t, vcc, cluster)
+
+	var text strings.Builder
+	assert.NoError(t, vcc.Metrics.WriteText(&text))
+	output := text.String()
+	assert.Contains(t, output,
+		`vcluster_command_duration_seconds_count{command="create_db",database="test_db",status="SUCCESS"} 1`)
+	// create_db polls the state of the nodes twice
+	assert.Contains(t, output, `vcluster_op_duration_seconds_count{op="HTTPSPollNodeStateOp",status="SUCCESS"} 2`)
+	assert.Contains(t, output, `vcluster_op_duration_seconds_count{op="NMABootstrapCatalogOp",status="SUCCESS"} 1`)
+	assert.Contains(t, output, `vcluster_http_requests_total{endpoint="v1/health",method="GET",code="200"} 3`)
+	// the requests to an endpoint that has the host in its path are counted together
+	assert.Contains(t, output, `vcluster_http_requests_total{endpoint="v1/nodes/{host}",method="GET",code="200"}`)
+	assert.NotContains(t, output, `endpoint="v1/nodes/`+fakeClusterHosts[0])
+	assert.Contains(t, output, `vcluster_polling_iterations_total{op="HTTPSPollNodeStateOp"}`)
+	assert.NotContains(t, output, "vcluster_http_request_retries_total{")
+}
+
+func TestNoMetricsWithoutRegistry(t *testing.T) {
+	var opMetrics *opMetrics
+	assert.Nil(t, makeOpMetrics(nil))
+	// a nil opMetrics records nothing, and does not panic
+	opMetrics.countRetry(&hostHTTPRequest{})
+	opMetrics.observeCommand(commandCreateDB, "test_db", 0, nil)
+}
+
+func TestBuildEndpointFromTemplate(t *testing.T) {
+	var request hostHTTPRequest
+	request.buildHTTPSEndpoint("subclusters/{subcluster}/drop", "sc1")
+	assert.Equal(t, "v1/subclusters/sc1/drop", request.Endpoint)
+	assert.Equal(t, "v1/subclusters/{subcluster}/drop", request.getEndpointTemplate())
+
+	request.buildNMAEndpoint("scrutinize/{id}/{node}/normal/files", "VerticaScrutinize.20240101", "v_db_node0001")
+	assert.Equal(t, "v1/scrutinize/VerticaScrutinize.20240101/v_db_node0001/normal/files", request.Endpoint)
+	assert.Equal(t, "v1/scrutinize/{id}/{node}/normal/files", request.getEndpointTemplate())
+
+	// the endpoint of a request that was not built from a template is its template
+	request = hostHTTPRequest{Endpoint: "nodes"}
+	assert.Equal(t, "nodes", request.getEndpointTemplate())
+}
@@ -0,0 +1,183 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OpPlan describes what an op would do if it was run
+type OpPlan struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Hosts       []string      `json:"hosts"`
+	Requests    []RequestPlan `json:"requests,omitempty"`
+	// Note explains why the requests of the op are not fully known, which
+	// happens when they depend on the results of earlier planned ops
+	Note string `json:"note,omitempty"`
+}
+
+// RequestPlan describes an HTTP request that an op would send to a host.
+// The values of sensitive fields in the body are masked.
+type RequestPlan struct {
+	Host        string            `json:"host"`
+	Method      string            `json:"method"`
+	Endpoint    string            `json:"endpoint"`
+	QueryParams map[string]string `json:"query_params,omitempty"`
+	Body        string            `json:"body,omitempty"`
+}
+
+// DryRunPlan is returned as an error by the VClusterCommands APIs when
+// DatabaseOptions.DryRun is set and the command reaches an op that would
+// change the database. It lists that op and the ones after it, which are
+// planned and not run. The read-only ops before it are run, as they
+// discover the state of the cluster that the plan is built on.
+type DryRunPlan struct {
+	Ops []OpPlan `json:"ops"`
+}
+
+func (plan *DryRunPlan) Error() string {
+	return fmt.Sprintf("dry run: %d operations were planned and not run", len(plan.Ops))
+}
+
+// merge appends the ops planned by a later engine run of the same command.
+// It returns err if that run failed for a reason other than dry run.
+func (plan *DryRunPlan) merge(err error) error {
+	var laterPlan *DryRunPlan
+	if errors.As(err, &laterPlan) {
+		plan.Ops = append(plan.Ops, laterPlan.Ops...)
+		return plan
+	}
+	if err != nil {
+		return err
+	}
+	return plan
+}
+
+// String returns a human-readable description of the plan
+func (plan *DryRunPlan) String() string {
+	var sb strings.Builder
+	for i := range plan.Ops {
+		op := &plan.Ops[i]
+		fmt.Fprintf(&sb, "%d. %s", i+1, op.Name)
+		if op.Description != "" {
+			fmt.Fprintf(&sb, " (%s)", op.Description)
+		}
+		fmt.Fprintf(&sb, "\n   hosts: %s\n", strings.Join(op.Hosts, ", "))
+		for _, req := range op.Requests {
+			fmt.Fprintf(&sb, "   %s %s on %s", req.Method, req.Endpoint, req.Host)
+			if req.Body != "" {
+				fmt.Fprintf(&sb, " with body %s", req.Body)
+			}
+			sb.WriteString("\n")
+		}
+		if op.Note != "" {
+			fmt.Fprintf(&sb, "   note: %s\n", op.Note)
+		}
+	}
+	return sb.String()
+}
+
+// isReadOnly returns true if the op only sends GET requests, or if it is
+// known not to change the cluster with the other ones it sends
+func (op *opBase) isReadOnly() bool {
+	if op.readsOnly {
+		return true
+	}
+	for host := range op.clusterHTTPRequest.RequestCollection {
+		if op.clusterHTTPRequest.RequestCollection[host].Method != GetMethod {
+			return false
+		}
+	}
+	return true
+}
+
+// getPlan describes the requests that the op has prepared
+func (op *opBase) getPlan() OpPlan {
+	plan := OpPlan{Name: op.name, Description: op.description, Hosts: op.hosts}
+	requestHosts := make([]string, 0, len(op.clusterHTTPRequest.RequestCollection))
+	for host := range op.clusterHTTPRequest.RequestCollection {
+		requestHosts = append(requestHosts, host)
+	}
+	sort.Strings(requestHosts)
+	for _, host := range requestHosts {
+		request := op.clusterHTTPRequest.RequestCollection[host]
+		plan.Requests = append(plan.Requests, RequestPlan{
+			Host:        host,
+			Method:      request.Method,
+			Endpoint:    request.Endpoint,
+			QueryParams: request.QueryParams,
+			Body:        maskRequestBody(request.RequestData),
+		})
+	}
+	if len(plan.Hosts) == 0 {
+		plan.Hosts = requestHosts
+	}
+	return plan
+}
+
+// the values of the request body fields whose names contain one of these
+// words are masked in the plan, and in the debug log
+var sensitiveFieldWords = []string{"password", "secret", "key", "token", "auth", "credential", "security"}
+
+// maskRequestBody masks the values of sensitive fields at any level
+// of a JSON request body, or response body. A body that is not JSON is
+// masked entirely.
+func maskRequestBody(requestData string) string {
+	const maskedValue = "******"
+	if requestData == "" {
+		return ""
+	}
+	var body any
+	if err := json.Unmarshal([]byte(requestData), &body); err != nil {
+		return maskedValue
+	}
+	maskedBody, err := json.Marshal(maskSensitiveValues(body, maskedValue))
+	if err != nil {
+		return maskedValue
+	}
+	return string(maskedBody)
+}
+
+func maskSensitiveValues(value any, maskedValue string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for field, fieldValue := range v {
+			fieldLowerCase := strings.ToLower(field)
+			sensitive := false
+			for _, word := range sensitiveFieldWords {
+				if strings.Contains(fieldLowerCase, word) {
+					sensitive = true
+					break
+				}
+			}
+			if sensitive {
+				v[field] = maskedValue
+			} else {
+				v[field] = maskSensitiveValues(fieldValue, maskedValue)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = maskSensitiveValues(v[i], maskedValue)
+		}
+	}
+	return value
+}
@@ -0,0 +1,165 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// ProgressEventType is the kind of a ProgressEvent
+type ProgressEventType string
+
+const (
+	// OpStartedEvent is sent when an op starts
+	OpStartedEvent ProgressEventType = "OP_STARTED"
+	// OpFinishedEvent is sent when an op has finished, with its Status, and
+	// its Err if it failed
+	OpFinishedEvent ProgressEventType = "OP_FINISHED"
+	// HostResultEvent is sent when the request of an op to a host has a
+	// result, with the Status of that result
+	HostResultEvent ProgressEventType = "HOST_RESULT"
+	// PollingEvent is sent after each time an op has polled the state of the
+	// nodes, with the number of nodes that are up and down
+	PollingEvent ProgressEventType = "POLLING"
+	// DownloadEvent is sent as a file is downloaded from a host, with the
+	// number of bytes downloaded so far
+	DownloadEvent ProgressEventType = "DOWNLOAD"
+)
+
+// downloadEventInterval is the minimum time between two DownloadEvents of a
+// file, so that a fast download does not send an event per buffer
+const downloadEventInterval = time.Second
+
+// ProgressEvent tells what a VClusterCommands call is doing. The fields that
+// are not about the Type of the event are left empty.
+type ProgressEvent struct {
+	Type ProgressEventType
+	Time time.Time
+	// the name and the description of the op the event is about, empty for
+	// a DownloadEvent
+	OpName        string
+	OpDescription string
+	// the host of a HostResultEvent or a DownloadEvent
+	Host string
+	// SUCCESS, FAILURE, SKIPPED or PLANNED for an OpFinishedEvent, and
+	// SUCCESS, FAILURE or EXCEPTION for a HostResultEvent
+	Status string
+	Err    error
+	// the polling iteration of a PollingEvent, starting at 1, how long the
+	// op has been polling, and how long it waits before the next poll
+	Iteration  int
+	Elapsed    time.Duration
+	NextPollIn time.Duration
+	UpNodes    int
+	DownNodes  int
+	// the file of a DownloadEvent, and the bytes written to it so far
+	FilePath        string
+	BytesDownloaded int64
+}
+
+// ProgressHandler receives the progress events of the VClusterCommands calls.
+// The events of a call are sent one at a time, in order, from the goroutines
+// of the call. The handler should return quickly, as the call waits for it.
+type ProgressHandler func(event ProgressEvent)
+
+// progressReporter sends the events of a call to its handler, one at a
+// time. A nil reporter sends nothing.
+type progressReporter struct {
+	mu      sync.Mutex
+	handler ProgressHandler
+}
+
+func makeProgressReporter(handler ProgressHandler) *progressReporter {
+	if handler == nil {
+		return nil
+	}
+	return &progressReporter{handler: handler}
+}
+
+func (reporter *progressReporter) send(event ProgressEvent) {
+	if reporter == nil {
+		return
+	}
+	event.Time = time.Now()
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	reporter.handler(event)
+}
+
+func (reporter *progressReporter) opStarted(op clusterOp) {
+	if reporter == nil {
+		return
+	}
+	reporter.send(ProgressEvent{Type: OpStartedEvent, OpName: op.getName(), OpDescription: op.getDescription()})
+}
+
+func (reporter *progressReporter) opFinished(op clusterOp, status string, err error) {
+	if reporter == nil {
+		return
+	}
+	reporter.send(ProgressEvent{Type: OpFinishedEvent, OpName: op.getName(), OpDescription: op.getDescription(),
+		Status: status, Err: err})
+}
+
+func (reporter *progressReporter) hostResult(opName string, result *hostHTTPResult) {
+	reporter.send(ProgressEvent{Type: HostResultEvent, OpName: opName, Host: result.host,
+		Status: result.status.getStatusString(), Err: result.err})
+}
+
+// nodeStateCounter is a statePoller that counts the nodes that are up and
+// down each time it polls
+type nodeStateCounter interface {
+	getNodeStateCounts() (upNodes, downNodes int)
+}
+
+func (reporter *progressReporter) polling(poller statePoller, iteration int, elapsed, nextPollIn time.Duration) {
+	if reporter == nil {
+		return
+	}
+	event := ProgressEvent{Type: PollingEvent, OpName: poller.getName(), Iteration: iteration,
+		Elapsed: elapsed, NextPollIn: nextPollIn}
+	if counter, ok := poller.(nodeStateCounter); ok {
+		event.UpNodes, event.DownNodes = counter.getNodeStateCounts()
+	}
+	reporter.send(event)
+}
+
+// downloadProgressWriter sends DownloadEvents as a file is written
+type downloadProgressWriter struct {
+	writer          io.Writer
+	reporter        *progressReporter
+	host            string
+	filePath        string
+	bytesDownloaded int64
+	lastEventTime   time.Time
+}
+
+func (w *downloadProgressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.bytesDownloaded += int64(n)
+	if time.Since(w.lastEventTime) >= downloadEventInterval {
+		w.sendEvent()
+	}
+	return n, err
+}
+
+func (w *downloadProgressWriter) sendEvent() {
+	w.lastEventTime = time.Now()
+	w.reporter.send(ProgressEvent{Type: DownloadEvent, Host: w.host, FilePath: w.filePath,
+		BytesDownloaded: w.bytesDownloaded})
+}
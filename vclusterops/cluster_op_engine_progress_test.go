@@ -0,0 +1,97 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+)
+
+func TestProgressEventsOnFakeCluster(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+	var events []ProgressEvent
+	vcc.OnProgress = func(event ProgressEvent) {
+		events = append(events, event)
+	}
+	createFakeClusterDatabase(t, vcc, cluster)
+
+	var startedOps, finishedOps int
+	hostResults := make(map[string]int)
+	var lastPolling *ProgressEvent
+	for i := range events {
+		event := &events[i]
+		assert.False(t, event.Time.IsZero())
+		switch event.Type {
+		case OpStartedEvent:
+			startedOps++
+			assert.NotEmpty(t, event.OpDescription)
+		case OpFinishedEvent:
+			finishedOps++
+			assert.Equal(t, SuccessResult, event.Status)
+		case HostResultEvent:
+			hostResults[event.Host]++
+			assert.NotEmpty(t, event.OpName)
+		case PollingEvent:
+			if event.OpName == "HTTPSPollNodeStateOp" {
+				lastPolling = event
+			}
+		}
+	}
+	// each op that starts finishes, and the events of an op are in between
+	assert.Positive(t, startedOps)
+	assert.Equal(t, startedOps, finishedOps)
+	assert.Equal(t, OpStartedEvent, events[0].Type)
+	assert.Equal(t, OpFinishedEvent, events[len(events)-1].Type)
+	for _, host := range fakeClusterHosts {
+		assert.Positive(t, hostResults[host])
+	}
+	// the last polling of the nodes found them all up
+	if assert.NotNil(t, lastPolling) {
+		assert.Positive(t, lastPolling.Iteration)
+		assert.Equal(t, len(fakeClusterHosts), lastPolling.UpNodes)
+		assert.Equal(t, 0, lastPolling.DownNodes)
+	}
+}
+
+func TestDownloadProgressEvents(t *testing.T) {
+	var events []ProgressEvent
+	progress := makeProgressReporter(func(event ProgressEvent) {
+		events = append(events, event)
+	})
+	destFilePath := filepath.Join(t.TempDir(), "cluster_config.json")
+	downloader := responseBodyDownloader{destFilePath: destFilePath, progress: progress, host: "host1"}
+	content := strings.Repeat("x", 100000)
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(content))}
+
+	_, err := downloader.processResponseBody(resp)
+	assert.NoError(t, err)
+	fileBytes, err := os.ReadFile(destFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(fileBytes))
+
+	// a fast download sends a single event once the file is written
+	assert.Equal(t, []ProgressEvent{{Type: DownloadEvent, Time: events[0].Time, Host: "host1",
+		FilePath: destFilePath, BytesDownloaded: int64(len(content))}}, events)
+}
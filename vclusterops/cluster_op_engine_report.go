@@ -0,0 +1,163 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"sort"
+	"time"
+)
+
+// the status of an op in the execution report, in addition to
+// SuccessResult and FailureResult
+const (
+	SkippedResult = "SKIPPED"
+	PlannedResult = "PLANNED"
+)
+
+// ExecutionReport describes how the ops of a VClusterCommands call were run.
+// It is returned by the VClusterCommands APIs, whether they succeed or not.
+type ExecutionReport struct {
+	StartTime time.Time     `json:"start_time"`
+	EndTime   time.Time     `json:"end_time"`
+	Duration  time.Duration `json:"duration_ns"`
+	Status    string        `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	Ops       []OpReport    `json:"ops"`
+	// the actions run to undo the changes of the ops, after one failed
+	Undo []UndoReport `json:"undo,omitempty"`
+}
+
+// OpReport describes how an op was run
+type OpReport struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	StartTime   time.Time          `json:"start_time"`
+	EndTime     time.Time          `json:"end_time"`
+	Duration    time.Duration      `json:"duration_ns"`
+	Hosts       []string           `json:"hosts"`
+	HostResults []HostResultReport `json:"host_results,omitempty"`
+	Status      string             `json:"status"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// HostResultReport describes the result of the request an op sent to a host.
+// It is the result of the last attempt if the request was retried, and the
+// errors of the attempts before it are in RetriedErrors.
+type HostResultReport struct {
+	Host          string   `json:"host"`
+	StatusCode    int      `json:"status_code"`
+	Status        string   `json:"status"`
+	Error         string   `json:"error,omitempty"`
+	Attempts      int      `json:"attempts"`
+	RetriedErrors []string `json:"retried_errors,omitempty"`
+}
+
+var resultStatusNames = map[resultStatus]string{
+	SUCCESS:   "SUCCESS",
+	FAILURE:   "FAILURE",
+	EXCEPTION: "EXCEPTION",
+}
+
+// startReport starts a new execution report, which the op engines of a
+// call add their ops to
+func startReport() *ExecutionReport {
+	return &ExecutionReport{StartTime: time.Now()}
+}
+
+// finish sets the end time and the final status of the report
+func (report *ExecutionReport) finish(err error) {
+	report.EndTime = time.Now()
+	report.Duration = report.EndTime.Sub(report.StartTime)
+	report.Status = SuccessResult
+	report.Error = ""
+	if err != nil {
+		report.Status = FailureResult
+		report.Error = err.Error()
+	}
+}
+
+// addUndo adds an undo action that was run, or skipped, after an op failed
+func (report *ExecutionReport) addUndo(description, status string, err error) {
+	if report == nil {
+		return
+	}
+	undoReport := UndoReport{Description: description, Status: status}
+	if err != nil {
+		undoReport.Error = err.Error()
+	}
+	report.Undo = append(report.Undo, undoReport)
+}
+
+// addOp adds an op that was run from startTime, with the given status and error
+func (report *ExecutionReport) addOp(op clusterOp, startTime time.Time, status string, err error) {
+	if report == nil {
+		return
+	}
+	opReport := op.getReport()
+	opReport.StartTime = startTime
+	opReport.EndTime = time.Now()
+	opReport.Duration = opReport.EndTime.Sub(startTime)
+	opReport.Status = status
+	if err != nil {
+		opReport.Error = err.Error()
+	}
+	// the results are not the ones of this run if it did not send the requests
+	if status == SkippedResult || status == PlannedResult {
+		opReport.HostResults = nil
+	}
+	report.Ops = append(report.Ops, opReport)
+}
+
+// getReport describes the op and the results of the requests it sent
+func (op *opBase) getReport() OpReport {
+	opReport := OpReport{Name: op.name, Description: op.description, Hosts: op.hosts}
+	opReport.HostResults = op.getHostResults()
+	if len(opReport.Hosts) == 0 {
+		opReport.Hosts = make([]string, 0, len(opReport.HostResults))
+		for _, hostResult := range opReport.HostResults {
+			opReport.Hosts = append(opReport.Hosts, hostResult.Host)
+		}
+	}
+	return opReport
+}
+
+// getHostResults returns the results of the requests the op sent, in the
+// order of the hosts
+func (op *opBase) getHostResults() []HostResultReport {
+	resultHosts := make([]string, 0, len(op.clusterHTTPRequest.ResultCollection))
+	for host := range op.clusterHTTPRequest.ResultCollection {
+		resultHosts = append(resultHosts, host)
+	}
+	sort.Strings(resultHosts)
+	var hostResults []HostResultReport
+	for _, host := range resultHosts {
+		result := op.clusterHTTPRequest.ResultCollection[host]
+		hostResult := HostResultReport{
+			Host:       host,
+			StatusCode: result.statusCode,
+			Status:     resultStatusNames[result.status],
+			Attempts:   result.attempts,
+		}
+		if result.err != nil {
+			hostResult.Error = result.err.Error()
+		}
+		for _, retriedErr := range result.retriedErrs {
+			hostResult.RetriedErrors = append(hostResult.RetriedErrors, retriedErr.Error())
+		}
+		hostResults = append(hostResults, hostResult)
+	}
+	return hostResults
+}
@@ -0,0 +1,186 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// lastStageID is the ID of the last stage made by runConcurrently
+var lastStageID atomic.Int64
+
+// runConcurrently groups the ops into a stage that the engine runs at the
+// same time. The ops of a stage must not depend on the results of each
+// other, and they must not set the same state in the exec context.
+func runConcurrently(ops ...clusterOp) []clusterOp {
+	stageID := lastStageID.Add(1)
+	for _, op := range ops {
+		op.setStageID(stageID)
+	}
+	return ops
+}
+
+// getStageEnd returns the index after the last instruction of the stage
+// that starts at the given index. The ops are planned one at a time in a
+// dry run, so a dry run has no concurrent stage.
+func (opEngine *VClusterOpEngine) getStageEnd(start int) int {
+	end := start + 1
+	stageID := opEngine.instructions[start].getStageID()
+	if stageID == 0 || opEngine.dryRun {
+		return end
+	}
+	for end < len(opEngine.instructions) && opEngine.instructions[end].getStageID() == stageID {
+		end++
+	}
+	return end
+}
+
+// stageOpResult is the outcome of an op of a concurrent stage
+type stageOpResult struct {
+	startTime time.Time
+	skipped   bool
+	err       error
+}
+
+// runConcurrentStage runs the instructions from start to end at the same
+// time. Each op has its own copy of the exec context, and the state the
+// ops have set is merged back once they all have finished. The ops are
+// reported and recorded in the journal in the order of the instructions.
+func (opEngine *VClusterOpEngine) runConcurrentStage(ctx context.Context, logger vlog.Printer,
+	execContext *opEngineExecContext, findCertsInOptions bool, journalRunIndex, start, end int) error {
+	stage := opEngine.instructions[start:end]
+	results := make([]stageOpResult, len(stage))
+	opContexts := make([]*opEngineExecContext, len(stage))
+	for i, op := range stage {
+		opContexts[i] = execContext.copyForConcurrentOp(start + i)
+		results[i].startTime = time.Now()
+		skipped, err := opEngine.resumeInstruction(logger, opContexts[i], journalRunIndex, start+i, op)
+		if err != nil {
+			return err
+		}
+		results[i].skipped = skipped
+	}
+
+	var wg sync.WaitGroup
+	for i, op := range stage {
+		if results[i].skipped {
+			continue
+		}
+		opEngine.progress.opStarted(op)
+		wg.Add(1)
+		go func(op clusterOp, opContext *opEngineExecContext, result *stageOpResult) {
+			defer wg.Done()
+			result.err = opEngine.runInstruction(ctx, logger, opContext, op, findCertsInOptions)
+		}(op, opContexts[i], &results[i])
+	}
+	wg.Wait()
+
+	base := *execContext
+	for i := range stage {
+		execContext.mergeConcurrentOp(&base, opContexts[i], results[i].err == nil)
+	}
+
+	// the ops after the first one that failed are not recorded in the
+	// journal, so that the recorded ops stay the first ones of the run
+	var stageErr error
+	for i, op := range stage {
+		result := &results[i]
+		if result.skipped {
+			opEngine.finishOp(op, result.startTime, SkippedResult, nil)
+			continue
+		}
+		status := opEngine.getInstructionStatus(op, result.err)
+		opEngine.finishOp(op, result.startTime, status, result.err)
+		if result.err != nil {
+			if stageErr == nil {
+				stageErr = result.err
+			}
+			continue
+		}
+		if stageErr == nil && status != PlannedResult {
+			journalOp := makeJournalOp(op, execContext)
+			opEngine.journal.complete(journalRunIndex, start+i, &journalOp, logger)
+		}
+	}
+	return stageErr
+}
+
+// copyForConcurrentOp returns the exec context that an op of a concurrent
+// stage runs with. It has its own dispatcher, and its own copy of the
+// state, so that the op does not change the state the other ops read.
+func (execContext *opEngineExecContext) copyForConcurrentOp(opIndex int) *opEngineExecContext {
+	opContext := *execContext
+	opContext.dispatcher = execContext.dispatcher.copyForConcurrentOp()
+	opContext.networkProfiles = maps.Clone(execContext.networkProfiles)
+	opContext.nmaVDatabase = execContext.nmaVDatabase.copy()
+	opContext.upHosts = slices.Clone(execContext.upHosts)
+	opContext.nodesInfo = slices.Clone(execContext.nodesInfo)
+	opContext.scNodesInfo = slices.Clone(execContext.scNodesInfo)
+	opContext.upScInfo = maps.Clone(execContext.upScInfo)
+	opContext.upHostsToSandboxes = maps.Clone(execContext.upHostsToSandboxes)
+	opContext.hostsWithLatestCatalog = slices.Clone(execContext.hostsWithLatestCatalog)
+	opContext.primaryHostsWithLatestCatalog = slices.Clone(execContext.primaryHostsWithLatestCatalog)
+	opContext.startupCommandMap = maps.Clone(execContext.startupCommandMap)
+	opContext.restorePoints = slices.Clone(execContext.restorePoints)
+	// the lists that the ops add to start empty, and they are appended
+	// to the ones of the engine when the stage is merged
+	opContext.hostsWithWrongAuth = nil
+	opContext.undoActions = nil
+	opContext.opIndex = opIndex
+	return &opContext
+}
+
+// mergeConcurrentOp keeps the state that an op of a concurrent stage has
+// changed from the base context, which the op started with. The state of an
+// op that failed is dropped, but the changes it has made before failing are
+// still undone, and the hosts it could not log in to are still reported.
+// A field added to opEngineExecContext must be merged here too.
+func (execContext *opEngineExecContext) mergeConcurrentOp(base, opContext *opEngineExecContext, succeeded bool) {
+	execContext.hostsWithWrongAuth = append(execContext.hostsWithWrongAuth, opContext.hostsWithWrongAuth...)
+	execContext.undoActions = append(execContext.undoActions, opContext.undoActions...)
+	if !succeeded {
+		return
+	}
+	mergeState(&execContext.networkProfiles, base.networkProfiles, opContext.networkProfiles)
+	mergeState(&execContext.nmaVDatabase, base.nmaVDatabase, opContext.nmaVDatabase)
+	mergeState(&execContext.upHosts, base.upHosts, opContext.upHosts)
+	mergeState(&execContext.nodesInfo, base.nodesInfo, opContext.nodesInfo)
+	mergeState(&execContext.scNodesInfo, base.scNodesInfo, opContext.scNodesInfo)
+	mergeState(&execContext.upScInfo, base.upScInfo, opContext.upScInfo)
+	mergeState(&execContext.upHostsToSandboxes, base.upHostsToSandboxes, opContext.upHostsToSandboxes)
+	mergeState(&execContext.defaultSCName, base.defaultSCName, opContext.defaultSCName)
+	mergeState(&execContext.hostsWithLatestCatalog, base.hostsWithLatestCatalog, opContext.hostsWithLatestCatalog)
+	mergeState(&execContext.primaryHostsWithLatestCatalog, base.primaryHostsWithLatestCatalog, opContext.primaryHostsWithLatestCatalog)
+	mergeState(&execContext.startupCommandMap, base.startupCommandMap, opContext.startupCommandMap)
+	mergeState(&execContext.dbInfo, base.dbInfo, opContext.dbInfo)
+	mergeState(&execContext.restorePoints, base.restorePoints, opContext.restorePoints)
+	mergeState(&execContext.systemTableList, base.systemTableList, opContext.systemTableList)
+}
+
+// mergeState keeps the state of an op if the op has changed it
+func mergeState[T any](state *T, stageState, opState T) {
+	if !reflect.DeepEqual(stageState, opState) {
+		*state = opState
+	}
+}
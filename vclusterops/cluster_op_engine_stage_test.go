@@ -0,0 +1,170 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// mockStageOp is a mock op that waits until all the ops of its stage have
+// started, so that it only completes if they run at the same time
+type mockStageOp struct {
+	mockRequestOp
+	started      *sync.WaitGroup
+	fail         bool
+	setExecState func(execContext *opEngineExecContext)
+}
+
+func makeMockStageOps(names ...string) []*mockStageOp {
+	var started sync.WaitGroup
+	started.Add(len(names))
+	var ops []*mockStageOp
+	for _, name := range names {
+		op := &mockStageOp{mockRequestOp: mockRequestOp{mockOp: makeMockOp(false), method: GetMethod}, started: &started}
+		op.name = name
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func (m *mockStageOp) execute(_ context.Context, execContext *opEngineExecContext) error {
+	m.calledExecute = true
+	m.started.Done()
+	m.started.Wait()
+	if m.setExecState != nil {
+		m.setExecState(execContext)
+	}
+	if m.fail {
+		return fmt.Errorf("%s failed", m.name)
+	}
+	return nil
+}
+
+func TestRunConcurrentStage(t *testing.T) {
+	stageOps := makeMockStageOps("CheckVersionOp", "NetworkProfileOp", "PrepareDirectoriesOp")
+	stageOps[0].setExecState = func(execContext *opEngineExecContext) {
+		execContext.defaultSCName = "default_subcluster"
+	}
+	stageOps[1].setExecState = func(execContext *opEngineExecContext) {
+		execContext.networkProfiles = map[string]networkProfile{"host1": {Address: "host1"}}
+	}
+	stageOps[2].setExecState = func(execContext *opEngineExecContext) {
+		execContext.registerUndo("remove the directories")
+	}
+	firstOp := mockRequestOp{mockOp: makeMockOp(false), method: GetMethod}
+	firstOp.name = "HealthOp"
+	instructions := []clusterOp{&firstOp}
+	instructions = append(instructions, runConcurrently(stageOps[0], stageOps[1], stageOps[2])...)
+
+	options := DatabaseOptionsFactory()
+	ctx, call := startTestCall(context.Background(), commandCreateDB, &options)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err := clusterOpEngine.run(ctx, vlog.Printer{})
+	assert.NoError(t, err)
+
+	// the state set by each op of the stage is kept
+	execContext := clusterOpEngine.execContext
+	assert.Equal(t, "default_subcluster", execContext.defaultSCName)
+	assert.Contains(t, execContext.networkProfiles, "host1")
+	assert.Len(t, execContext.undoActions, 1)
+	assert.Equal(t, 3, execContext.undoActions[0].opIndex)
+
+	// the ops are reported in the order of the instructions
+	var reportedOps []string
+	for _, opReport := range call.report.Ops {
+		reportedOps = append(reportedOps, opReport.Name)
+	}
+	assert.Equal(t, []string{"HealthOp", "CheckVersionOp", "NetworkProfileOp", "PrepareDirectoriesOp"}, reportedOps)
+}
+
+func TestRunConcurrentStageFailure(t *testing.T) {
+	stageOps := makeMockStageOps("CheckVersionOp", "NetworkProfileOp", "PrepareDirectoriesOp")
+	stageOps[1].fail = true
+	stageOps[1].setExecState = func(execContext *opEngineExecContext) {
+		execContext.registerUndo("remove the network profiles")
+		execContext.hostsWithWrongAuth = append(execContext.hostsWithWrongAuth, "host1")
+		execContext.upHosts = []string{"host1"}
+	}
+	stageOps[2].setExecState = func(execContext *opEngineExecContext) {
+		execContext.defaultSCName = "default_subcluster"
+	}
+	nextOp := mockRequestOp{mockOp: makeMockOp(false), method: GetMethod}
+	instructions := runConcurrently(stageOps[0], stageOps[1], stageOps[2])
+	instructions = append(instructions, &nextOp)
+
+	options := DatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.JournalPath = filepath.Join(t.TempDir(), "create_db.journal")
+	ctx, call := startTestCall(context.Background(), commandCreateDB, &options)
+	err := call.startJournal(&options, commandCreateDB)
+	assert.NoError(t, err)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(ctx, vlog.Printer{})
+
+	// the other ops of the stage finish, and the engine stops after it
+	assert.ErrorContains(t, err, "NetworkProfileOp failed")
+	assert.True(t, stageOps[2].calledExecute)
+	assert.False(t, nextOp.calledPrepare)
+	assert.Equal(t, "default_subcluster", clusterOpEngine.execContext.defaultSCName)
+	assert.Len(t, call.report.Ops, 3)
+	assert.Equal(t, FailureResult, call.report.Ops[1].Status)
+	assert.Equal(t, SuccessResult, call.report.Ops[2].Status)
+
+	// the state of the failed op is dropped, but what it has done is undone
+	assert.Empty(t, clusterOpEngine.execContext.upHosts)
+	assert.Equal(t, []string{"host1"}, clusterOpEngine.execContext.hostsWithWrongAuth)
+	assert.Len(t, call.report.Undo, 1)
+	assert.Equal(t, "remove the network profiles", call.report.Undo[0].Description)
+
+	// only the ops before the failure are recorded in the journal
+	journal, err := readJournal(options.JournalPath)
+	assert.NoError(t, err)
+	assert.Len(t, journal.Runs[0].Ops, 1)
+	assert.Equal(t, "CheckVersionOp", journal.Runs[0].Ops[0].Name)
+}
+
+func TestDryRunWithConcurrentStage(t *testing.T) {
+	// the ops of a stage are run one at a time in a dry run
+	firstOp := mockRequestOp{mockOp: makeMockOp(false), method: GetMethod}
+	secondOp := mockRequestOp{mockOp: makeMockOp(false), method: PostMethod}
+	opEngn := makeClusterOpEngine(runConcurrently(&firstOp, &secondOp), &httpsCerts{})
+	opEngn.dryRun = true
+	assert.Equal(t, 1, opEngn.getStageEnd(0))
+	opEngn.dryRun = false
+	assert.Equal(t, 2, opEngn.getStageEnd(0))
+}
+
+func TestCopyForConcurrentOp(t *testing.T) {
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	execContext.nmaVDatabase.Nodes = []nmaVNode{{Address: "host1", Name: "v_db_node0001"}}
+	execContext.nmaVDatabase.HostNodeMap = map[string]*nmaVNode{"host1": {Address: "host1", Name: "v_db_node0001"}}
+
+	// the nodes an op changes are not the ones of the other ops
+	opContext := execContext.copyForConcurrentOp(1)
+	opContext.nmaVDatabase.HostNodeMap["host1"].Name = "v_db_node0002"
+	opContext.nmaVDatabase.HostNodeMap["host2"] = &nmaVNode{Address: "host2"}
+	opContext.nmaVDatabase.Nodes[0].Name = "v_db_node0002"
+	assert.Equal(t, "v_db_node0001", execContext.nmaVDatabase.HostNodeMap["host1"].Name)
+	assert.NotContains(t, execContext.nmaVDatabase.HostNodeMap, "host2")
+	assert.Equal(t, "v_db_node0001", execContext.nmaVDatabase.Nodes[0].Name)
+}
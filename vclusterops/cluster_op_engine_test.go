@@ -16,8 +16,11 @@
 package vclusterops
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -47,7 +50,7 @@ func (m *mockOp) prepare(_ *opEngineExecContext) error {
 	return nil
 }
 
-func (m *mockOp) execute(_ *opEngineExecContext) error {
+func (m *mockOp) execute(_ context.Context, _ *opEngineExecContext) error {
 	m.calledExecute = true
 	return nil
 }
@@ -75,7 +78,7 @@ func TestSkipExecuteOp(t *testing.T) {
 	instructions := []clusterOp{&opWithSkipDisabled, &opWithSkipEnabled}
 	certs := httpsCerts{key: "key", cert: "cert", caCert: "ca-cert"}
 	opEngn := makeClusterOpEngine(instructions, &certs)
-	err := opEngn.run(vlog.Printer{})
+	err := opEngn.run(context.Background(), vlog.Printer{})
 	assert.Equal(t, nil, err)
 	assert.True(t, opWithSkipDisabled.calledPrepare)
 	assert.True(t, opWithSkipDisabled.calledExecute)
@@ -84,3 +87,182 @@ func TestSkipExecuteOp(t *testing.T) {
 	assert.False(t, opWithSkipEnabled.calledExecute)
 	assert.True(t, opWithSkipEnabled.calledFinalize)
 }
+
+// mockBlockingOp blocks in execute until its context is canceled,
+// like an op with in-flight requests would
+type mockBlockingOp struct {
+	mockOp
+	started chan struct{}
+}
+
+func (m *mockBlockingOp) execute(ctx context.Context, _ *opEngineExecContext) error {
+	m.calledExecute = true
+	close(m.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCancelBeforeRun(t *testing.T) {
+	op := makeMockOp(false)
+	op.name = "MockOp"
+	certs := httpsCerts{}
+	opEngn := makeClusterOpEngine([]clusterOp{&op}, &certs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := opEngn.run(ctx, vlog.Printer{})
+
+	// the engine should stop before the first instruction
+	var cancelErr *OpCanceledError
+	assert.True(t, errors.As(err, &cancelErr))
+	assert.Equal(t, "MockOp", cancelErr.OpName)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, op.calledPrepare)
+}
+
+func TestCancelDuringExecute(t *testing.T) {
+	blockingOp := mockBlockingOp{mockOp: makeMockOp(false), started: make(chan struct{})}
+	blockingOp.name = "BlockingOp"
+	nextOp := makeMockOp(false)
+	certs := httpsCerts{}
+	opEngn := makeClusterOpEngine([]clusterOp{&blockingOp, &nextOp}, &certs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-blockingOp.started
+		cancel()
+	}()
+	err := opEngn.run(ctx, vlog.Printer{})
+
+	// the error should name the op that was interrupted
+	var cancelErr *OpCanceledError
+	assert.True(t, errors.As(err, &cancelErr))
+	assert.Equal(t, "BlockingOp", cancelErr.OpName)
+	assert.ErrorIs(t, err, context.Canceled)
+	// the engine should not move on to the next instruction
+	assert.False(t, nextOp.calledPrepare)
+
+	// an expired deadline is reported the same way
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	blockingOp = mockBlockingOp{mockOp: makeMockOp(false), started: make(chan struct{})}
+	opEngn = makeClusterOpEngine([]clusterOp{&blockingOp}, &certs)
+	err = opEngn.run(ctx, vlog.Printer{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// mockRequestOp prepares a request with the given method and body
+type mockRequestOp struct {
+	mockOp
+	method string
+	body   string
+}
+
+func (m *mockRequestOp) prepare(_ *opEngineExecContext) error {
+	m.calledPrepare = true
+	m.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{
+		"host1": {Method: m.method, Endpoint: "nodes", RequestData: m.body},
+	}
+	return nil
+}
+
+func TestDryRun(t *testing.T) {
+	readOp := mockRequestOp{mockOp: makeMockOp(false), method: GetMethod}
+	writeOp := mockRequestOp{mockOp: makeMockOp(false), method: PostMethod,
+		body: `{"name":"v_db_node0001","params":{"db-password":"secret"}}`}
+	writeOp.name = "WriteOp"
+	nextReadOp := mockRequestOp{mockOp: makeMockOp(false), method: GetMethod}
+	nextReadOp.name = "NextReadOp"
+	opEngn := makeClusterOpEngine([]clusterOp{&readOp, &writeOp, &nextReadOp}, &httpsCerts{})
+	opEngn.dryRun = true
+	err := opEngn.run(context.Background(), vlog.Printer{})
+
+	var plan *DryRunPlan
+	assert.True(t, errors.As(err, &plan))
+	// the read-only op before the first op that changes the database is run
+	assert.True(t, readOp.calledExecute)
+	assert.True(t, readOp.calledFinalize)
+	// that op and the ones after it are planned instead of being run
+	assert.False(t, writeOp.calledExecute)
+	assert.False(t, writeOp.calledFinalize)
+	assert.False(t, nextReadOp.calledExecute)
+	assert.Len(t, plan.Ops, 2)
+	assert.Equal(t, "WriteOp", plan.Ops[0].Name)
+	assert.Equal(t, []string{"host1"}, plan.Ops[0].Hosts)
+	assert.Equal(t, []RequestPlan{{
+		Host:     "host1",
+		Method:   PostMethod,
+		Endpoint: "nodes",
+		Body:     `{"name":"v_db_node0001","params":{"db-password":"******"}}`,
+	}}, plan.Ops[0].Requests)
+	assert.Equal(t, "NextReadOp", plan.Ops[1].Name)
+
+	// without dry run, all ops are run
+	writeOp = mockRequestOp{mockOp: makeMockOp(false), method: PostMethod}
+	opEngn = makeClusterOpEngine([]clusterOp{&writeOp}, &httpsCerts{})
+	err = opEngn.run(context.Background(), vlog.Printer{})
+	assert.NoError(t, err)
+	assert.True(t, writeOp.calledExecute)
+}
+
+func TestMaskRequestBody(t *testing.T) {
+	assert.Equal(t, "", maskRequestBody(""))
+	assert.Equal(t, "******", maskRequestBody("not json"))
+	assert.Equal(t, `[{"AWSAuth":"******","name":"a"}]`, maskRequestBody(`[{"name":"a","AWSAuth":"id:key"}]`))
+	assert.Equal(t, `{"sessionToken":"******","storage":{"secretKey":"******"}}`,
+		maskRequestBody(`{"storage":{"secretKey":"abc"},"sessionToken":"xyz"}`))
+}
+
+// mockFailedOp fails in execute after getting the given host results
+type mockFailedOp struct {
+	mockOp
+	results map[string]hostHTTPResult
+}
+
+func (m *mockFailedOp) execute(_ context.Context, _ *opEngineExecContext) error {
+	m.calledExecute = true
+	m.clusterHTTPRequest.ResultCollection = m.results
+	return errors.New("host2 failed")
+}
+
+func TestExecutionReport(t *testing.T) {
+	okOp := makeMockOp(false)
+	okOp.name = "OkOp"
+	skippedOp := makeMockOp(true)
+	failedOp := mockFailedOp{mockOp: makeMockOp(false), results: map[string]hostHTTPResult{
+		"host2": {status: FAILURE, statusCode: InternalErrorCode, err: errors.New("internal error"), attempts: 2,
+			retriedErrs: []error{errors.New("service unavailable")}},
+		"host1": {status: SUCCESS, statusCode: SuccessCode, attempts: 1},
+	}}
+	failedOp.name = "FailedOp"
+	failedOp.hosts = []string{"host1", "host2"}
+	nextOp := makeMockOp(false)
+
+	options := DatabaseOptionsFactory()
+	ctx, call := startTestCall(context.Background(), commandCreateDB, &options)
+	opEngn := options.makeClusterOpEngine([]clusterOp{&okOp, &skippedOp, &failedOp, &nextOp})
+	err := opEngn.run(ctx, vlog.Printer{})
+	report := call.finish(err)
+
+	assert.Equal(t, FailureResult, report.Status)
+	assert.Contains(t, report.Error, "host2 failed")
+	// the ops after the failed one are not run
+	assert.Len(t, report.Ops, 3)
+	assert.Equal(t, "OkOp", report.Ops[0].Name)
+	assert.Equal(t, SuccessResult, report.Ops[0].Status)
+	assert.Equal(t, SkippedResult, report.Ops[1].Status)
+
+	failedOpReport := report.Ops[2]
+	assert.Equal(t, "FailedOp", failedOpReport.Name)
+	assert.Equal(t, FailureResult, failedOpReport.Status)
+	assert.Contains(t, failedOpReport.Error, "host2 failed")
+	assert.Equal(t, []string{"host1", "host2"}, failedOpReport.Hosts)
+	assert.Equal(t, []HostResultReport{
+		{Host: "host1", StatusCode: SuccessCode, Status: "SUCCESS", Attempts: 1},
+		{Host: "host2", StatusCode: InternalErrorCode, Status: "FAILURE", Error: "internal error", Attempts: 2,
+			RetriedErrors: []string{"service unavailable"}},
+	}, failedOpReport.HostResults)
+	assert.False(t, failedOpReport.EndTime.Before(failedOpReport.StartTime))
+	assert.Equal(t, failedOpReport.EndTime.Sub(failedOpReport.StartTime), failedOpReport.Duration)
+	assert.False(t, report.EndTime.Before(failedOpReport.EndTime))
+}
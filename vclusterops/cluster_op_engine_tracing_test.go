@@ -0,0 +1,86 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+	"github.com/vertica/vcluster/vclusterops/tracing"
+)
+
+func TestTracingOnFakeCluster(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+	var buf bytes.Buffer
+	tracer := tracing.NewJSONTracer(&buf)
+	vcc.Tracer = tracer
+	createFakeClusterDatabase(t, vcc, cluster)
+	assert.NoError(t, tracer.Err())
+
+	spans := make(map[string]tracing.SpanRecord)
+	var records []tracing.SpanRecord
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record tracing.SpanRecord
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		spans[record.SpanID] = record
+		records = append(records, record)
+	}
+	if !assert.NotEmpty(t, records) {
+		return
+	}
+	// the span of the command ends last, and it is the root of the others
+	root := records[len(records)-1]
+	assert.Equal(t, commandCreateDB, root.Name)
+	assert.Empty(t, root.ParentSpanID)
+	var opSpans, requestSpans int
+	requestSpanNames := make(map[string]bool)
+	for _, record := range records[:len(records)-1] {
+		assert.Equal(t, root.TraceID, record.TraceID)
+		parent, ok := spans[record.ParentSpanID]
+		if !assert.True(t, ok, "span %s has no parent", record.Name) {
+			continue
+		}
+		if parent.SpanID == root.SpanID {
+			opSpans++
+			continue
+		}
+		// a request is the child of an op
+		requestSpans++
+		assert.Equal(t, root.SpanID, parent.ParentSpanID)
+		assert.NotEmpty(t, record.Attributes["http.host"])
+		assert.NotEmpty(t, record.Attributes["http.endpoint"])
+		requestSpanNames[record.Name] = true
+	}
+	assert.Positive(t, opSpans)
+	assert.Positive(t, requestSpans)
+	// the spans of the requests are named after the template of their endpoint
+	assert.True(t, requestSpanNames["GET v1/nodes/{host}"])
+	assert.False(t, requestSpanNames["GET v1/nodes/"+fakeClusterHosts[0]])
+
+	// the hosts received the trace context of the requests
+	for _, request := range cluster.Requests() {
+		traceParent := request.Header.Get(tracing.TraceParentHeader)
+		assert.True(t, strings.HasPrefix(traceParent, "00-"+root.TraceID+"-"), traceParent)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// undoAction reverts the change that an op has made, such as the nodes it
+// has added to the catalog. The ops that change the cluster register an
+// undo action once they have succeeded, and if a later op of the engine
+// run fails, the engine runs the registered actions in reverse order.
+type undoAction struct {
+	// what the action does, as shown to the user
+	description string
+	// the ops that revert the change, run in order
+	instructions []clusterOp
+	// the index of the op that registered the action in the engine run
+	opIndex int
+}
+
+// UndoReport describes an undo action that was run after an op failed
+type UndoReport struct {
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// registerUndo adds the action that reverts the change an op has just made
+func (execContext *opEngineExecContext) registerUndo(description string, instructions ...clusterOp) {
+	execContext.undoActions = append(execContext.undoActions, undoAction{
+		description:  description,
+		instructions: instructions,
+		opIndex:      execContext.opIndex,
+	})
+}
+
+// undo runs the undo actions of the ops that have completed, in reverse
+// order, after an op has failed with opErr. It stops at the first action
+// that fails, as the ones before it may expect that change to be reverted.
+// The returned error tells whether the changes were undone.
+func (opEngine *VClusterOpEngine) undo(logger vlog.Printer, execContext *opEngineExecContext,
+	findCertsInOptions bool, journalRunIndex int, opErr error) error {
+	undoActions := execContext.undoActions
+	execContext.undoActions = nil
+	if len(undoActions) == 0 {
+		return opErr
+	}
+
+	logger.PrintWarning("The operation failed, undoing the changes it has made")
+	// the changes are undone even if the caller has given up, so that the
+	// database is not left half changed
+	ctx := context.Background()
+	for i := len(undoActions) - 1; i >= 0; i-- {
+		action := &undoActions[i]
+		err := opEngine.runUndoAction(ctx, logger, execContext, action, findCertsInOptions)
+		if err != nil {
+			opEngine.report.addUndo(action.description, FailureResult, err)
+			logger.PrintError("Failed to %s, details: %s", action.description, err)
+			for j := i - 1; j >= 0; j-- {
+				opEngine.report.addUndo(undoActions[j].description, SkippedResult, nil)
+				logger.PrintWarning("Did not %s", undoActions[j].description)
+			}
+			return fmt.Errorf("%w; the changes made before the failure could not be undone, failed to %s: %w",
+				opErr, action.description, err)
+		}
+		opEngine.report.addUndo(action.description, SuccessResult, nil)
+		logger.PrintInfo("Undo succeeded: %s", action.description)
+		opEngine.journal.undo(journalRunIndex, action.opIndex, logger)
+	}
+	return fmt.Errorf("%w; the changes made before the failure were undone", opErr)
+}
+
+func (opEngine *VClusterOpEngine) runUndoAction(ctx context.Context, logger vlog.Printer,
+	execContext *opEngineExecContext, action *undoAction, findCertsInOptions bool) error {
+	for _, op := range action.instructions {
+		startTime := time.Now()
+		opEngine.progress.opStarted(op)
+		err := opEngine.runInstruction(ctx, logger, execContext, op, findCertsInOptions)
+		opEngine.finishOp(op, startTime, opEngine.getInstructionStatus(op, err), err)
+		if err != nil {
+			return err
+		}
+	}
+	// the undo ops do not register undo actions of their own
+	execContext.undoActions = nil
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// mockUndoOp is a mock op that changes the cluster, records that it ran,
+// and registers its undo op once it has succeeded
+type mockUndoOp struct {
+	mockRequestOp
+	fail   bool
+	undoOp *mockUndoOp
+	ran    *[]string
+	// the caller gives up once the op has run
+	cancel context.CancelFunc
+}
+
+func makeMockUndoOp(name string, ran *[]string) *mockUndoOp {
+	op := &mockUndoOp{mockRequestOp: mockRequestOp{mockOp: makeMockOp(false), method: PostMethod}, ran: ran}
+	op.name = name
+	return op
+}
+
+func (m *mockUndoOp) execute(_ context.Context, _ *opEngineExecContext) error {
+	m.calledExecute = true
+	*m.ran = append(*m.ran, m.name)
+	if m.cancel != nil {
+		m.cancel()
+	}
+	if m.fail {
+		return fmt.Errorf("%s failed", m.name)
+	}
+	return nil
+}
+
+func (m *mockUndoOp) finalize(execContext *opEngineExecContext) error {
+	if m.undoOp != nil {
+		execContext.registerUndo("undo "+m.name, m.undoOp)
+	}
+	return nil
+}
+
+func TestUndoInReverseOrder(t *testing.T) {
+	var ran []string
+	createOp := makeMockUndoOp("CreateNodeOp", &ran)
+	createOp.undoOp = makeMockUndoOp("DropNodeOp", &ran)
+	addOp := makeMockUndoOp("AddSubclusterOp", &ran)
+	addOp.undoOp = makeMockUndoOp("DropSubclusterOp", &ran)
+	failingOp := makeMockUndoOp("StartNodeOp", &ran)
+	failingOp.fail = true
+	nextOp := makeMockUndoOp("PollNodeStateOp", &ran)
+
+	options := DatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.JournalPath = filepath.Join(t.TempDir(), "add_node.journal")
+	ctx, call := startTestCall(context.Background(), commandAddNode, &options)
+	err := call.startJournal(&options, commandAddNode)
+	assert.NoError(t, err)
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{createOp, addOp, failingOp, nextOp})
+	err = clusterOpEngine.run(ctx, vlog.Printer{})
+
+	// the changes are undone in reverse order, after the op that failed
+	assert.Equal(t, []string{"CreateNodeOp", "AddSubclusterOp", "StartNodeOp", "DropSubclusterOp", "DropNodeOp"}, ran)
+	assert.False(t, nextOp.calledExecute)
+	assert.ErrorContains(t, err, "StartNodeOp failed")
+	assert.ErrorContains(t, err, "the changes made before the failure were undone")
+	assert.Equal(t, []UndoReport{
+		{Description: "undo AddSubclusterOp", Status: SuccessResult},
+		{Description: "undo CreateNodeOp", Status: SuccessResult},
+	}, call.report.Undo)
+
+	// the undone ops are run again if the command is resumed
+	journal, err := readJournal(options.JournalPath)
+	assert.NoError(t, err)
+	assert.Len(t, journal.Runs[0].Ops, 2)
+	assert.False(t, journal.Runs[0].Ops[0].ChangedCluster)
+	assert.False(t, journal.Runs[0].Ops[1].ChangedCluster)
+}
+
+func TestUndoStopsAtFailure(t *testing.T) {
+	var ran []string
+	createOp := makeMockUndoOp("CreateNodeOp", &ran)
+	createOp.undoOp = makeMockUndoOp("DropNodeOp", &ran)
+	addOp := makeMockUndoOp("AddSubclusterOp", &ran)
+	addOp.undoOp = makeMockUndoOp("DropSubclusterOp", &ran)
+	addOp.undoOp.fail = true
+	failingOp := makeMockUndoOp("StartNodeOp", &ran)
+	failingOp.fail = true
+
+	options := DatabaseOptionsFactory()
+	ctx, call := startTestCall(context.Background(), commandAddNode, &options)
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{createOp, addOp, failingOp})
+	err := clusterOpEngine.run(ctx, vlog.Printer{})
+
+	// the earlier changes are kept once an undo action fails
+	assert.Equal(t, []string{"CreateNodeOp", "AddSubclusterOp", "StartNodeOp", "DropSubclusterOp"}, ran)
+	assert.ErrorContains(t, err, "StartNodeOp failed")
+	assert.ErrorContains(t, err, "could not be undone, failed to undo AddSubclusterOp")
+	assert.ErrorContains(t, err, "DropSubclusterOp failed")
+	assert.Len(t, call.report.Undo, 2)
+	assert.Equal(t, FailureResult, call.report.Undo[0].Status)
+	assert.Contains(t, call.report.Undo[0].Error, "DropSubclusterOp failed")
+	assert.Equal(t, UndoReport{Description: "undo CreateNodeOp", Status: SkippedResult}, call.report.Undo[1])
+}
+
+func TestUndoOnCancel(t *testing.T) {
+	var ran []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	createOp := makeMockUndoOp("CreateNodeOp", &ran)
+	createOp.undoOp = makeMockUndoOp("DropNodeOp", &ran)
+	createOp.cancel = cancel
+	nextOp := makeMockUndoOp("StartNodeOp", &ran)
+
+	options := DatabaseOptionsFactory()
+	ctx, call := startTestCall(ctx, commandAddNode, &options)
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{createOp, nextOp})
+	err := clusterOpEngine.run(ctx, vlog.Printer{})
+
+	// the caller gives up between the ops, and the change of the first one
+	// is undone
+	assert.Equal(t, []string{"CreateNodeOp", "DropNodeOp"}, ran)
+	assert.False(t, nextOp.calledExecute)
+	var canceledErr *OpCanceledError
+	assert.ErrorAs(t, err, &canceledErr)
+	assert.Equal(t, "StartNodeOp", canceledErr.OpName)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "the changes made before the failure were undone")
+	assert.Equal(t, []UndoReport{{Description: "undo CreateNodeOp", Status: SuccessResult}}, call.report.Undo)
+}
+
+func TestNoUndoOnSuccess(t *testing.T) {
+	var ran []string
+	createOp := makeMockUndoOp("CreateNodeOp", &ran)
+	createOp.undoOp = makeMockUndoOp("DropNodeOp", &ran)
+
+	options := DatabaseOptionsFactory()
+	ctx, call := startTestCall(context.Background(), commandAddNode, &options)
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{createOp})
+	err := clusterOpEngine.run(ctx, vlog.Printer{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"CreateNodeOp"}, ran)
+	assert.Empty(t, call.report.Undo)
+}
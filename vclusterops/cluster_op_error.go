@@ -0,0 +1,101 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/vertica/vcluster/rfc7807"
+)
+
+// ClusterOpError is returned by the VClusterCommands APIs when an op fails
+// on some of its hosts. It tells which hosts failed and why, so that the
+// caller can retry on the failed hosts only. It can be found with errors.As,
+// even when a problem such as rfc7807.PartialFailure wraps it.
+type ClusterOpError struct {
+	// OpName is the name of the op that failed
+	OpName string
+	// HostErrors has the result of each host whose request failed
+	HostErrors map[string]*HostError
+	// Err is the error that the op returned
+	Err error
+}
+
+// HostError is the failed result of the request an op sent to a host
+type HostError struct {
+	Host string
+	// StatusCode is 0 if the host did not respond
+	StatusCode int
+	Err        error
+	// Problem is the rfc7807 problem that the host responded with, or that
+	// vcluster raised for the request, if there is one
+	Problem *rfc7807.VProblem
+}
+
+func (e *ClusterOpError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the op, then the error of each failed host,
+// so that errors.Is and errors.As can be used on any of them
+func (e *ClusterOpError) Unwrap() []error {
+	errs := []error{e.Err}
+	for _, host := range e.FailedHosts() {
+		errs = append(errs, e.HostErrors[host])
+	}
+	return errs
+}
+
+// FailedHosts returns the sorted hosts whose request failed
+func (e *ClusterOpError) FailedHosts() []string {
+	hosts := make([]string, 0, len(e.HostErrors))
+	for host := range e.HostErrors {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func (e *HostError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HostError) Unwrap() error {
+	return e.Err
+}
+
+// makeClusterOpError returns the error of the op as a ClusterOpError if the
+// requests of the op failed on any host. Otherwise, the op failed for a
+// reason that is not the result of a host, and err is returned as it is.
+func (op *opBase) makeClusterOpError(err error) error {
+	hostErrors := make(map[string]*HostError)
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		if result.isPassing() {
+			continue
+		}
+		hostErr := &HostError{Host: host, StatusCode: result.statusCode, Err: result.err}
+		problem := &rfc7807.VProblem{}
+		if errors.As(result.err, &problem) {
+			hostErr.Problem = problem
+		}
+		hostErrors[host] = hostErr
+	}
+	if len(hostErrors) == 0 {
+		return err
+	}
+	return &ClusterOpError{OpName: op.name, HostErrors: hostErrors, Err: err}
+}
@@ -0,0 +1,74 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+)
+
+func TestClusterOpError(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+	options := VCreateDatabaseOptionsFactory()
+	setFakeClusterOptions(&options.DatabaseOptions, cluster, fakeClusterHosts)
+	options.CatalogPrefix = defaultPath
+	options.DataPrefix = defaultPath
+
+	// the host without the NMA is the only one that failed
+	cluster.SetNMARunning(fakeClusterHosts[2], false)
+	_, _, err := vcc.VCreateDatabase(context.Background(), &options)
+	opErr := &ClusterOpError{}
+	if assert.ErrorAs(t, err, &opErr) {
+		assert.Equal(t, "NMAHealthOp", opErr.OpName)
+		assert.Equal(t, []string{fakeClusterHosts[2]}, opErr.FailedHosts())
+		hostErr := opErr.HostErrors[fakeClusterHosts[2]]
+		assert.Equal(t, 0, hostErr.StatusCode)
+		assert.ErrorIs(t, hostErr, syscall.ECONNREFUSED)
+		if assert.NotNil(t, hostErr.Problem) {
+			assert.True(t, hostErr.Problem.IsInstanceOf(rfc7807.HostUnreachable))
+		}
+	}
+	hostErr := &HostError{}
+	if assert.ErrorAs(t, err, &hostErr) {
+		assert.Equal(t, fakeClusterHosts[2], hostErr.Host)
+	}
+
+	// the problem that a host responded with is parsed
+	cluster.SetNMARunning(fakeClusterHosts[2], true)
+	cluster.Handle(fakecluster.NMA, http.MethodPost, "catalog/bootstrap",
+		func(host string, w http.ResponseWriter, _ *http.Request) {
+			fakecluster.WriteProblem(w, host, rfc7807.GenericLicenseCheckFailure, "the license is expired")
+		})
+	_, _, err = vcc.VCreateDatabase(context.Background(), &options)
+	if assert.ErrorAs(t, err, &opErr) {
+		assert.Len(t, opErr.HostErrors, 1)
+		for _, hostErr := range opErr.HostErrors {
+			assert.Equal(t, http.StatusInternalServerError, hostErr.StatusCode)
+			if assert.NotNil(t, hostErr.Problem) {
+				assert.True(t, hostErr.Problem.IsInstanceOf(rfc7807.GenericLicenseCheckFailure))
+				assert.Equal(t, "the license is expired", hostErr.Problem.Detail)
+			}
+		}
+	}
+}
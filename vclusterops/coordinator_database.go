@@ -109,7 +109,7 @@ func (vdb *VCoordinationDatabase) setFromCreateDBOptions(options *VCreateDatabas
 	// build after validating the options
 	err := options.validateAnalyzeOptions(logger)
 	if err != nil {
-		return err
+		return makeValidationProblem(err)
 	}
 
 	err = vdb.setFromBasicDBOptions(options)
@@ -274,8 +274,13 @@ func (vdb *VCoordinationDatabase) genDataPath(nodeName string) string {
 
 // genDepotPath builds and returns the depot path
 func (vdb *VCoordinationDatabase) genDepotPath(nodeName string) string {
+	return makeDepotPath(vdb.DepotPrefix, vdb.Name, nodeName)
+}
+
+// makeDepotPath builds the depot path of a node from a depot prefix
+func makeDepotPath(depotPrefix, dbName, nodeName string) string {
 	depotSuffix := fmt.Sprintf("%s_depot", nodeName)
-	return filepath.Join(vdb.DepotPrefix, vdb.Name, depotSuffix)
+	return filepath.Join(depotPrefix, dbName, depotSuffix)
 }
 
 // genCatalogPath builds and returns the catalog path
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -41,10 +42,9 @@ type VCreateDatabaseOptions struct {
 	DepotSize                string // depot size with two supported formats: % and KMGT, e.g., 50% or 10G
 	GetAwsCredentialsFromEnv bool   // whether get AWS credentials from environmental variables
 	// part 3: optional info
-	ForceCleanupOnFailure     bool // whether force remove existing directories on failure
-	ForceRemovalAtCreation    bool // whether force remove existing directories before creating the database
-	SkipPackageInstall        bool // whether skip package installation
-	TimeoutNodeStartupSeconds int  // timeout in seconds for polling node start up state
+	ForceCleanupOnFailure  bool // whether force remove existing directories on failure
+	ForceRemovalAtCreation bool // whether force remove existing directories before creating the database
+	SkipPackageInstall     bool // whether skip package installation
 
 	/* part 3: new params originally in installer generated admintools.conf, now in create db op */
 
@@ -85,9 +85,6 @@ func (opt *VCreateDatabaseOptions) setDefaultValues() {
 	defaultPolicy := util.DefaultRestartPolicy
 	opt.Policy = defaultPolicy
 
-	// optional info
-	opt.TimeoutNodeStartupSeconds = util.DefaultTimeoutSeconds
-
 	// new params originally in installer generated admintools.conf, now in create db op
 	opt.P2p = util.DefaultP2p
 	opt.LargeCluster = util.DefaultLargeCluster
@@ -283,7 +280,15 @@ func (opt *VCreateDatabaseOptions) validateAnalyzeOptions(logger vlog.Printer) e
 	return opt.analyzeOptions()
 }
 
-func (vcc VClusterCommands) VCreateDatabase(options *VCreateDatabaseOptions) (VCoordinationDatabase, error) {
+func (vcc VClusterCommands) VCreateDatabase(ctx context.Context,
+	options *VCreateDatabaseOptions) (VCoordinationDatabase, *ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandCreateDB, &options.DatabaseOptions)
+	vdb, err := vcc.createDatabase(ctx, options)
+	report := call.finish(err)
+	return vdb, report, err
+}
+
+func (vcc VClusterCommands) createDatabase(ctx context.Context, options *VCreateDatabaseOptions) (VCoordinationDatabase, error) {
 	vcc.Log.Info("starting VCreateDatabase")
 
 	/*
@@ -305,11 +310,10 @@ func (vcc VClusterCommands) VCreateDatabase(options *VCreateDatabaseOptions) (VC
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	err = clusterOpEngine.run(vcc.Log)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		vcc.Log.Error(err, "fail to create database")
 		return vdb, err
@@ -412,12 +416,12 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 		return instructions, err
 	}
 
+	// the checks, and then the directories and the network profiles, do
+	// not depend on each other
+	instructions = append(instructions, &nmaHealthOp)
+	instructions = append(instructions, runConcurrently(&nmaVerticaVersionOp, &checkDBRunningOp)...)
+	instructions = append(instructions, runConcurrently(&nmaPrepareDirectoriesOp, &nmaNetworkProfileOp)...)
 	instructions = append(instructions,
-		&nmaHealthOp,
-		&nmaVerticaVersionOp,
-		&checkDBRunningOp,
-		&nmaPrepareDirectoriesOp,
-		&nmaNetworkProfileOp,
 		&nmaBootstrapCatalogOp,
 		&nmaReadCatalogEditorOp,
 	)
@@ -431,7 +435,7 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 	nmaStartNodeOp := makeNMAStartNodeOp(bootstrapHost, options.StartUpConf)
 
 	httpsPollBootstrapNodeStateOp, err := makeHTTPSPollNodeStateOpWithTimeoutAndCommand(bootstrapHost, true, /* useHTTPPassword */
-		options.UserName, options.Password, options.TimeoutNodeStartupSeconds, CreateDBCmd)
+		options.UserName, options.Password, getTimeout(options.Timeouts.StatePolling, defaultNodeStartupTimeout), CreateDBCmd)
 	if err != nil {
 		return instructions, err
 	}
@@ -513,7 +517,7 @@ func (vcc VClusterCommands) produceAdditionalCreateDBInstructions(vdb *VCoordina
 
 	if !options.SkipStartupPolling {
 		httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOpWithTimeoutAndCommand(hosts, true, username, options.Password,
-			options.TimeoutNodeStartupSeconds, CreateDBCmd)
+			getTimeout(options.Timeouts.StatePolling, defaultNodeStartupTimeout), CreateDBCmd)
 		if err != nil {
 			return instructions, err
 		}
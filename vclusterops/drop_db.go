@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -56,7 +57,14 @@ func (options *VDropDatabaseOptions) validateAnalyzeOptions() error {
 	return options.analyzeOptions()
 }
 
-func (vcc VClusterCommands) VDropDatabase(options *VDropDatabaseOptions) error {
+func (vcc VClusterCommands) VDropDatabase(ctx context.Context, options *VDropDatabaseOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandDropDB, &options.DatabaseOptions)
+	err := vcc.dropDatabase(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) dropDatabase(ctx context.Context, options *VDropDatabaseOptions) error {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -68,7 +76,7 @@ func (vcc VClusterCommands) VDropDatabase(options *VDropDatabaseOptions) error {
 
 	err := options.validateAnalyzeOptions()
 	if err != nil {
-		return err
+		return makeValidationProblem(err)
 	}
 
 	err = vdb.setFromBasicDBOptions(&options.VCreateDatabaseOptions)
@@ -83,11 +91,10 @@ func (vcc VClusterCommands) VDropDatabase(options *VDropDatabaseOptions) error {
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to drop database: %w", runError)
 	}
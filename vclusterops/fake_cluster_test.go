@@ -0,0 +1,204 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+var fakeClusterHosts = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+
+// setFakeClusterOptions makes the options connect to the fake cluster
+func setFakeClusterOptions(options *DatabaseOptions, cluster *fakecluster.Cluster, hosts []string) {
+	options.DBName = "test_db"
+	options.RawHosts = hosts
+	options.UserName = "dbadmin"
+	options.Password = new(string)
+	options.Key, options.Cert, options.CaCert = cluster.Certs()
+}
+
+func makeFakeClusterCommands(cluster *fakecluster.Cluster) VClusterCommands {
+	vcc := VClusterCommands{Transport: cluster}
+	vcc.Log = vlog.Printer{}
+	return vcc
+}
+
+func createFakeClusterDatabase(t *testing.T, vcc VClusterCommands, cluster *fakecluster.Cluster) {
+	options := VCreateDatabaseOptionsFactory()
+	setFakeClusterOptions(&options.DatabaseOptions, cluster, fakeClusterHosts)
+	options.CatalogPrefix = defaultPath
+	options.DataPrefix = defaultPath
+
+	vdb, _, err := vcc.VCreateDatabase(context.Background(), &options)
+	assert.NoError(t, err)
+	assert.Equal(t, fakeClusterHosts, vdb.HostList)
+}
+
+func TestCreateStopDatabaseOnFakeCluster(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+
+	createFakeClusterDatabase(t, vcc, cluster)
+	assert.Equal(t, "test_db", cluster.DatabaseName())
+	nodes := cluster.Nodes()
+	assert.Len(t, nodes, len(fakeClusterHosts))
+	for _, node := range nodes {
+		assert.Equal(t, util.NodeUpState, node.State)
+		assert.True(t, node.IsPrimary)
+	}
+
+	// the database is made 1-safe, and the packages are installed
+	var endpoints []string
+	for _, request := range cluster.Requests() {
+		if request.Service == fakecluster.HTTPS && request.Method != http.MethodGet {
+			endpoints = append(endpoints, request.Endpoint)
+		}
+	}
+	assert.Contains(t, endpoints, "cluster/k-safety")
+	assert.Contains(t, endpoints, "packages")
+
+	fetchOptions := VFetchNodeStateOptionsFactory()
+	setFakeClusterOptions(&fetchOptions.DatabaseOptions, cluster, fakeClusterHosts[:1])
+	nodeStates, _, err := vcc.VFetchNodeState(context.Background(), &fetchOptions)
+	assert.NoError(t, err)
+	assert.Len(t, nodeStates, len(fakeClusterHosts))
+	for _, nodeState := range nodeStates {
+		assert.Equal(t, util.NodeUpState, nodeState.State)
+		assert.Equal(t, fakecluster.Version, nodeState.Version)
+	}
+
+	stopOptions := VStopDatabaseOptionsFactory()
+	setFakeClusterOptions(&stopOptions.DatabaseOptions, cluster, fakeClusterHosts)
+	_, err = vcc.VStopDatabase(context.Background(), &stopOptions)
+	assert.NoError(t, err)
+	for _, node := range cluster.Nodes() {
+		assert.Equal(t, util.NodeDownState, node.State)
+	}
+}
+
+func TestFakeClusterScriptedFailures(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+
+	// a host without the NMA refuses the connection
+	cluster.SetNMARunning(fakeClusterHosts[2], false)
+	options := VCreateDatabaseOptionsFactory()
+	setFakeClusterOptions(&options.DatabaseOptions, cluster, fakeClusterHosts)
+	options.CatalogPrefix = defaultPath
+	options.DataPrefix = defaultPath
+	_, report, err := vcc.VCreateDatabase(context.Background(), &options)
+	assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+	assert.Equal(t, "NMAHealthOp", report.Ops[0].Name)
+	assert.Equal(t, FailureResult, report.Ops[0].Status)
+	assert.Empty(t, cluster.DatabaseName())
+
+	// a replaced handler makes the bootstrap fail
+	cluster.SetNMARunning(fakeClusterHosts[2], true)
+	cluster.Handle(fakecluster.NMA, http.MethodPost, "catalog/bootstrap",
+		func(host string, w http.ResponseWriter, _ *http.Request) {
+			fakecluster.WriteProblem(w, host, rfc7807.GenericLicenseCheckFailure, "the license is expired")
+		})
+	_, _, err = vcc.VCreateDatabase(context.Background(), &options)
+	assert.ErrorContains(t, err, "the license is expired")
+	assert.Empty(t, cluster.DatabaseName())
+}
+
+func TestFakeClusterDatabaseDown(t *testing.T) {
+	cluster := fakecluster.New()
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+	var nodes []fakecluster.Node
+	for i, host := range fakeClusterHosts {
+		name := fmt.Sprintf("v_test_db_node%04d", i+1)
+		nodes = append(nodes, fakecluster.Node{
+			Name:        name,
+			Address:     host,
+			State:       util.NodeDownState,
+			Subcluster:  "default_subcluster",
+			IsPrimary:   true,
+			CatalogPath: "/data/test_db/" + name + "_catalog",
+		})
+	}
+	cluster.CreateDatabase("test_db", false, nodes...)
+
+	// only the node on the first host is up
+	err := cluster.SetNodeState(fakeClusterHosts[0], util.NodeUpState)
+	assert.NoError(t, err)
+	options := VFetchNodeStateOptionsFactory()
+	setFakeClusterOptions(&options.DatabaseOptions, cluster, fakeClusterHosts)
+	nodeStates, _, err := vcc.VFetchNodeState(context.Background(), &options)
+	assert.NoError(t, err)
+	upNodes := 0
+	for _, nodeState := range nodeStates {
+		if nodeState.State == util.NodeUpState {
+			upNodes++
+		}
+	}
+	assert.Equal(t, 1, upNodes)
+}
+
+func TestFindRemovedNodesOnNonDefaultPorts(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	const nmaPort, httpsPort = 15554, 18443
+	cluster.SetPorts(nmaPort, httpsPort)
+	vcc := makeFakeClusterCommands(cluster)
+
+	options := VRemoveNodeOptionsFactory()
+	setFakeClusterOptions(&options.DatabaseOptions, cluster, fakeClusterHosts)
+	options.NMAPort = nmaPort
+	options.HTTPSPort = httpsPort
+	options.CatalogPrefix = defaultPath
+	options.DataPrefix = defaultPath
+	createOptions := VCreateDatabaseOptionsFactory()
+	createOptions.DatabaseOptions = options.DatabaseOptions
+	_, _, err := vcc.VCreateDatabase(context.Background(), &createOptions)
+	assert.NoError(t, err)
+
+	// the node states are fetched on the ports of the database, so the node
+	// that is still in the catalog is found
+	ctx, call := vcc.startCall(context.Background(), commandRemoveNode, &options.DatabaseOptions)
+	defer call.finish(nil)
+	options.HostsToRemove = fakeClusterHosts[2:]
+	assert.True(t, vcc.findRemovedNodesInCatalog(ctx, &options, fakeClusterHosts[:2]))
+
+	// the node is not found once it has been removed
+	var remainingNodes []fakecluster.Node
+	for _, node := range cluster.Nodes() {
+		if node.Address != fakeClusterHosts[2] {
+			remainingNodes = append(remainingNodes, node)
+		}
+	}
+	cluster.CreateDatabase("test_db", false, remainingNodes...)
+	assert.False(t, vcc.findRemovedNodesInCatalog(ctx, &options, fakeClusterHosts[:2]))
+
+	// the nodes are not taken as removed when their states cannot be fetched
+	options.HTTPSPort = 0
+	options.NMAPort = 0
+	assert.True(t, vcc.findRemovedNodesInCatalog(ctx, &options, fakeClusterHosts[:2]))
+}
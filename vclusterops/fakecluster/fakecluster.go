@@ -0,0 +1,459 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package fakecluster emulates the NMA and the Vertica HTTPS service of the
+// hosts of a cluster, in process, so that the VClusterCommands APIs can be
+// tested without any host. A Cluster implements vclusterops.HTTPTransport:
+//
+//	cluster := fakecluster.New("192.168.1.101", "192.168.1.102")
+//	defer cluster.Close()
+//	vcc := vclusterops.VClusterCommands{Transport: cluster}
+//
+// The requests to all the hosts are served by a single TLS server. A request
+// to a host where the service is not running fails the same way as on the
+// network, with a refused connection, and the connections kept alive to a
+// service are closed when it stops. The state of the nodes can be scripted
+// and the handlers of the endpoints can be replaced.
+package fakecluster
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// Version is the Vertica version that the hosts report by default
+const Version = "v24.1.0"
+
+// Service is a service running on the hosts
+type Service int
+
+const (
+	NMA Service = iota
+	HTTPS
+)
+
+func (service Service) String() string {
+	if service == NMA {
+		return "NMA"
+	}
+	return "HTTPS"
+}
+
+// Node is a node of the database of the fake cluster
+type Node struct {
+	Name        string
+	Address     string
+	State       string
+	Subcluster  string
+	IsPrimary   bool
+	Sandbox     string
+	CatalogPath string
+	DataPaths   []string
+	DepotPath   string
+}
+
+// Request is a request that a host of the fake cluster received
+type Request struct {
+	Host     string
+	Service  Service
+	Method   string
+	Endpoint string
+	Query    url.Values
+	Header   http.Header
+	Body     string
+}
+
+// Handler serves a request sent to the given host
+type Handler func(host string, w http.ResponseWriter, r *http.Request)
+
+type route struct {
+	service  Service
+	method   string
+	endpoint string
+}
+
+type connKey struct{}
+
+type database struct {
+	name  string
+	isEon bool
+	nodes map[string]*Node // the keys are the node addresses
+}
+
+// Cluster is a set of fake hosts running the NMA, and the database created
+// on them
+type Cluster struct {
+	server    *httptest.Server
+	certs     certs
+	nmaPort   int
+	httpsPort int
+
+	mu       sync.Mutex
+	version  string
+	hosts    map[string]bool // the hosts that have the NMA running
+	db       *database
+	configs  map[string]string
+	handlers map[route]Handler
+	requests []Request
+	// the connections to the services, by the address they were made to
+	conns       map[net.Conn]string
+	connections int
+}
+
+// New starts a fake cluster with the NMA running on the given hosts. The
+// services listen on the default ports. The cluster must be closed after use.
+func New(hosts ...string) *Cluster {
+	cluster := &Cluster{
+		nmaPort:   util.DefaultNMAPort,
+		httpsPort: util.DefaultHTTPPort,
+		version:   Version,
+		hosts:     make(map[string]bool),
+		configs:   make(map[string]string),
+		handlers:  make(map[route]Handler),
+		conns:     make(map[net.Conn]string),
+	}
+	for _, host := range hosts {
+		cluster.hosts[host] = true
+	}
+
+	var err error
+	cluster.certs, err = generateCerts()
+	if err != nil {
+		panic(fmt.Sprintf("fakecluster: fail to generate the certificates: %v", err))
+	}
+	cluster.server = httptest.NewUnstartedServer(http.HandlerFunc(cluster.serveHTTP))
+	cluster.server.TLS = &tls.Config{Certificates: []tls.Certificate{cluster.certs.keyPair}}
+	cluster.server.Config.ConnContext = func(ctx context.Context, conn net.Conn) context.Context {
+		return context.WithValue(ctx, connKey{}, conn)
+	}
+	cluster.server.Config.ConnState = cluster.trackConn
+	cluster.server.StartTLS()
+	return cluster
+}
+
+// Close shuts down the server of the cluster
+func (cluster *Cluster) Close() {
+	cluster.server.Close()
+}
+
+// RoundTripper returns a round tripper that sends the requests to the fake
+// hosts. It implements vclusterops.HTTPTransport.
+func (cluster *Cluster) RoundTripper(tlsConfig *tls.Config) http.RoundTripper {
+	return &http.Transport{
+		TLSClientConfig: tlsConfig,
+		DialContext:     cluster.dialContext,
+	}
+}
+
+// Connections returns the number of connections made to the services of the
+// hosts. Each of them has cost a TLS handshake.
+func (cluster *Cluster) Connections() int {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	return cluster.connections
+}
+
+// Certs returns the key, the certificate and the CA certificate that the
+// clients of the cluster can use, as in the Key, Cert and CaCert options
+func (cluster *Cluster) Certs() (key, cert, caCert string) {
+	return cluster.certs.key, cluster.certs.cert, cluster.certs.cert
+}
+
+// SetVersion sets the Vertica version that the hosts report
+func (cluster *Cluster) SetVersion(version string) {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	cluster.version = version
+}
+
+// SetPorts makes the NMA and the HTTPS service of all the hosts listen on
+// the given ports
+func (cluster *Cluster) SetPorts(nmaPort, httpsPort int) {
+	cluster.mu.Lock()
+	cluster.nmaPort, cluster.httpsPort = nmaPort, httpsPort
+	cluster.mu.Unlock()
+	cluster.closeStoppedConns(nil)
+}
+
+// SetNMARunning starts or stops the NMA on a host
+func (cluster *Cluster) SetNMARunning(host string, running bool) {
+	cluster.mu.Lock()
+	cluster.hosts[host] = running
+	cluster.mu.Unlock()
+	cluster.closeStoppedConns(nil)
+}
+
+// CreateDatabase makes the cluster run an existing database with the given
+// nodes, replacing the one it has. The NMA is started on the nodes' hosts.
+func (cluster *Cluster) CreateDatabase(name string, isEon bool, nodes ...Node) {
+	cluster.mu.Lock()
+	cluster.db = &database{name: name, isEon: isEon, nodes: make(map[string]*Node)}
+	for i := range nodes {
+		node := nodes[i]
+		cluster.db.nodes[node.Address] = &node
+		cluster.hosts[node.Address] = true
+	}
+	cluster.mu.Unlock()
+	cluster.closeStoppedConns(nil)
+}
+
+// DatabaseName returns the name of the database, or an empty string if the
+// cluster has none
+func (cluster *Cluster) DatabaseName() string {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	if cluster.db == nil {
+		return ""
+	}
+	return cluster.db.name
+}
+
+// Nodes returns the nodes of the database, sorted by name
+func (cluster *Cluster) Nodes() []Node {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	return cluster.nodeList()
+}
+
+// Node returns the node on the given host
+func (cluster *Cluster) Node(host string) (Node, bool) {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	node := cluster.getNode(host)
+	if node == nil {
+		return Node{}, false
+	}
+	return *node, true
+}
+
+// SetNodeState sets the state of the node on the given host. The HTTPS
+// service of a node only runs when it is UP.
+func (cluster *Cluster) SetNodeState(host, state string) error {
+	cluster.mu.Lock()
+	node := cluster.getNode(host)
+	if node == nil {
+		cluster.mu.Unlock()
+		return fmt.Errorf("no node is found on host %s", host)
+	}
+	node.State = state
+	cluster.mu.Unlock()
+	cluster.closeStoppedConns(nil)
+	return nil
+}
+
+// Handle replaces the handler of an endpoint, e.g. "nodes" or
+// "catalog/database", of a service. The endpoint does not have the API
+// version prefix.
+func (cluster *Cluster) Handle(service Service, method, endpoint string, handler Handler) {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	cluster.handlers[route{service: service, method: method, endpoint: endpoint}] = handler
+}
+
+// Requests returns the requests that the hosts received, in order
+func (cluster *Cluster) Requests() []Request {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	return append([]Request(nil), cluster.requests...)
+}
+
+// getNode returns the node on the given host, or nil. The caller must hold
+// the lock of the cluster.
+func (cluster *Cluster) getNode(host string) *Node {
+	if cluster.db == nil {
+		return nil
+	}
+	return cluster.db.nodes[host]
+}
+
+// nodeList returns a copy of the nodes of the database sorted by name.
+// The caller must hold the lock of the cluster.
+func (cluster *Cluster) nodeList() []Node {
+	var nodes []Node
+	if cluster.db == nil {
+		return nodes
+	}
+	for _, node := range cluster.db.nodes {
+		nodes = append(nodes, *node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
+
+// getService returns the service that listens on the given port of a host,
+// and whether it is running. The caller must hold the lock of the cluster.
+func (cluster *Cluster) getService(host string, port int) (service Service, running bool) {
+	switch port {
+	case cluster.nmaPort:
+		return NMA, cluster.hosts[host]
+	case cluster.httpsPort:
+		node := cluster.getNode(host)
+		return HTTPS, node != nil && node.State == util.NodeUpState
+	}
+	return NMA, false
+}
+
+// isRunning returns whether the service at address is running. The caller
+// must hold the lock of the cluster.
+func (cluster *Cluster) isRunning(address string) bool {
+	host, port, err := splitHostPort(address)
+	if err != nil {
+		return false
+	}
+	_, running := cluster.getService(host, port)
+	return running
+}
+
+// trackConn counts the new connections to the server, forgets the closed
+// ones, and closes the ones that became idle after their service stopped
+func (cluster *Cluster) trackConn(conn net.Conn, state http.ConnState) {
+	cluster.mu.Lock()
+	stopped := false
+	switch state {
+	case http.StateNew:
+		cluster.connections++
+	case http.StateIdle:
+		address, ok := cluster.conns[conn]
+		stopped = ok && !cluster.isRunning(address)
+	case http.StateClosed, http.StateHijacked:
+		delete(cluster.conns, conn)
+	}
+	cluster.mu.Unlock()
+	if stopped {
+		conn.Close()
+	}
+}
+
+// closeStoppedConns closes the connections to the services that are not
+// running anymore, except the one serving the current request
+func (cluster *Cluster) closeStoppedConns(current net.Conn) {
+	var stoppedConns []net.Conn
+	cluster.mu.Lock()
+	for conn, address := range cluster.conns {
+		if conn != current && !cluster.isRunning(address) {
+			stoppedConns = append(stoppedConns, conn)
+			delete(cluster.conns, conn)
+		}
+	}
+	cluster.mu.Unlock()
+	for _, conn := range stoppedConns {
+		conn.Close()
+	}
+}
+
+// dialContext connects to the server of the cluster if the service at
+// address is running, and refuses the connection otherwise
+func (cluster *Cluster) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	cluster.mu.Lock()
+	running := cluster.isRunning(address)
+	cluster.mu.Unlock()
+	if !running {
+		return nil, &net.OpError{
+			Op:  "dial",
+			Net: network,
+			Err: os.NewSyscallError("connect", syscall.ECONNREFUSED),
+		}
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, cluster.server.Listener.Addr().String())
+}
+
+func (cluster *Cluster) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	host, port, err := splitHostPort(r.Host)
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	conn, _ := r.Context().Value(connKey{}).(net.Conn)
+	cluster.mu.Lock()
+	service, running := cluster.getService(host, port)
+	if !running {
+		// the service was stopped after the connection was made
+		cluster.mu.Unlock()
+		panic(http.ErrAbortHandler)
+	}
+	if conn != nil {
+		cluster.conns[conn] = r.Host
+	}
+	endpoint := strings.TrimPrefix(r.URL.Path, "/v1/")
+	cluster.requests = append(cluster.requests, Request{
+		Host:     host,
+		Service:  service,
+		Method:   r.Method,
+		Endpoint: endpoint,
+		Query:    r.URL.Query(),
+		Header:   r.Header.Clone(),
+		Body:     string(body),
+	})
+	handler, ok := cluster.handlers[route{service: service, method: r.Method, endpoint: endpoint}]
+	cluster.mu.Unlock()
+
+	switch {
+	case ok:
+		handler(host, w, r)
+	case service == NMA:
+		cluster.serveNMA(host, endpoint, w, r)
+	default:
+		cluster.serveHTTPS(host, endpoint, w, r)
+	}
+	// the request may have stopped services, like a shutdown does
+	cluster.closeStoppedConns(conn)
+}
+
+func splitHostPort(address string) (host string, port int, err error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, err
+	}
+	port, err = strconv.Atoi(portStr)
+	return host, port, err
+}
+
+// WriteJSON writes obj as the JSON body of a successful response
+func WriteJSON(w http.ResponseWriter, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(obj)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WriteProblem writes an RFC 7807 problem as the response
+func WriteProblem(w http.ResponseWriter, host string, id rfc7807.ProblemID, detail string) {
+	rfc7807.New(id).WithDetail(detail).WithHost(host).SendError(w)
+}
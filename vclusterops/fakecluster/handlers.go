@@ -0,0 +1,332 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fakecluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+const (
+	defaultSubcluster = "default_subcluster"
+	clientPort        = "5433"
+	controlPort       = "4803"
+)
+
+func (cluster *Cluster) serveNMA(host, endpoint string, w http.ResponseWriter, r *http.Request) {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+
+	switch r.Method + " " + endpoint {
+	case "GET health":
+		WriteJSON(w, map[string]string{"healthy": "true"})
+	case "GET vertica/version":
+		WriteJSON(w, map[string]string{"vertica_version": "Vertica Analytic Database " + cluster.version})
+	case "GET network-profiles":
+		WriteJSON(w, makeNetworkProfile(host))
+	case "POST directories/prepare":
+		cluster.prepareDirectories(host, w, r)
+	case "POST catalog/bootstrap":
+		cluster.bootstrapCatalog(host, w, r)
+	case "GET catalog/database":
+		cluster.readCatalogEditor(host, w)
+	case "POST nodes/start":
+		cluster.startNode(host, w)
+	case "GET config/vertica", "GET config/spread":
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, cluster.configs[endpoint])
+	case "POST config/vertica", "POST config/spread":
+		cluster.uploadConfig(host, endpoint, w, r)
+	default:
+		WriteProblem(w, host, rfc7807.BadRequest, fmt.Sprintf("endpoint %s %s is not supported by the fake NMA", r.Method, endpoint))
+	}
+}
+
+func (cluster *Cluster) serveHTTPS(host, endpoint string, w http.ResponseWriter, r *http.Request) {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+
+	switch r.Method + " " + endpoint {
+	case "GET nodes":
+		WriteJSON(w, map[string]any{"node_list": cluster.nodeStates(cluster.nodeList())})
+	case "POST nodes":
+		cluster.createNodes(host, w, r)
+	case "GET cluster":
+		WriteJSON(w, map[string]any{
+			"is_eon":                     cluster.db.isEon,
+			"db_name":                    cluster.db.name,
+			"commnual_storage_locations": []string{},
+		})
+	case "POST config/spread/reload":
+		WriteJSON(w, map[string]string{"detail": "Reloaded"})
+	case "GET startup/commands":
+		commands := make(map[string][]string)
+		for _, node := range cluster.db.nodes {
+			commands[node.Name] = cluster.startCommand(node)
+		}
+		WriteJSON(w, commands)
+	case "PUT cluster/k-safety":
+		WriteJSON(w, map[string]string{"detail": fmt.Sprintf("Marked design %s-safe", r.URL.Query().Get("k"))})
+	case "POST packages":
+		WriteJSON(w, map[string]any{"packages": []map[string]string{
+			{"package_name": "ComplexTypes", "install_status": "Success"},
+		}})
+	case "POST cluster/shutdown":
+		for _, node := range cluster.db.nodes {
+			node.State = util.NodeDownState
+		}
+		WriteJSON(w, map[string]string{"detail": "Shutdown: moveout complete"})
+	default:
+		nodeHost, found := strings.CutPrefix(endpoint, "nodes/")
+		if r.Method == http.MethodGet && found {
+			cluster.getNodeState(host, nodeHost, w)
+			return
+		}
+		WriteProblem(w, host, rfc7807.BadRequest, fmt.Sprintf("endpoint %s %s is not supported by the fake HTTPS service", r.Method, endpoint))
+	}
+}
+
+// nodeStates describes the nodes as the /nodes endpoint does
+func (cluster *Cluster) nodeStates(nodes []Node) []map[string]any {
+	states := []map[string]any{}
+	for i := range nodes {
+		node := &nodes[i]
+		states = append(states, map[string]any{
+			"name":            node.Name,
+			"address":         node.Address,
+			"state":           node.State,
+			"database":        cluster.db.name,
+			"catalog_path":    node.CatalogPath,
+			"data_path":       node.DataPaths,
+			"depot_path":      node.DepotPath,
+			"subcluster_name": node.Subcluster,
+			"is_primary":      node.IsPrimary,
+			"sandbox_name":    node.Sandbox,
+			"build_info":      cluster.version + "-0",
+		})
+	}
+	return states
+}
+
+func (cluster *Cluster) getNodeState(host, nodeHost string, w http.ResponseWriter) {
+	node := cluster.getNode(nodeHost)
+	if node == nil {
+		WriteProblem(w, host, rfc7807.GenericGetNodeInfoFailure, fmt.Sprintf("node %s is not found", nodeHost))
+		return
+	}
+	WriteJSON(w, map[string]any{"node_list": cluster.nodeStates([]Node{*node})})
+}
+
+func makeNetworkProfile(host string) map[string]string {
+	profile := map[string]string{
+		"name":      "eth0",
+		"address":   host,
+		"subnet":    host + "/128",
+		"netmask":   "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+		"broadcast": host,
+	}
+	if ip := net.ParseIP(host).To4(); ip != nil {
+		mask := net.CIDRMask(24, 32)
+		subnet := ip.Mask(mask)
+		broadcast := make(net.IP, len(subnet))
+		for i := range subnet {
+			broadcast[i] = subnet[i] | ^mask[i]
+		}
+		profile["subnet"] = subnet.String() + "/24"
+		profile["netmask"] = net.IP(mask).String()
+		profile["broadcast"] = broadcast.String()
+	}
+	return profile
+}
+
+func (cluster *Cluster) prepareDirectories(host string, w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		CatalogPath      string   `json:"catalog_path"`
+		DepotPath        string   `json:"depot_path"`
+		StorageLocations []string `json:"storage_locations"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		WriteProblem(w, host, rfc7807.BadRequest, err.Error())
+		return
+	}
+	created := map[string]string{request.CatalogPath: "created"}
+	for _, dir := range append(request.StorageLocations, request.DepotPath) {
+		if dir != "" {
+			created[dir] = "created"
+		}
+	}
+	WriteJSON(w, created)
+}
+
+func (cluster *Cluster) bootstrapCatalog(host string, w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		DBName          string `json:"db_name"`
+		NodeName        string `json:"node_name"`
+		CatalogPath     string `json:"catalog_path"`
+		StorageLocation string `json:"storage_location"`
+		CommunalStorage string `json:"communal_storage"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		WriteProblem(w, host, rfc7807.BadRequest, err.Error())
+		return
+	}
+	if cluster.db != nil {
+		WriteProblem(w, host, rfc7807.GenericBootstrapCatalogFailure,
+			fmt.Sprintf("database %s already exists", cluster.db.name))
+		return
+	}
+	cluster.db = &database{
+		name:  request.DBName,
+		isEon: request.CommunalStorage != "",
+		nodes: make(map[string]*Node),
+	}
+	cluster.db.nodes[host] = &Node{
+		Name:        request.NodeName,
+		Address:     host,
+		State:       util.NodeDownState,
+		Subcluster:  defaultSubcluster,
+		IsPrimary:   true,
+		CatalogPath: request.CatalogPath,
+		DataPaths:   []string{request.StorageLocation},
+	}
+	WriteJSON(w, map[string]string{
+		"bootstrap_catalog_stdout":      "Catalog successfully bootstrapped",
+		"bootstrap_catalog_stderr":      "",
+		"bootstrap_catalog_return_code": "0",
+	})
+}
+
+func (cluster *Cluster) startCommand(node *Node) []string {
+	return []string{
+		"/opt/vertica/bin/vertica",
+		"-D", node.CatalogPath,
+		"-C", cluster.db.name,
+		"-n", node.Name,
+		"-h", node.Address,
+		"-p", clientPort,
+		"-P", controlPort,
+		"-Y", "ipv4",
+	}
+}
+
+func (cluster *Cluster) readCatalogEditor(host string, w http.ResponseWriter) {
+	if cluster.getNode(host) == nil {
+		WriteProblem(w, host, rfc7807.CatalogPathNotExistError, "no catalog is found on the host")
+		return
+	}
+	nodes := []map[string]any{}
+	for _, node := range cluster.nodeList() {
+		nodes = append(nodes, map[string]any{
+			"name":              node.Name,
+			"address":           node.Address,
+			"catalog_path":      path.Join(node.CatalogPath, "Catalog"),
+			"is_primary":        node.IsPrimary,
+			"start_command":     cluster.startCommand(&node),
+			"storage_locations": node.DataPaths,
+			"sc_details": map[string]any{
+				"sc_name":       node.Subcluster,
+				"is_primary_sc": node.IsPrimary,
+				"is_default":    node.Subcluster == defaultSubcluster,
+				"sandbox":       node.Sandbox != "",
+			},
+		})
+	}
+	WriteJSON(w, map[string]any{
+		"name":     cluster.db.name,
+		"versions": map[string]int{"global": 1, "local": 1, "session": 1, "spread": 1, "transaction": 1, "two_phase_id": 1},
+		"nodes":    nodes,
+	})
+}
+
+func (cluster *Cluster) startNode(host string, w http.ResponseWriter) {
+	node := cluster.getNode(host)
+	if node == nil {
+		WriteProblem(w, host, rfc7807.CatalogPathNotExistError, "no node is found on the host")
+		return
+	}
+	node.State = util.NodeUpState
+	WriteJSON(w, map[string]any{
+		"dbLogPath":   path.Join(path.Dir(node.CatalogPath), "dbLog"),
+		"return_code": 0,
+	})
+}
+
+func (cluster *Cluster) uploadConfig(host, endpoint string, w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		CatalogPath string `json:"catalog_path"`
+		Content     string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		WriteProblem(w, host, rfc7807.BadRequest, err.Error())
+		return
+	}
+	cluster.configs[endpoint] = request.Content
+	fileName := "vertica.conf"
+	if endpoint == "config/spread" {
+		fileName = "spread.conf"
+	}
+	WriteJSON(w, map[string]string{"destination": path.Join(request.CatalogPath, fileName)})
+}
+
+func (cluster *Cluster) createNodes(host string, w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	subcluster := query.Get("subcluster")
+	if subcluster == "" {
+		subcluster = defaultSubcluster
+	}
+	// new nodes have the type of the subcluster they are added to
+	isPrimary := true
+	maxNodeNumber := 0
+	for _, node := range cluster.db.nodes {
+		if node.Subcluster == subcluster {
+			isPrimary = node.IsPrimary
+		}
+		number, err := strconv.Atoi(strings.TrimPrefix(node.Name, "v_"+cluster.db.name+"_node"))
+		if err == nil && number > maxNodeNumber {
+			maxNodeNumber = number
+		}
+	}
+
+	createdNodes := []map[string]string{}
+	for _, newHost := range strings.Split(query.Get("hosts"), ",") {
+		if cluster.getNode(newHost) != nil {
+			WriteProblem(w, host, rfc7807.BadRequest, fmt.Sprintf("host %s already has a node", newHost))
+			return
+		}
+		maxNodeNumber++
+		name := fmt.Sprintf("v_%s_node%04d", cluster.db.name, maxNodeNumber)
+		node := &Node{
+			Name:        name,
+			Address:     newHost,
+			State:       util.NodeDownState,
+			Subcluster:  subcluster,
+			IsPrimary:   isPrimary,
+			CatalogPath: path.Join(query.Get("catalog-prefix"), name+"_catalog"),
+			DataPaths:   []string{path.Join(query.Get("data-prefix"), name+"_data")},
+		}
+		cluster.db.nodes[newHost] = node
+		createdNodes = append(createdNodes, map[string]string{"name": name, "catalog_path": node.CatalogPath})
+	}
+	WriteJSON(w, map[string]any{"created_nodes": createdNodes})
+}
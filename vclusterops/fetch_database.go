@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -24,7 +25,7 @@ import (
 
 type VFetchCoordinationDatabaseOptions struct {
 	DatabaseOptions
-	Overwrite bool // overwrite existing config file at the same location
+	Overwrite bool // overwrite the database in the existing config file, which the caller writes
 
 	// hidden option
 	readOnly bool // this should be only used if we don't want to update the config file
@@ -54,14 +55,6 @@ func (opt *VFetchCoordinationDatabaseOptions) analyzeOptions() error {
 	// process correct catalog path
 	opt.CatalogPrefix = util.GetCleanPath(opt.CatalogPrefix)
 
-	// check existing config file at the same location
-	if !opt.readOnly && !opt.Overwrite {
-		if util.CanWriteAccessPath(opt.ConfigPath) == util.FileExist {
-			return fmt.Errorf("config file exists at %s. "+
-				"You can use --overwrite to overwrite this existing config file", opt.ConfigPath)
-		}
-	}
-
 	return nil
 }
 
@@ -72,7 +65,16 @@ func (opt *VFetchCoordinationDatabaseOptions) validateAnalyzeOptions(logger vlog
 	return opt.analyzeOptions()
 }
 
-func (vcc VClusterCommands) VFetchCoordinationDatabase(options *VFetchCoordinationDatabaseOptions) (VCoordinationDatabase, error) {
+func (vcc VClusterCommands) VFetchCoordinationDatabase(ctx context.Context,
+	options *VFetchCoordinationDatabaseOptions) (VCoordinationDatabase, *ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandConfigRecover, &options.DatabaseOptions)
+	vdb, err := vcc.fetchCoordinationDatabase(ctx, options)
+	report := call.finish(err)
+	return vdb, report, err
+}
+
+func (vcc VClusterCommands) fetchCoordinationDatabase(ctx context.Context,
+	options *VFetchCoordinationDatabaseOptions) (VCoordinationDatabase, error) {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -83,7 +85,7 @@ func (vcc VClusterCommands) VFetchCoordinationDatabase(options *VFetchCoordinati
 
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return vdb, err
+		return vdb, makeValidationProblem(err)
 	}
 
 	// pre-fill vdb from the user input
@@ -100,11 +102,10 @@ func (vcc VClusterCommands) VFetchCoordinationDatabase(options *VFetchCoordinati
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 
 	// nmaVDB is an object obtained from the read catalog editor result
 	// we use nmaVDB data to complete vdb
@@ -149,7 +150,7 @@ func (vcc VClusterCommands) produceRecoverConfigInstructions(
 
 	nmaHealthOp := makeNMAHealthOp(options.Hosts)
 
-	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(options.Hosts, options.DBName, options.CatalogPrefix,
+	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(options.Hosts, options.DBName, options.getCatalogPrefixes(options.Hosts),
 		true /* ignore internal errors */, vdb)
 
 	nmaReadCatalogEditorOp, err := makeNMAReadCatalogEditorOp(vdb)
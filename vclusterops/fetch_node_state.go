@@ -1,6 +1,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -50,7 +51,14 @@ func (options *VFetchNodeStateOptions) validateAnalyzeOptions(vcc VClusterComman
 
 // VFetchNodeState returns the node state (e.g., up or down) for each node in the cluster and any
 // error encountered.
-func (vcc VClusterCommands) VFetchNodeState(options *VFetchNodeStateOptions) ([]NodeInfo, error) {
+func (vcc VClusterCommands) VFetchNodeState(ctx context.Context, options *VFetchNodeStateOptions) ([]NodeInfo, *ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandFetchNodeState, &options.DatabaseOptions)
+	nodeStates, err := vcc.fetchNodeState(ctx, options)
+	report := call.finish(err)
+	return nodeStates, report, err
+}
+
+func (vcc VClusterCommands) fetchNodeState(ctx context.Context, options *VFetchNodeStateOptions) ([]NodeInfo, error) {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -59,7 +67,7 @@ func (vcc VClusterCommands) VFetchNodeState(options *VFetchNodeStateOptions) ([]
 
 	err := options.validateAnalyzeOptions(vcc)
 	if err != nil {
-		return nil, err
+		return nil, makeValidationProblem(err)
 	}
 
 	// produce list_allnodes instructions
@@ -69,11 +77,10 @@ func (vcc VClusterCommands) VFetchNodeState(options *VFetchNodeStateOptions) ([]
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	nodeStates := clusterOpEngine.execContext.nodesInfo
 	if runError == nil {
 		return nodeStates, nil
@@ -107,7 +114,7 @@ func (vcc VClusterCommands) VFetchNodeState(options *VFetchNodeStateOptions) ([]
 		fetchDatabaseOptions.DatabaseOptions = options.DatabaseOptions
 		fetchDatabaseOptions.readOnly = true
 
-		vdb, err := vcc.VFetchCoordinationDatabase(&fetchDatabaseOptions)
+		vdb, err := vcc.fetchCoordinationDatabase(ctx, &fetchDatabaseOptions)
 		if err != nil {
 			return downNodeStates, err
 		}
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -113,7 +114,16 @@ func (options *VFetchNodesDetailsOptions) validateAnalyzeOptions(log vlog.Printe
 }
 
 // VFetchNodesDetails can return nodes' details including node state and storage locations for the provided hosts
-func (vcc VClusterCommands) VFetchNodesDetails(options *VFetchNodesDetailsOptions) (nodesDetails NodesDetails, err error) {
+func (vcc VClusterCommands) VFetchNodesDetails(ctx context.Context,
+	options *VFetchNodesDetailsOptions) (nodesDetails NodesDetails, report *ExecutionReport, err error) {
+	ctx, call := vcc.startCall(ctx, commandFetchNodesDetails, &options.DatabaseOptions)
+	nodesDetails, err = vcc.fetchNodesDetails(ctx, options)
+	report = call.finish(err)
+	return nodesDetails, report, err
+}
+
+func (vcc VClusterCommands) fetchNodesDetails(ctx context.Context,
+	options *VFetchNodesDetailsOptions) (nodesDetails NodesDetails, err error) {
 	/*
 	 *   - Validate Options
 	 *   - Produce Instructions
@@ -123,7 +133,7 @@ func (vcc VClusterCommands) VFetchNodesDetails(options *VFetchNodesDetailsOption
 
 	err = options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return nodesDetails, err
+		return nodesDetails, makeValidationProblem(err)
 	}
 
 	hostsWithNodeDetails := make(hostNodeDetailsMap, len(options.Hosts))
@@ -133,10 +143,9 @@ func (vcc VClusterCommands) VFetchNodesDetails(options *VFetchNodesDetailsOption
 		return nodesDetails, fmt.Errorf("fail to produce instructions: %w", err)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
-	err = clusterOpEngine.run(vcc.Log)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		return nodesDetails, fmt.Errorf("failed to fetch node details on hosts %v: %w", options.Hosts, err)
 	}
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path"
@@ -154,17 +155,18 @@ func getInitiatorHost(primaryUpNodes, hostsToSkip []string) (string, error) {
 }
 
 // getVDBFromRunningDB will retrieve db configurations from a non-sandboxed host by calling https endpoints of a running db
-func (vcc VClusterCommands) getVDBFromRunningDB(vdb *VCoordinationDatabase, options *DatabaseOptions) error {
-	return vcc.getVDBFromRunningDBImpl(vdb, options, false, util.MainClusterSandbox)
+func (vcc VClusterCommands) getVDBFromRunningDB(ctx context.Context, vdb *VCoordinationDatabase, options *DatabaseOptions) error {
+	return vcc.getVDBFromRunningDBImpl(ctx, vdb, options, false, util.MainClusterSandbox)
 }
 
 // getVDBFromRunningDB will retrieve db configurations from any UP host by calling https endpoints of a running db
-func (vcc VClusterCommands) getVDBFromRunningDBIncludeSandbox(vdb *VCoordinationDatabase, options *DatabaseOptions, sandbox string) error {
-	return vcc.getVDBFromRunningDBImpl(vdb, options, true, sandbox)
+func (vcc VClusterCommands) getVDBFromRunningDBIncludeSandbox(ctx context.Context,
+	vdb *VCoordinationDatabase, options *DatabaseOptions, sandbox string) error {
+	return vcc.getVDBFromRunningDBImpl(ctx, vdb, options, true, sandbox)
 }
 
 // getVDBFromRunningDB will retrieve db configurations by calling https endpoints of a running db
-func (vcc VClusterCommands) getVDBFromRunningDBImpl(vdb *VCoordinationDatabase, options *DatabaseOptions,
+func (vcc VClusterCommands) getVDBFromRunningDBImpl(ctx context.Context, vdb *VCoordinationDatabase, options *DatabaseOptions,
 	allowUseSandboxRes bool, sandbox string) error {
 	err := options.setUsePassword(vcc.Log)
 	if err != nil {
@@ -186,9 +188,8 @@ func (vcc VClusterCommands) getVDBFromRunningDBImpl(vdb *VCoordinationDatabase,
 	var instructions []clusterOp
 	instructions = append(instructions, &httpsGetNodesInfoOp, &httpsGetClusterInfoOp)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		return fmt.Errorf("fail to retrieve database configurations, %w", err)
 	}
@@ -197,7 +198,7 @@ func (vcc VClusterCommands) getVDBFromRunningDBImpl(vdb *VCoordinationDatabase,
 }
 
 // getClusterInfoFromRunningDB will retrieve db configurations by calling https endpoints of a running db
-func (vcc VClusterCommands) getClusterInfoFromRunningDB(vdb *VCoordinationDatabase, options *DatabaseOptions) error {
+func (vcc VClusterCommands) getClusterInfoFromRunningDB(ctx context.Context, vdb *VCoordinationDatabase, options *DatabaseOptions) error {
 	err := options.setUsePassword(vcc.Log)
 	if err != nil {
 		return fmt.Errorf("fail to set userPassword while retrieving cluster configurations, %w", err)
@@ -212,9 +213,8 @@ func (vcc VClusterCommands) getClusterInfoFromRunningDB(vdb *VCoordinationDataba
 	var instructions []clusterOp
 	instructions = append(instructions, &httpsGetClusterInfoOp)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		return fmt.Errorf("fail to retrieve cluster configurations, %w", err)
 	}
@@ -17,6 +17,7 @@ package vclusterops
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"time"
 
 	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/tracing"
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
@@ -35,7 +37,16 @@ import (
 type httpAdapter struct {
 	opBase
 	host            string
+	nmaPort         int
+	httpsPort       int
 	respBodyHandler responseBodyHandler
+	transport       HTTPTransport
+	// the round trippers shared by the adapters of an engine run
+	transports *transportCache
+	// the requests and their retries are counted in it if it is set
+	metrics *opMetrics
+	// the timeouts of the requests
+	timeouts TimeoutPolicy
 }
 
 func makeHTTPAdapter(logger vlog.Printer) httpAdapter {
@@ -43,18 +54,24 @@ func makeHTTPAdapter(logger vlog.Printer) httpAdapter {
 	newHTTPAdapter.name = "HTTPAdapter"
 	newHTTPAdapter.logger = logger.WithName(newHTTPAdapter.name)
 	newHTTPAdapter.respBodyHandler = &responseBodyReader{}
+	newHTTPAdapter.nmaPort = util.DefaultNMAPort
+	newHTTPAdapter.httpsPort = util.DefaultHTTPPort
+	newHTTPAdapter.transport = &NetworkTransport{}
 	return newHTTPAdapter
 }
 
 // makeHTTPDownloadAdapter creates an HTTP adapter which will
 // download a response body to a file via streaming read and
-// buffered write, rather than copying the body to memory.
+// buffered write, rather than copying the body to memory. The bytes
+// downloaded from the host are sent to progress, if it is set.
 func makeHTTPDownloadAdapter(logger vlog.Printer,
-	destFilePath string) httpAdapter {
+	destFilePath string, progress *progressReporter, host string) httpAdapter {
 	newHTTPAdapter := makeHTTPAdapter(logger)
 	newHTTPAdapter.respBodyHandler = &responseBodyDownloader{
-		logger,
-		destFilePath,
+		logger:       logger,
+		destFilePath: destFilePath,
+		progress:     progress,
+		host:         host,
 	}
 	return newHTTPAdapter
 }
@@ -70,31 +87,115 @@ type responseBodyReader struct{}
 type responseBodyDownloader struct {
 	logger       vlog.Printer
 	destFilePath string
+	// the bytes downloaded from host are sent to it as DownloadEvents
+	progress *progressReporter
+	host     string
 }
 
 const (
 	certPathBase          = "/opt/vertica/config/https_certs"
-	nmaPort               = 5554
-	httpsPort             = 8443
-	defaultRequestTimeout = 300 // seconds
+	defaultRequestTimeout = 300 * time.Second
 )
 
+// portConfig holds the ports that the adapters connect to. A zero port
+// means the default port is used.
+type portConfig struct {
+	nmaPort   int
+	httpsPort int
+	// per-host ports that override nmaPort and httpsPort
+	hostPorts map[string]HostPorts
+}
+
+// getNMAPort returns the NMA port to use for the given host
+func (p *portConfig) getNMAPort(host string) int {
+	if hp, ok := p.hostPorts[host]; ok && hp.NMAPort != 0 {
+		return hp.NMAPort
+	}
+	if p.nmaPort != 0 {
+		return p.nmaPort
+	}
+	return util.DefaultNMAPort
+}
+
+// getHTTPSPort returns the Vertica HTTPS service port to use for the given host
+func (p *portConfig) getHTTPSPort(host string) int {
+	if hp, ok := p.hostPorts[host]; ok && hp.HTTPSPort != 0 {
+		return hp.HTTPSPort
+	}
+	if p.httpsPort != 0 {
+		return p.httpsPort
+	}
+	return util.DefaultHTTPPort
+}
+
 type certificatePaths struct {
 	certFile string
 	keyFile  string
 	caFile   string
 }
 
-func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+func (adapter *httpAdapter) sendRequest(ctx context.Context, request *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+	resultChannel <- adapter.sendRequestWithRetry(ctx, request)
+}
+
+// sendRequestWithRetry sends the request to the host, and sends it again
+// following the retry policy of the request if it failed on a retryable
+// error. The returned result is the one of the last attempt, and it
+// records the errors of the attempts that were retried.
+func (adapter *httpAdapter) sendRequestWithRetry(ctx context.Context, request *hostHTTPRequest) hostHTTPResult {
+	policy := request.getRetryPolicy()
+	startTime := time.Now()
+	var retriedErrs []error
+	for attempt := 1; ; attempt++ {
+		result := adapter.sendTracedRequest(ctx, request)
+		adapter.metrics.countRequest(request, &result)
+		result.attempts = attempt
+		result.retriedErrs = retriedErrs
+		if policy == nil || ctx.Err() != nil || !result.isRetryable() {
+			return result
+		}
+		backoff, ok := policy.getBackoff(attempt, time.Since(startTime))
+		if !ok {
+			return result
+		}
+
+		adapter.logger.PrintWarning("Attempt %d of request %s on host %s failed, retrying in %.1f seconds, details: %v",
+			attempt, request.Endpoint, adapter.host, backoff.Seconds(), result.err)
+		retriedErrs = append(retriedErrs, result.err)
+		adapter.metrics.countRetry(request)
+		if sleepWithContext(ctx, backoff) != nil {
+			return result
+		}
+	}
+}
+
+// sendTracedRequest sends the request to the host one time, in a span of
+// its own that is the child of the span of the op. The span is named after
+// the template of the endpoint, and the endpoint is one of its attributes.
+func (adapter *httpAdapter) sendTracedRequest(ctx context.Context, request *hostHTTPRequest) hostHTTPResult {
+	ctx, span := tracing.StartChild(ctx, request.Method+" "+request.getEndpointTemplate())
+	span.SetAttribute("http.method", request.Method)
+	span.SetAttribute("http.endpoint", request.Endpoint)
+	span.SetAttribute("http.host", adapter.host)
+	result := adapter.sendRequestOnce(ctx, request)
+	if result.statusCode != 0 {
+		span.SetAttribute("http.status_code", result.statusCode)
+	}
+	span.End(result.err)
+	return result
+}
+
+// sendRequestOnce sends the request to the host one time
+func (adapter *httpAdapter) sendRequestOnce(ctx context.Context, request *hostHTTPRequest) hostHTTPResult {
 	// build query params
 	queryParams := buildQueryParamString(request.QueryParams)
 
 	// set up the request URL
 	var port int
 	if request.IsNMACommand {
-		port = nmaPort
+		port = adapter.nmaPort
 	} else {
-		port = httpsPort
+		port = adapter.httpsPort
 	}
 
 	requestURL := fmt.Sprintf("https://%s:%d/%s%s",
@@ -103,19 +204,20 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 		request.Endpoint,
 		queryParams)
 	adapter.logger.Info("Request URL", "URL", requestURL)
+	if request.RequestData != "" && adapter.logger.IsDebugEnabled() {
+		adapter.logger.Debug("Request body", "URL", requestURL, "body", maskRequestBody(request.RequestData))
+	}
 
 	// whether use password (for HTTPS endpoints only)
 	usePassword, err := whetherUsePassword(request)
 	if err != nil {
-		resultChannel <- adapter.makeExceptionResult(err)
-		return
+		return adapter.makeExceptionResult(err)
 	}
 
 	// HTTP client
-	client, err := adapter.setupHTTPClient(request, usePassword, resultChannel)
+	client, err := adapter.setupHTTPClient(request, usePassword)
 	if err != nil {
-		resultChannel <- adapter.makeExceptionResult(err)
-		return
+		return adapter.makeExceptionResult(err)
 	}
 
 	// set up request body
@@ -126,15 +228,17 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 		requestBody = bytes.NewBuffer([]byte(request.RequestData))
 	}
 
-	// build HTTP request
-	req, err := http.NewRequest(request.Method, requestURL, requestBody)
+	// build HTTP request, which will be aborted if ctx is canceled
+	req, err := http.NewRequestWithContext(ctx, request.Method, requestURL, requestBody)
 	if err != nil {
 		err = fmt.Errorf("fail to build request %v on host %s, details %w",
 			request.Endpoint, adapter.host, err)
-		resultChannel <- adapter.makeExceptionResult(err)
-		return
+		return adapter.makeExceptionResult(err)
 	}
 
+	// send the trace context, so that the host can continue the trace
+	tracing.Inject(ctx, req.Header)
+
 	// set username and password
 	// which is only used for HTTPS endpoints
 	if usePassword {
@@ -146,13 +250,12 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 	if err != nil {
 		err = fmt.Errorf("fail to send request %v on host %s, details %w",
 			request.Endpoint, adapter.host, err)
-		resultChannel <- adapter.makeExceptionResult(err)
-		return
+		return adapter.makeExceptionResult(makeSendRequestProblem(adapter.host, err))
 	}
 	defer resp.Body.Close()
 
 	// generate and return the result
-	resultChannel <- adapter.generateResult(resp)
+	return adapter.generateResult(resp)
 }
 
 func (adapter *httpAdapter) generateResult(resp *http.Response) hostHTTPResult {
@@ -160,6 +263,10 @@ func (adapter *httpAdapter) generateResult(resp *http.Response) hostHTTPResult {
 	if err != nil {
 		return adapter.makeExceptionResult(err)
 	}
+	if adapter.logger.IsDebugEnabled() {
+		adapter.logger.Debug("Response body", "host", adapter.host, "statusCode", resp.StatusCode,
+			"body", maskRequestBody(bodyString))
+	}
 	if isSuccess(resp) {
 		return adapter.makeSuccessResult(bodyString, resp.StatusCode)
 	}
@@ -191,7 +298,14 @@ func (downloader *responseBodyDownloader) downloadFile(resp *http.Response) (byt
 		return 0, err
 	}
 	defer file.Close()
-	return io.Copy(file, resp.Body)
+	if downloader.progress == nil {
+		return io.Copy(file, resp.Body)
+	}
+	progressWriter := &downloadProgressWriter{writer: file, reporter: downloader.progress,
+		host: downloader.host, filePath: downloader.destFilePath, lastEventTime: time.Now()}
+	bytesWritten, err = io.Copy(progressWriter, resp.Body)
+	progressWriter.sendEvent()
+	return bytesWritten, err
 }
 
 // readResponseBody attempts to read the entire contents of the http response into bodyString
@@ -236,12 +350,13 @@ func (adapter *httpAdapter) makeExceptionResult(err error) hostHTTPResult {
 // makeFailResult is a factory method for hostHTTPResult when an error response
 // is received from a REST endpoint.
 func (adapter *httpAdapter) makeFailResult(header http.Header, respBody string, statusCode int) hostHTTPResult {
+	err := adapter.extractErrorFromResponse(header, respBody, statusCode)
 	return hostHTTPResult{
 		host:       adapter.host,
 		status:     FAILURE,
 		statusCode: statusCode,
 		content:    respBody,
-		err:        adapter.extractErrorFromResponse(header, respBody, statusCode),
+		err:        makeResponseProblem(adapter.host, statusCode, respBody, err),
 	}
 }
 
@@ -324,59 +439,61 @@ func (adapter *httpAdapter) buildCertsFromMemory(key, cert, caCert string) (tls.
 
 func (adapter *httpAdapter) setupHTTPClient(
 	request *hostHTTPRequest,
-	usePassword bool,
-	_ chan<- hostHTTPResult) (*http.Client, error) {
-	var client *http.Client
-
-	// set up request timeout
-	requestTimeout := time.Duration(defaultRequestTimeout)
-	if request.Timeout > 0 {
-		requestTimeout = time.Duration(request.Timeout)
-	} else if request.Timeout == -1 {
-		requestTimeout = time.Duration(0) // a Timeout of zero means no timeout.
+	usePassword bool) (*http.Client, error) {
+	// set up request timeout, where a timeout of zero means no timeout
+	requestTimeout := adapter.timeouts.getRequestTimeout(request.Timeout)
+
+	// the round tripper of the host is reused by the requests of the engine run,
+	// so that their connections are kept alive instead of made for each request
+	key := transportKey{host: adapter.host, usePassword: usePassword}
+	roundTripper, err := adapter.transports.get(key, func() (http.RoundTripper, error) {
+		tlsConfig, err := adapter.buildTLSConfig(request, usePassword)
+		if err != nil {
+			return nil, err
+		}
+		return adapter.transport.RoundTripper(tlsConfig), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: roundTripper,
+	}, nil
+}
+
+func (adapter *httpAdapter) buildTLSConfig(request *hostHTTPRequest, usePassword bool) (*tls.Config, error) {
 	if usePassword {
 		// TODO: we have to use `InsecureSkipVerify: true` here,
 		//       as password is used
 		//nolint:gosec
-		client = &http.Client{
-			Timeout: time.Second * requestTimeout,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true,
-				},
-			},
-		}
-	} else {
-		var cert tls.Certificate
-		var caCertPool *x509.CertPool
-		var err error
-		if request.UseCertsInOptions {
-			cert, caCertPool, err = adapter.buildCertsFromMemory(request.Certs.key, request.Certs.cert, request.Certs.caCert)
-		} else {
-			cert, caCertPool, err = adapter.buildCertsFromFile()
-		}
-		if err != nil {
-			return client, err
-		}
-		// for both http and nma, we have to use `InsecureSkipVerify: true` here
-		// because the certs are self signed at this time
-		// TODO: update the InsecureSkipVerify once we start to use non-self-signed certs
+		return &tls.Config{
+			InsecureSkipVerify: true,
+		}, nil
+	}
 
-		//nolint:gosec
-		client = &http.Client{
-			Timeout: time.Second * requestTimeout,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					Certificates:       []tls.Certificate{cert},
-					RootCAs:            caCertPool,
-					InsecureSkipVerify: true,
-				},
-			},
-		}
+	var cert tls.Certificate
+	var caCertPool *x509.CertPool
+	var err error
+	if request.UseCertsInOptions {
+		cert, caCertPool, err = adapter.buildCertsFromMemory(request.Certs.key, request.Certs.cert, request.Certs.caCert)
+	} else {
+		cert, caCertPool, err = adapter.buildCertsFromFile()
 	}
-	return client, nil
+	if err != nil {
+		return nil, err
+	}
+	// for both http and nma, we have to use `InsecureSkipVerify: true` here
+	// because the certs are self signed at this time
+	// TODO: update the InsecureSkipVerify once we start to use non-self-signed certs
+
+	//nolint:gosec
+	return &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		RootCAs:            caCertPool,
+		InsecureSkipVerify: true,
+	}, nil
 }
 
 func buildQueryParamString(queryParams map[string]string) string {
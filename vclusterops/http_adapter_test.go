@@ -16,17 +16,23 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 func TestBuildQueryParams(t *testing.T) {
@@ -214,3 +220,88 @@ func TestHandleGenericErrorResponse(t *testing.T) {
 	assert.False(t, ok)
 	assert.Contains(t, result.err.Error(), errorMessage)
 }
+
+func TestPortConfig(t *testing.T) {
+	// no ports given, the default ports should be used
+	ports := portConfig{}
+	assert.Equal(t, util.DefaultNMAPort, ports.getNMAPort("192.168.1.101"))
+	assert.Equal(t, util.DefaultHTTPPort, ports.getHTTPSPort("192.168.1.101"))
+
+	// database-wide ports
+	ports.nmaPort = 6554
+	ports.httpsPort = 9443
+	assert.Equal(t, 6554, ports.getNMAPort("192.168.1.101"))
+	assert.Equal(t, 9443, ports.getHTTPSPort("192.168.1.101"))
+
+	// per-host ports override the database-wide ones
+	ports.hostPorts = map[string]HostPorts{
+		"192.168.1.101": {NMAPort: 7554, HTTPSPort: 10443},
+		"192.168.1.102": {HTTPSPort: 11443},
+	}
+	assert.Equal(t, 7554, ports.getNMAPort("192.168.1.101"))
+	assert.Equal(t, 10443, ports.getHTTPSPort("192.168.1.101"))
+	assert.Equal(t, 6554, ports.getNMAPort("192.168.1.102"))
+	assert.Equal(t, 11443, ports.getHTTPSPort("192.168.1.102"))
+	assert.Equal(t, 6554, ports.getNMAPort("192.168.1.103"))
+	assert.Equal(t, 9443, ports.getHTTPSPort("192.168.1.103"))
+}
+
+// setupAdapterForServer points a dispatcher adapter to the given test server
+func setupAdapterForServer(t *testing.T, server *httptest.Server) httpAdapter {
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	assert.NoError(t, err)
+
+	dispatcher := makeHTTPRequestDispatcher(vlog.Printer{})
+	dispatcher.ports = portConfig{hostPorts: map[string]HostPorts{
+		serverURL.Hostname(): {HTTPSPort: port},
+	}}
+	adapter := makeHTTPAdapter(vlog.Printer{})
+	dispatcher.setAdapterHost(&adapter, serverURL.Hostname())
+	return adapter
+}
+
+func TestSendRequestToConfiguredPort(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+	adapter := setupAdapterForServer(t, server)
+
+	password := "password"
+	request := hostHTTPRequest{Method: GetMethod, Username: "dbadmin", Password: &password}
+	request.buildHTTPSEndpoint("nodes")
+	resultChannel := make(chan hostHTTPResult, 1)
+	adapter.sendRequest(context.Background(), &request, resultChannel)
+	result := <-resultChannel
+	assert.NoError(t, result.err)
+	assert.Equal(t, SUCCESS, result.status)
+	assert.Equal(t, "/v1/nodes", result.content)
+}
+
+func TestSendRequestCanceled(t *testing.T) {
+	requestReceived := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		close(requestReceived)
+		// hold the request until the client goes away
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	adapter := setupAdapterForServer(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-requestReceived
+		cancel()
+	}()
+
+	password := "password"
+	request := hostHTTPRequest{Method: GetMethod, Username: "dbadmin", Password: &password}
+	request.buildHTTPSEndpoint("nodes")
+	resultChannel := make(chan hostHTTPResult, 1)
+	adapter.sendRequest(ctx, &request, resultChannel)
+	result := <-resultChannel
+	assert.Equal(t, EXCEPTION, result.status)
+	assert.ErrorIs(t, result.err, context.Canceled)
+}
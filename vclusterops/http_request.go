@@ -15,6 +15,11 @@
 
 package vclusterops
 
+import (
+	"strings"
+	"time"
+)
+
 type hostHTTPRequest struct {
 	Method       string
 	Endpoint     string
@@ -24,11 +29,25 @@ type hostHTTPRequest struct {
 	Username     string // optional, for HTTPS endpoints only
 	// string pointer is used here as we need to check whether the password has been set
 	Password *string // optional, for HTTPS endpoints only
-	Timeout  int     // optional, set it if an Op needs longer time to complete
+	// optional, set it if an Op needs a different time to complete than the
+	// Request timeout of the TimeoutPolicy. A negative one means no timeout.
+	Timeout time.Duration
 
 	// optional, for calling NMA/Vertica HTTPS endpoints. If Username/Password is set, that takes precedence over this for HTTPS calls.
 	UseCertsInOptions bool
 	Certs             httpsCerts
+
+	// Idempotent is set by ops whose endpoint can be safely called more than
+	// once, such as GET nodes. Only idempotent requests are retried.
+	Idempotent bool
+	// optional, the retry policy of an idempotent request. If it is not set,
+	// the one of the clusterHTTPRequest or the default one is used.
+	RetryPolicy retryPolicy
+
+	// EndpointTemplate is the endpoint with placeholders for its parameters,
+	// such as v1/nodes/{host}. The metrics are labeled with it, so that they
+	// do not have a series for each host, node or subcluster.
+	EndpointTemplate string
 }
 
 type httpsCerts struct {
@@ -37,14 +56,60 @@ type httpsCerts struct {
 	caCert string
 }
 
-func (req *hostHTTPRequest) buildNMAEndpoint(url string) {
+// buildNMAEndpoint sets the endpoint of an NMA request. The placeholders of
+// the url, such as {host}, are replaced by the params in order.
+func (req *hostHTTPRequest) buildNMAEndpoint(url string, params ...string) {
 	req.IsNMACommand = true
-	req.Endpoint = NMACurVersion + url
+	req.EndpointTemplate = NMACurVersion + url
+	req.Endpoint = NMACurVersion + fillEndpointTemplate(url, params)
 }
 
-func (req *hostHTTPRequest) buildHTTPSEndpoint(url string) {
+// buildHTTPSEndpoint sets the endpoint of an HTTPS request. The placeholders
+// of the url, such as {subcluster}, are replaced by the params in order.
+func (req *hostHTTPRequest) buildHTTPSEndpoint(url string, params ...string) {
 	req.IsNMACommand = false
-	req.Endpoint = HTTPCurVersion + url
+	req.EndpointTemplate = HTTPCurVersion + url
+	req.Endpoint = HTTPCurVersion + fillEndpointTemplate(url, params)
+}
+
+// fillEndpointTemplate replaces the placeholders of the template with the
+// params, in the order they appear
+func fillEndpointTemplate(template string, params []string) string {
+	var endpoint strings.Builder
+	rest := template
+	for _, param := range params {
+		start := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if start < 0 || end < start {
+			break
+		}
+		endpoint.WriteString(rest[:start])
+		endpoint.WriteString(param)
+		rest = rest[end+1:]
+	}
+	endpoint.WriteString(rest)
+	return endpoint.String()
+}
+
+// getEndpointTemplate returns the template of the endpoint, or the endpoint
+// of a request that was not built from a template
+func (req *hostHTTPRequest) getEndpointTemplate() string {
+	if req.EndpointTemplate == "" {
+		return req.Endpoint
+	}
+	return req.EndpointTemplate
+}
+
+// getRetryPolicy returns the retry policy of the request,
+// or nil if the request should not be retried
+func (req *hostHTTPRequest) getRetryPolicy() retryPolicy {
+	if !req.Idempotent {
+		return nil
+	}
+	if req.RetryPolicy == nil {
+		return defaultRetryPolicy
+	}
+	return req.RetryPolicy
 }
 
 // this is used as the "ATModuleBase" in Admintools
@@ -53,4 +118,11 @@ type clusterHTTPRequest struct {
 	ResultCollection  map[string]hostHTTPResult
 	SemVar            semVer
 	Name              string
+	// optional, the retry policy of the idempotent host requests
+	// that do not have their own one
+	RetryPolicy retryPolicy
+	// optional, the maximum number of host requests sent at once, for the
+	// ops with heavy requests. The lower of it and the limit of the engine
+	// is used.
+	MaxConcurrency int
 }
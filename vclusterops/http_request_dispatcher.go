@@ -16,48 +16,82 @@
 package vclusterops
 
 import (
+	"context"
+
 	"github.com/theckman/yacspin"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 type requestDispatcher struct {
 	opBase
-	pool adapterPool
+	pool       *adapterPool
+	ports      portConfig
+	transport  HTTPTransport
+	transports *transportCache
+	progress   *progressReporter
+	metrics    *opMetrics
+	timeouts   TimeoutPolicy
 }
 
 func makeHTTPRequestDispatcher(logger vlog.Printer) requestDispatcher {
 	newHTTPRequestDispatcher := requestDispatcher{}
 	newHTTPRequestDispatcher.name = "HTTPRequestDispatcher"
 	newHTTPRequestDispatcher.logger = logger.WithName(newHTTPRequestDispatcher.name)
+	newHTTPRequestDispatcher.pool = makeAdapterPool(newHTTPRequestDispatcher.logger)
+	newHTTPRequestDispatcher.transports = makeTransportCache()
 
 	return newHTTPRequestDispatcher
 }
 
+// copyForConcurrentOp returns a dispatcher with its own adapter pool, for an
+// op that runs at the same time as other ops. The connections to the hosts
+// are still shared.
+func (dispatcher *requestDispatcher) copyForConcurrentOp() requestDispatcher {
+	newDispatcher := *dispatcher
+	newDispatcher.pool = makeAdapterPool(dispatcher.logger)
+	newDispatcher.pool.maxConcurrency = dispatcher.pool.maxConcurrency
+	newDispatcher.pool.progress = dispatcher.pool.progress
+	return newDispatcher
+}
+
 // set up the pool connection for each host
 func (dispatcher *requestDispatcher) setup(hosts []string) {
-	dispatcher.pool = getPoolInstance(dispatcher.logger)
-
-	dispatcher.pool.connections = make(map[string]adapter)
+	connections := make(map[string]adapter)
 	for _, host := range hosts {
 		adapter := makeHTTPAdapter(dispatcher.logger)
-		adapter.host = host
-		dispatcher.pool.connections[host] = &adapter
+		dispatcher.setAdapterHost(&adapter, host)
+		connections[host] = &adapter
 	}
+	dispatcher.pool.setConnections(connections)
 }
 
 // set up the pool connection for each host to download a file
 func (dispatcher *requestDispatcher) setupForDownload(hosts []string,
 	hostToFilePathsMap map[string]string) {
-	dispatcher.pool = getPoolInstance(dispatcher.logger)
-
+	connections := make(map[string]adapter)
 	for _, host := range hosts {
-		adapter := makeHTTPDownloadAdapter(dispatcher.logger, hostToFilePathsMap[host])
-		adapter.host = host
-		dispatcher.pool.connections[host] = &adapter
+		adapter := makeHTTPDownloadAdapter(dispatcher.logger, hostToFilePathsMap[host], dispatcher.progress, host)
+		dispatcher.setAdapterHost(&adapter, host)
+		connections[host] = &adapter
+	}
+	dispatcher.pool.addConnections(connections)
+}
+
+// setAdapterHost points the adapter to the given host, using the ports
+// configured for that host and the transports of the dispatcher
+func (dispatcher *requestDispatcher) setAdapterHost(adapter *httpAdapter, host string) {
+	adapter.host = host
+	adapter.nmaPort = dispatcher.ports.getNMAPort(host)
+	adapter.httpsPort = dispatcher.ports.getHTTPSPort(host)
+	if dispatcher.transport != nil {
+		adapter.transport = dispatcher.transport
 	}
+	adapter.transports = dispatcher.transports
+	adapter.metrics = dispatcher.metrics
+	adapter.timeouts = dispatcher.timeouts
 }
 
-func (dispatcher *requestDispatcher) sendRequest(httpRequest *clusterHTTPRequest, spinner *yacspin.Spinner) error {
+func (dispatcher *requestDispatcher) sendRequest(ctx context.Context, httpRequest *clusterHTTPRequest, spinner *yacspin.Spinner) error {
 	dispatcher.logger.Info("HTTP request dispatcher's sendRequest is called")
-	return dispatcher.pool.sendRequest(httpRequest, spinner)
+	return dispatcher.pool.sendRequest(ctx, httpRequest, spinner)
 }
@@ -0,0 +1,128 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HTTPTransport makes the round trippers that the HTTP adapters use to send
+// requests to the NMA and the Vertica HTTPS service. tlsConfig holds the
+// client certificates, or none if the request uses a password.
+//
+// Setting VClusterCommands.Transport replaces the network, for example with
+// the fake cluster of the vclusterops/fakecluster package in tests.
+type HTTPTransport interface {
+	RoundTripper(tlsConfig *tls.Config) http.RoundTripper
+}
+
+const (
+	defaultMaxIdleConnsPerHost = 2
+	defaultIdleConnTimeout     = 30 * time.Second
+)
+
+// NetworkTransport is the HTTPTransport that connects to the hosts over the
+// network. It is used when VClusterCommands.Transport is not set.
+//
+// A round tripper is made for each host in an op engine run, and is shared by
+// all the ops of the run. Its connections are kept alive, so the requests to
+// a host, like the ones polling the node states, do not make a new TLS
+// handshake each time. HTTP/2 is used if the service supports it.
+type NetworkTransport struct {
+	// the maximum number of idle connections kept to a service of a host,
+	// 0 means defaultMaxIdleConnsPerHost
+	MaxIdleConnsPerHost int
+	// how long an idle connection is kept before it is closed,
+	// 0 means defaultIdleConnTimeout
+	IdleConnTimeout time.Duration
+}
+
+func (transport *NetworkTransport) RoundTripper(tlsConfig *tls.Config) http.RoundTripper {
+	maxIdleConnsPerHost := transport.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost <= 0 {
+		maxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	idleConnTimeout := transport.IdleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = defaultIdleConnTimeout
+	}
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSClientConfig:     tlsConfig,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+	}
+}
+
+// transportKey identifies the round tripper of a host. The requests that use
+// a password do not send the client certificates, so they need another one.
+type transportKey struct {
+	host        string
+	usePassword bool
+}
+
+// transportCache holds the round trippers made in an op engine run, so that
+// the adapters of all the ops reuse the connections to the hosts
+type transportCache struct {
+	mu            sync.Mutex
+	roundTrippers map[transportKey]http.RoundTripper
+}
+
+func makeTransportCache() *transportCache {
+	return &transportCache{roundTrippers: make(map[transportKey]http.RoundTripper)}
+}
+
+// get returns the round tripper of the key, calling makeRoundTripper if
+// there is none yet. Without a cache, a new round tripper is made each time.
+func (cache *transportCache) get(key transportKey,
+	makeRoundTripper func() (http.RoundTripper, error)) (http.RoundTripper, error) {
+	if cache == nil {
+		return makeRoundTripper()
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if roundTripper, ok := cache.roundTrippers[key]; ok {
+		return roundTripper, nil
+	}
+	roundTripper, err := makeRoundTripper()
+	if err != nil {
+		return nil, err
+	}
+	cache.roundTrippers[key] = roundTripper
+	return roundTripper, nil
+}
+
+// closeIdleConnections closes the connections of the round trippers that
+// are not in use, once the engine run is done
+func (cache *transportCache) closeIdleConnections() {
+	type closeIdler interface {
+		CloseIdleConnections()
+	}
+	if cache == nil {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for _, roundTripper := range cache.roundTrippers {
+		if transport, ok := roundTripper.(closeIdler); ok {
+			transport.CloseIdleConnections()
+		}
+	}
+}
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -81,7 +82,7 @@ func (op *httpsAddSubclusterOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
-		httpRequest.buildHTTPSEndpoint("subclusters/" + op.scName)
+		httpRequest.buildHTTPSEndpoint("subclusters/{subcluster}", op.scName)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -108,8 +109,8 @@ func (op *httpsAddSubclusterOp) prepare(execContext *opEngineExecContext) error
 	return op.setupClusterHTTPRequest(hosts)
 }
 
-func (op *httpsAddSubclusterOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsAddSubclusterOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -150,6 +151,12 @@ func (op *httpsAddSubclusterOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
-func (op *httpsAddSubclusterOp) finalize(_ *opEngineExecContext) error {
+func (op *httpsAddSubclusterOp) finalize(execContext *opEngineExecContext) error {
+	httpsDropSubclusterOp, err := makeHTTPSDropSubclusterOp([]string{execContext.upHosts[0]}, op.scName,
+		op.useHTTPPassword, op.userName, op.httpsPassword)
+	if err != nil {
+		return err
+	}
+	execContext.registerUndo("drop subcluster "+op.scName, &httpsDropSubclusterOp)
 	return nil
 }
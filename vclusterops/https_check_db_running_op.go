@@ -16,9 +16,9 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -398,42 +398,35 @@ func (op *httpsCheckRunningDBOp) checkProcessedResult(sandboxedHosts map[string]
 	return false
 }
 
-func (op *httpsCheckRunningDBOp) execute(execContext *opEngineExecContext) error {
+func (op *httpsCheckRunningDBOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
 	op.logger.Info("Execute() called", "opType", op.opType)
 	switch op.opType {
 	case CreateDB, StartDB, ReviveDB:
-		return op.checkDBConnection(execContext)
+		return op.checkDBConnection(ctx, execContext)
 	case StopDB, StopSC:
-		return op.pollForDBDown(execContext)
+		return op.pollForDBDown(ctx, execContext)
 	}
 
 	return fmt.Errorf("unknown operation found in HTTPCheckRunningDBOp")
 }
 
-func (op *httpsCheckRunningDBOp) pollForDBDown(execContext *opEngineExecContext) error {
+func (op *httpsCheckRunningDBOp) pollForDBDown(ctx context.Context, execContext *opEngineExecContext) error {
 	// start the polling
 	startTime := time.Now()
-	// for tests
-	timeoutSecondStr := util.GetEnv("NODE_STATE_POLLING_TIMEOUT", strconv.Itoa(StopDBTimeout))
-	timeoutSecond, err := strconv.Atoi(timeoutSecondStr)
-	if err != nil {
-		return fmt.Errorf("invalid timeout value %s: %w", timeoutSecondStr, err)
-	}
-
-	// do not poll, just return succeed
-	if timeoutSecond <= 0 {
-		return nil
-	}
-	duration := time.Duration(timeoutSecond) * time.Second
-	count := 0
-	for endTime := startTime.Add(duration); ; {
-		if time.Now().After(endTime) {
+	// the timeout is the one of pollState, which polls forever if it is negative
+	timeout := execContext.dispatcher.timeouts.getPollingTimeout(StopDBTimeout)
+	schedule := op.getPollingSchedule()
+	var err error
+	for iteration, endTime := 1, startTime.Add(timeout); ; iteration++ {
+		if timeout >= 0 && time.Now().After(endTime) {
 			break
 		}
-		if count > 0 {
-			time.Sleep(PollingInterval * time.Second)
+		if iteration > 1 {
+			if err = sleepWithContext(ctx, schedule.getInterval(iteration-1)); err != nil {
+				return err
+			}
 		}
-		err = execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
+		err = execContext.dispatcher.sendRequest(ctx, &op.clusterHTTPRequest, op.spinner)
 		if err != nil {
 			return fmt.Errorf("fail to dispatch request %v: %w", op.clusterHTTPRequest, err)
 		}
@@ -446,20 +439,19 @@ func (op *httpsCheckRunningDBOp) pollForDBDown(execContext *opEngineExecContext)
 		} else {
 			return nil
 		}
-		count++
 	}
 	// timeout
 	target := "DB"
 	if op.opType == StopSC {
 		target = "subcluster"
 	}
-	msg := fmt.Sprintf("the %s is still up after %s seconds", target, timeoutSecondStr)
+	msg := fmt.Sprintf("the %s is still up after %s", target, timeout)
 	op.logger.PrintWarning(msg)
-	return errors.New(msg)
+	return makeProblem(rfc7807.PollingTimeout, "", errors.New(msg))
 }
 
-func (op *httpsCheckRunningDBOp) checkDBConnection(execContext *opEngineExecContext) error {
-	err := execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
+func (op *httpsCheckRunningDBOp) checkDBConnection(ctx context.Context, execContext *opEngineExecContext) error {
+	err := execContext.dispatcher.sendRequest(ctx, &op.clusterHTTPRequest, op.spinner)
 	if err != nil {
 		return fmt.Errorf("fail to dispatch request %v: %w", op.clusterHTTPRequest, err)
 	}
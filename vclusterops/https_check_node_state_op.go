@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -56,6 +57,7 @@ func (op *httpsCheckNodeStateOp) setupClusterHTTPRequest(hosts []string) error {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
 		httpRequest.buildHTTPSEndpoint("nodes")
+		httpRequest.Idempotent = true
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -72,8 +74,8 @@ func (op *httpsCheckNodeStateOp) prepare(execContext *opEngineExecContext) error
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsCheckNodeStateOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsCheckNodeStateOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
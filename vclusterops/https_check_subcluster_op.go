@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -54,7 +55,7 @@ func (op *httpsCheckSubclusterOp) setupClusterHTTPRequest(hosts []string) error
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
-		httpRequest.buildHTTPSEndpoint("subclusters/" + op.scName)
+		httpRequest.buildHTTPSEndpoint("subclusters/{subcluster}", op.scName)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -74,8 +75,8 @@ func (op *httpsCheckSubclusterOp) prepare(execContext *opEngineExecContext) erro
 	return op.setupClusterHTTPRequest(execContext.upHosts)
 }
 
-func (op *httpsCheckSubclusterOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsCheckSubclusterOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
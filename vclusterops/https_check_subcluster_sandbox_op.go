@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -65,8 +66,8 @@ func (op *httpsCheckSubclusterSandboxOp) prepare(execContext *opEngineExecContex
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsCheckSubclusterSandboxOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsCheckSubclusterSandboxOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
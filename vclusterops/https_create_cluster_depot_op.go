@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -83,8 +84,8 @@ func (op *httpsCreateDepotOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsCreateDepotOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsCreateDepotOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
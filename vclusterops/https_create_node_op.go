@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -26,6 +27,10 @@ type httpsCreateNodeOp struct {
 	opBase
 	opHTTPSBase
 	RequestParams map[string]string
+	isEon         bool
+	// whether the created nodes are dropped if a later op fails
+	undoable     bool
+	createdNodes []string
 }
 
 func makeHTTPSCreateNodeOp(newNodeHosts []string, bootstrapHost []string,
@@ -35,6 +40,7 @@ func makeHTTPSCreateNodeOp(newNodeHosts []string, bootstrapHost []string,
 	op.name = "HTTPSCreateNodeOp"
 	op.description = "Create node in catalog"
 	op.hosts = bootstrapHost
+	op.isEon = vdb.IsEon
 	op.RequestParams = make(map[string]string)
 	// HTTPS create node endpoint requires passing everything before node name
 	op.RequestParams["catalog-prefix"] = vdb.CatalogPrefix + "/" + vdb.Name
@@ -89,15 +95,29 @@ func (op *httpsCreateNodeOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsCreateNodeOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsCreateNodeOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
 	return op.processResult(execContext)
 }
 
-func (op *httpsCreateNodeOp) finalize(_ *opEngineExecContext) error {
+func (op *httpsCreateNodeOp) finalize(execContext *opEngineExecContext) error {
+	if !op.undoable || len(op.createdNodes) == 0 {
+		return nil
+	}
+	var undoInstructions []clusterOp
+	for _, nodeName := range op.createdNodes {
+		// the created nodes are down, so they are dropped with cascade in Eon mode
+		httpsDropNodeOp, err := makeHTTPSDropNodeOp(nodeName, op.hosts,
+			op.useHTTPPassword, op.userName, op.httpsPassword, op.isEon)
+		if err != nil {
+			return err
+		}
+		undoInstructions = append(undoInstructions, &httpsDropNodeOp)
+	}
+	execContext.registerUndo(fmt.Sprintf("drop the created nodes %v", op.createdNodes), undoInstructions...)
 	return nil
 }
 
@@ -120,10 +140,14 @@ func (op *httpsCreateNodeOp) processResult(_ *opEngineExecContext) error {
 				allErrs = errors.Join(allErrs, err)
 				continue
 			}
-			_, ok := responseObj["created_nodes"]
+			createdNodes, ok := responseObj["created_nodes"]
 			if !ok {
 				err = fmt.Errorf(`[%s] response does not contain field "created_nodes"`, op.name)
 				allErrs = errors.Join(allErrs, err)
+				continue
+			}
+			for _, createdNode := range createdNodes {
+				op.createdNodes = append(op.createdNodes, createdNode["name"])
 			}
 		} else {
 			allErrs = errors.Join(allErrs, result.err)
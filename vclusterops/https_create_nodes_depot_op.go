@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -56,7 +57,7 @@ func (op *httpsCreateNodesDepotOp) setupClusterHTTPRequest(hosts []string) error
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
 		node := op.HostNodeMap[host]
-		httpRequest.buildHTTPSEndpoint("nodes/" + node.Name + "/depot")
+		httpRequest.buildHTTPSEndpoint("nodes/{node}/depot", node.Name)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -77,8 +78,8 @@ func (op *httpsCreateNodesDepotOp) prepare(execContext *opEngineExecContext) err
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsCreateNodesDepotOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsCreateNodesDepotOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,9 +16,11 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"github.com/vertica/vcluster/rfc7807"
 	"github.com/vertica/vcluster/vclusterops/util"
 )
 
@@ -54,7 +56,7 @@ func (op *httpsDropSubclusterOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
-		httpRequest.buildHTTPSEndpoint("subclusters/" + op.scName + "/drop")
+		httpRequest.buildHTTPSEndpoint("subclusters/{subcluster}/drop", op.scName)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -72,8 +74,8 @@ func (op *httpsDropSubclusterOp) prepare(execContext *opEngineExecContext) error
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsDropSubclusterOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsDropSubclusterOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -87,8 +89,8 @@ func (op *httpsDropSubclusterOp) processResult(_ *opEngineExecContext) error {
 		op.logResponse(host, result)
 
 		if result.isUnauthorizedRequest() {
-			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return makeProblem(rfc7807.WrongCredentials, host,
+				fmt.Errorf("[%s] wrong password/certificate for https service on host %s", op.name, host))
 		}
 
 		if result.isPassing() {
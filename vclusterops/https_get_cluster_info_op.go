@@ -16,9 +16,11 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"github.com/vertica/vcluster/rfc7807"
 	"github.com/vertica/vcluster/vclusterops/util"
 )
 
@@ -74,8 +76,8 @@ func (op *httpsGetClusterInfoOp) prepare(execContext *opEngineExecContext) error
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsGetClusterInfoOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsGetClusterInfoOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -94,8 +96,8 @@ func (op *httpsGetClusterInfoOp) processResult(_ *opEngineExecContext) error {
 		op.logResponse(host, result)
 
 		if result.isUnauthorizedRequest() {
-			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return makeProblem(rfc7807.WrongCredentials, host,
+				fmt.Errorf("[%s] wrong password/certificate for https service on host %s", op.name, host))
 		}
 
 		if result.isPassing() {
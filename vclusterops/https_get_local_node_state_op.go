@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -69,8 +70,8 @@ func (op *httpsGetLocalNodeStateOp) prepare(execContext *opEngineExecContext) er
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsGetLocalNodeStateOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsGetLocalNodeStateOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,10 +16,12 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/vertica/vcluster/rfc7807"
 	"github.com/vertica/vcluster/vclusterops/util"
 )
 
@@ -59,6 +61,7 @@ func (op *httpsGetNodesInfoOp) setupClusterHTTPRequest(hosts []string) error {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
 		httpRequest.buildHTTPSEndpoint("nodes")
+		httpRequest.Idempotent = true
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -76,8 +79,8 @@ func (op *httpsGetNodesInfoOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsGetNodesInfoOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsGetNodesInfoOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -112,8 +115,8 @@ func (op *httpsGetNodesInfoOp) processResult(_ *opEngineExecContext) error {
 		op.logResponse(host, result)
 
 		if result.isUnauthorizedRequest() {
-			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return makeProblem(rfc7807.WrongCredentials, host,
+				fmt.Errorf("[%s] wrong password/certificate for https service on host %s", op.name, host))
 		}
 
 		if result.isPassing() {
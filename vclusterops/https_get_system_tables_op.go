@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -81,8 +82,8 @@ func (op *httpsGetSystemTablesOp) prepare(execContext *opEngineExecContext) erro
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsGetSystemTablesOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsGetSystemTablesOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -83,8 +84,8 @@ func (op *httpsInstallPackagesOp) prepare(execContext *opEngineExecContext) erro
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsInstallPackagesOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsInstallPackagesOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -51,7 +52,7 @@ func (op *httpsMarkEphemeralNodeOp) setupClusterHTTPRequest(hosts []string) erro
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
-		httpRequest.buildHTTPSEndpoint("nodes/" + op.targetNodeName + "/ephemeral")
+		httpRequest.buildHTTPSEndpoint("nodes/{node}/ephemeral", op.targetNodeName)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -66,8 +67,8 @@ func (op *httpsMarkEphemeralNodeOp) prepare(execContext *opEngineExecContext) er
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsMarkEphemeralNodeOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsMarkEphemeralNodeOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,9 +16,10 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strconv"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 )
@@ -26,7 +27,7 @@ import (
 // Timeout set to 30 seconds for each GET /v1/nodes/{node} call.
 // 30 seconds is long enough for normal http request.
 // If this timeout is reached, it might imply that the target IP is unreachable
-const defaultHTTPRequestTimeoutSeconds = 30
+const defaultHTTPRequestTimeout = 30 * time.Second
 const (
 	StartDBCmd CmdType = iota
 	StartNodeCmd
@@ -52,12 +53,16 @@ type httpsPollNodeStateOp struct {
 	opHTTPSBase
 	currentHost string
 	// The timeout for the entire operation
-	timeout int
+	timeout time.Duration
 	// The timeout for each http request. Requests will be repeated if timeout hasn't been exceeded.
-	httpRequestTimeout int
+	httpRequestTimeout time.Duration
 	cmdType            CmdType
 	// poll for nodes down: Set to true if nodes need to be polled to be down
 	checkDown bool
+	// the number of up nodes found by the last polling iteration
+	upNodeCount int
+	// the nodes that have come up, which must not go down
+	stateTracker nodeStateTracker
 }
 
 func makeHTTPSPollNodeStateOpHelper(hosts []string,
@@ -67,7 +72,7 @@ func makeHTTPSPollNodeStateOpHelper(hosts []string,
 	op.description = fmt.Sprintf("Wait for %d node(s) to come up", len(hosts))
 	op.hosts = hosts
 	op.useHTTPPassword = useHTTPPassword
-	op.httpRequestTimeout = defaultHTTPRequestTimeoutSeconds
+	op.httpRequestTimeout = defaultHTTPRequestTimeout
 	op.checkDown = false // setting default to poll nodes UP
 	err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
 	if err != nil {
@@ -81,7 +86,7 @@ func makeHTTPSPollNodeStateOpHelper(hosts []string,
 
 func makeHTTPSPollNodeStateOpWithTimeoutAndCommand(hosts []string,
 	useHTTPPassword bool, userName string, httpsPassword *string,
-	timeout int, cmdType CmdType) (httpsPollNodeStateOp, error) {
+	timeout time.Duration, cmdType CmdType) (httpsPollNodeStateOp, error) {
 	op, err := makeHTTPSPollNodeStateOpHelper(hosts, useHTTPPassword, userName, httpsPassword)
 	if err != nil {
 		return op, err
@@ -97,12 +102,7 @@ func makeHTTPSPollNodeStateDownOp(hosts []string,
 	if err != nil {
 		return op, err
 	}
-	timeoutSecondStr := util.GetEnv("NODE_STATE_POLLING_TIMEOUT", strconv.Itoa(StartupPollingTimeout))
-	timeoutSecond, err := strconv.Atoi(timeoutSecondStr)
-	if err != nil {
-		return httpsPollNodeStateOp{}, err
-	}
-	op.timeout = timeoutSecond
+	op.timeout = StartupPollingTimeout
 	op.checkDown = true
 	op.description = fmt.Sprintf("Wait for %d node(s) to go DOWN", len(hosts))
 	return op, nil
@@ -114,16 +114,11 @@ func makeHTTPSPollNodeStateOp(hosts []string,
 	if err != nil {
 		return op, err
 	}
-	timeoutSecondStr := util.GetEnv("NODE_STATE_POLLING_TIMEOUT", strconv.Itoa(StartupPollingTimeout))
-	timeoutSecond, err := strconv.Atoi(timeoutSecondStr)
-	if err != nil {
-		return httpsPollNodeStateOp{}, err
-	}
-	op.timeout = timeoutSecond
+	op.timeout = StartupPollingTimeout
 	return op, nil
 }
 
-func (op *httpsPollNodeStateOp) getPollingTimeout() int {
+func (op *httpsPollNodeStateOp) getPollingTimeout() time.Duration {
 	return util.Max(op.timeout, 0)
 }
 
@@ -132,7 +127,7 @@ func (op *httpsPollNodeStateOp) setupClusterHTTPRequest(hosts []string) error {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
 		httpRequest.Timeout = op.httpRequestTimeout
-		httpRequest.buildHTTPSEndpoint("nodes/" + host)
+		httpRequest.buildHTTPSEndpoint("nodes/{host}", host)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -150,28 +145,35 @@ func (op *httpsPollNodeStateOp) prepare(execContext *opEngineExecContext) error
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsPollNodeStateOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsPollNodeStateOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
-	return op.processResult(execContext)
+	return op.pollResult(ctx, execContext)
 }
 
 func (op *httpsPollNodeStateOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
 
-func (op *httpsPollNodeStateOp) processResult(execContext *opEngineExecContext) error {
+func (op *httpsPollNodeStateOp) processResult(_ *opEngineExecContext) error {
+	// results are processed in shouldStopPolling() on each polling iteration
+	return nil
+}
+
+// pollResult keeps polling until the expected state is reached, the polling
+// timeout is hit, or ctx is canceled
+func (op *httpsPollNodeStateOp) pollResult(ctx context.Context, execContext *opEngineExecContext) error {
 	op.logger.PrintInfo("[%s] expecting %d %s host(s)", op.name, len(op.hosts), checkStatusToString(op.checkDown))
 
-	err := pollState(op, execContext)
+	err := pollState(ctx, op, execContext)
 	if err != nil {
 		// show the host that is not UP
-		msg := fmt.Sprintf("Cannot get the correct response from the host %s after %d seconds, details: %s",
-			op.currentHost, op.timeout, err)
-		op.logger.PrintError(msg)
-		return errors.New(msg)
+		err = fmt.Errorf("Cannot get the correct response from the host %s after %s, details: %w",
+			op.currentHost, execContext.dispatcher.timeouts.getPollingTimeout(op.getPollingTimeout()), err)
+		op.logger.PrintError("%v", err)
+		return err
 	}
 	return nil
 }
@@ -223,6 +225,9 @@ func (op *httpsPollNodeStateOp) shouldStopPolling() (bool, error) {
 				if nodeInfo.State == util.NodeUpState {
 					upNodeCount++
 				}
+				if err := op.stateTracker.checkNodeState(op.name, host, nodeInfo.State); err != nil {
+					return true, err
+				}
 			} else {
 				// if NMA endpoint cannot function well on any of the hosts, we do not want to retry polling
 				return true, fmt.Errorf("[%s] expect one node's information, but got %d nodes' information"+
@@ -232,6 +237,7 @@ func (op *httpsPollNodeStateOp) shouldStopPolling() (bool, error) {
 		}
 	}
 
+	op.upNodeCount = upNodeCount
 	if upNodeCount < len(op.hosts) {
 		op.logger.PrintInfo("[%s] %d host(s) up", op.name, upNodeCount)
 		op.updateSpinnerMessage("%d host(s) up, expecting %d up host(s)", upNodeCount, len(op.hosts))
@@ -281,6 +287,7 @@ func (op *httpsPollNodeStateOp) shouldStopPollingForDown() (bool, error) {
 		upNodeCount++
 	}
 
+	op.upNodeCount = upNodeCount
 	if upNodeCount != 0 {
 		op.logger.PrintInfo("[%s] %d host(s) up", op.name, upNodeCount)
 		op.updateSpinnerMessage("%d host(s) up, expecting %d host(s) to be down", upNodeCount, len(op.hosts))
@@ -291,3 +298,7 @@ func (op *httpsPollNodeStateOp) shouldStopPollingForDown() (bool, error) {
 
 	return true, nil
 }
+
+func (op *httpsPollNodeStateOp) getNodeStateCounts() (upNodes, downNodes int) {
+	return op.upNodeCount, len(op.hosts) - op.upNodeCount
+}
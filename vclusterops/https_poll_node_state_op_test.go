@@ -16,9 +16,14 @@
 package vclusterops
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
@@ -30,7 +35,7 @@ func TestTimeoutErrorCase(t *testing.T) {
 	username := "testUser"
 	password := "testPwd"
 	// Intentionally pick a low http request timeout to speed up the test.
-	const httpRequestTimeoutForTest = 3
+	const httpRequestTimeoutForTest = 3 * time.Second
 	httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOp(hosts, true, username, &password)
 	assert.Nil(t, err)
 	httpsPollNodeStateOp.httpRequestTimeout = httpRequestTimeoutForTest
@@ -39,19 +44,94 @@ func TestTimeoutErrorCase(t *testing.T) {
 	// default timeout value for the op
 	certs := httpsCerts{}
 	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vlog.Printer{})
+	err = clusterOpEngine.run(context.Background(), vlog.Printer{})
 	// expect timeout error in http response
 	assert.ErrorContains(t, err, "[HTTPSPollNodeStateOp] cannot connect to host 192.0.2.1, please check if the host is still alive")
 
 	// negative timeout value for the op (treated as 0, means no polling)
 	instructions = make([]clusterOp, 0)
 	httpsPollNodeStateOp, err = makeHTTPSPollNodeStateOpWithTimeoutAndCommand(hosts, true, username, &password,
-		-100, CreateDBCmd)
+		-100*time.Second, CreateDBCmd)
 	assert.Nil(t, err)
 	httpsPollNodeStateOp.httpRequestTimeout = httpRequestTimeoutForTest
 	instructions = append(instructions, &httpsPollNodeStateOp)
 	clusterOpEngine = makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vlog.Printer{})
+	err = clusterOpEngine.run(context.Background(), vlog.Printer{})
 	// no polling is done, directly error out
-	assert.ErrorContains(t, err, "reached polling timeout of 0 seconds")
+	assert.ErrorContains(t, err, "reached polling timeout of 0s")
+}
+
+// makeUpFakeCluster returns a fake cluster running a database whose nodes are
+// all up
+func makeUpFakeCluster() *fakecluster.Cluster {
+	cluster := fakecluster.New()
+	var nodes []fakecluster.Node
+	for i, host := range fakeClusterHosts {
+		nodes = append(nodes, fakecluster.Node{
+			Name:       fmt.Sprintf("v_test_db_node%04d", i+1),
+			Address:    host,
+			State:      util.NodeUpState,
+			Subcluster: "default_subcluster",
+			IsPrimary:  true,
+		})
+	}
+	cluster.CreateDatabase("test_db", false, nodes...)
+	return cluster
+}
+
+// runPollNodeStateOps polls the node state opCount times, in one engine run
+func runPollNodeStateOps(cluster *fakecluster.Cluster, opCount int) error {
+	password := ""
+	var instructions []clusterOp
+	for i := 0; i < opCount; i++ {
+		op, err := makeHTTPSPollNodeStateOp(fakeClusterHosts, true, "dbadmin", &password)
+		if err != nil {
+			return err
+		}
+		instructions = append(instructions, &op)
+	}
+	clusterOpEngine := makeClusterOpEngine(instructions, &httpsCerts{})
+	clusterOpEngine.transport = cluster
+	return clusterOpEngine.run(context.Background(), vlog.Printer{})
+}
+
+func TestPollNodeStateConnectionReuse(t *testing.T) {
+	cluster := makeUpFakeCluster()
+	defer cluster.Close()
+
+	// the ops of an engine run share one connection per host
+	err := runPollNodeStateOps(cluster, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, len(fakeClusterHosts), cluster.Connections())
+
+	// the connections do not outlive the engine run
+	err = runPollNodeStateOps(cluster, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*len(fakeClusterHosts), cluster.Connections())
+}
+
+// BenchmarkPollNodeStateHandshakes compares the TLS handshakes of polling the
+// node state when the connections are kept alive across the ops of an engine
+// run, and when each op runs in its own engine
+func BenchmarkPollNodeStateHandshakes(b *testing.B) {
+	b.Run("per_engine_run", func(b *testing.B) {
+		cluster := makeUpFakeCluster()
+		defer cluster.Close()
+		b.ResetTimer()
+		if err := runPollNodeStateOps(cluster, b.N); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(float64(cluster.Connections())/float64(b.N), "handshakes/op")
+	})
+	b.Run("per_op", func(b *testing.B) {
+		cluster := makeUpFakeCluster()
+		defer cluster.Close()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := runPollNodeStateOps(cluster, 1); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(cluster.Connections())/float64(b.N), "handshakes/op")
+	})
 }
@@ -16,9 +16,10 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strconv"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 )
@@ -27,14 +28,18 @@ type httpsPollSubclusterNodeStateOp struct {
 	opBase
 	opHTTPSBase
 	currentHost string
-	timeout     int
+	timeout     time.Duration
 	scName      string
 	checkDown   bool
+	// the number of up nodes found by the last polling iteration
+	upNodeCount int
+	// the nodes that have come up, which must not go down
+	stateTracker nodeStateTracker
 }
 
 // This op is used to poll for nodes that are a part of the subcluster `scName` to be UP.
 // A default timeout value defined by StartupPollingTimeout is applied. The user can suggest
-// an alternate timeout through the env var NODE_STATE_POLLING_TIMEOUT
+// an alternate timeout through the StatePolling timeout of the TimeoutPolicy
 func makeHTTPSPollSubclusterNodeStateOp(scName string,
 	useHTTPPassword bool, userName string,
 	httpsPassword *string) (httpsPollSubclusterNodeStateOp, error) {
@@ -50,12 +55,7 @@ func makeHTTPSPollSubclusterNodeStateOp(scName string,
 	}
 	op.userName = userName
 	op.httpsPassword = httpsPassword
-	timeoutSecondStr := util.GetEnv("NODE_STATE_POLLING_TIMEOUT", strconv.Itoa(StartupPollingTimeout))
-	timeoutSecond, err := strconv.Atoi(timeoutSecondStr)
-	if err != nil {
-		return httpsPollSubclusterNodeStateOp{}, err
-	}
-	op.timeout = timeoutSecond
+	op.timeout = StartupPollingTimeout
 	return op, nil
 }
 
@@ -77,7 +77,7 @@ func makeHTTPSPollSubclusterNodeStateDownOp(scName string,
 	return op, err
 }
 
-func (op *httpsPollSubclusterNodeStateOp) getPollingTimeout() int {
+func (op *httpsPollSubclusterNodeStateOp) getPollingTimeout() time.Duration {
 	// a negative value indicates no timeout and should never be used for this op
 	return util.Max(op.timeout, 0)
 }
@@ -86,8 +86,8 @@ func (op *httpsPollSubclusterNodeStateOp) setupClusterHTTPRequest(hosts []string
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
-		httpRequest.Timeout = defaultHTTPRequestTimeoutSeconds
-		httpRequest.buildHTTPSEndpoint("nodes/" + host)
+		httpRequest.Timeout = defaultHTTPRequestTimeout
+		httpRequest.buildHTTPSEndpoint("nodes/{host}", host)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -108,11 +108,11 @@ func (op *httpsPollSubclusterNodeStateOp) prepare(execContext *opEngineExecConte
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsPollSubclusterNodeStateOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsPollSubclusterNodeStateOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
-	return op.processResult(execContext)
+	return op.pollResult(ctx, execContext)
 }
 
 func (op *httpsPollSubclusterNodeStateOp) finalize(_ *opEngineExecContext) error {
@@ -142,15 +142,22 @@ sample https nodes/host endpoint response:
 	  ]
 	}
 */
-func (op *httpsPollSubclusterNodeStateOp) processResult(execContext *opEngineExecContext) error {
+func (op *httpsPollSubclusterNodeStateOp) processResult(_ *opEngineExecContext) error {
+	// results are processed in shouldStopPolling() on each polling iteration
+	return nil
+}
+
+// pollResult keeps polling until the expected state is reached, the polling
+// timeout is hit, or ctx is canceled
+func (op *httpsPollSubclusterNodeStateOp) pollResult(ctx context.Context, execContext *opEngineExecContext) error {
 	op.logger.PrintInfo("[%s] expecting %d %s host(s)", op.name, len(op.hosts), checkStatusToString(op.checkDown))
 	op.logger.Info("Processing Poll subcluster node state")
-	err := pollState(op, execContext)
+	err := pollState(ctx, op, execContext)
 	if err != nil {
 		// show the host that is not UP
-		msg := fmt.Sprintf("Cannot get the correct response from the host %s after %d seconds, details: %s",
-			op.currentHost, op.timeout, err)
-		return errors.New(msg)
+		err = fmt.Errorf("Cannot get the correct response from the host %s after %s, details: %w",
+			op.currentHost, execContext.dispatcher.timeouts.getPollingTimeout(op.getPollingTimeout()), err)
+		return err
 	}
 	return nil
 }
@@ -201,6 +208,9 @@ func (op *httpsPollSubclusterNodeStateOp) shouldStopPolling() (bool, error) {
 				if nodeInfo.State == util.NodeUpState {
 					upNodeCount++
 				}
+				if err := op.stateTracker.checkNodeState(op.name, host, nodeInfo.State); err != nil {
+					return true, err
+				}
 			} else {
 				// if NMA endpoint cannot function well on any of the hosts, we do not want to retry polling
 				return true, fmt.Errorf("[%s] expect one node's information, but got %d nodes' information"+
@@ -210,6 +220,7 @@ func (op *httpsPollSubclusterNodeStateOp) shouldStopPolling() (bool, error) {
 		}
 	}
 
+	op.upNodeCount = upNodeCount
 	if upNodeCount < len(op.hosts) {
 		op.logger.PrintInfo("[%s] %d host(s) up", op.name, upNodeCount)
 		return false, nil
@@ -252,6 +263,7 @@ func (op *httpsPollSubclusterNodeStateOp) shouldStopPollingForDown() (bool, erro
 		upNodeCount++
 	}
 
+	op.upNodeCount = upNodeCount
 	if upNodeCount != 0 {
 		op.logger.PrintInfo("[%s] %d host(s) up", op.name, upNodeCount)
 		return false, nil
@@ -261,3 +273,7 @@ func (op *httpsPollSubclusterNodeStateOp) shouldStopPollingForDown() (bool, erro
 
 	return true, nil
 }
+
+func (op *httpsPollSubclusterNodeStateOp) getNodeStateCounts() (upNodes, downNodes int) {
+	return op.upNodeCount, len(op.hosts) - op.upNodeCount
+}
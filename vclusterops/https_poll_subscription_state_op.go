@@ -16,7 +16,9 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 )
@@ -24,7 +26,7 @@ import (
 type httpsPollSubscriptionStateOp struct {
 	opBase
 	opHTTPSBase
-	timeout     int
+	timeout     time.Duration
 	nodesToPoll *[]string
 }
 
@@ -52,7 +54,7 @@ func makeHTTPSPollSubscriptionStateOp(hosts []string,
 	return op, nil
 }
 
-func (op *httpsPollSubscriptionStateOp) getPollingTimeout() int {
+func (op *httpsPollSubscriptionStateOp) getPollingTimeout() time.Duration {
 	// a negative value indicates no timeout and should never be used for this op
 	return util.Max(op.timeout, 0)
 }
@@ -61,7 +63,7 @@ func (op *httpsPollSubscriptionStateOp) setupClusterHTTPRequest(hosts []string)
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
-		httpRequest.Timeout = defaultHTTPRequestTimeoutSeconds
+		httpRequest.Timeout = defaultHTTPRequestTimeout
 		httpRequest.buildHTTPSEndpoint("subscriptions")
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
@@ -80,12 +82,12 @@ func (op *httpsPollSubscriptionStateOp) prepare(execContext *opEngineExecContext
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsPollSubscriptionStateOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsPollSubscriptionStateOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
-	return op.processResult(execContext)
+	return op.pollResult(ctx, execContext)
 }
 
 func (op *httpsPollSubscriptionStateOp) finalize(_ *opEngineExecContext) error {
@@ -120,8 +122,15 @@ type subscriptionInfo struct {
 	IsPrimary         bool   `json:"is_primary"`
 }
 
-func (op *httpsPollSubscriptionStateOp) processResult(execContext *opEngineExecContext) error {
-	err := pollState(op, execContext)
+func (op *httpsPollSubscriptionStateOp) processResult(_ *opEngineExecContext) error {
+	// results are processed in shouldStopPolling() on each polling iteration
+	return nil
+}
+
+// pollResult keeps polling until the expected state is reached, the polling
+// timeout is hit, or ctx is canceled
+func (op *httpsPollSubscriptionStateOp) pollResult(ctx context.Context, execContext *opEngineExecContext) error {
+	err := pollState(ctx, op, execContext)
 	if err != nil {
 		return fmt.Errorf("not all subscriptions are ACTIVE, %w", err)
 	}
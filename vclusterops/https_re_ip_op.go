@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -81,7 +82,7 @@ func (op *httpsReIPOp) setupClusterHTTPRequest(hostsToReIP []string) error {
 		if !ok {
 			return fmt.Errorf("[%s] cannot find node information for address %s", op.name, host)
 		}
-		httpRequest.buildHTTPSEndpoint("nodes/" + nodesInfo.NodeName + "/ip")
+		httpRequest.buildHTTPSEndpoint("nodes/{node}/ip", nodesInfo.NodeName)
 		httpRequest.QueryParams = make(map[string]string)
 		httpRequest.QueryParams["host"] = nodesInfo.TargetAddress
 		httpRequest.QueryParams["control-host"] = nodesInfo.TargetControlAddress
@@ -122,8 +123,8 @@ func (op *httpsReIPOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(op.nodeNamesToReIP)
 }
 
-func (op *httpsReIPOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsReIPOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -67,8 +68,8 @@ func (op *httpsRebalanceClusterOp) prepare(execContext *opEngineExecContext) err
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsRebalanceClusterOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsRebalanceClusterOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -55,7 +56,7 @@ func (op *httpsRebalanceSubclusterShardsOp) setupClusterHTTPRequest(hosts []stri
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
-		httpRequest.buildHTTPSEndpoint("subclusters/" + op.scName + "/rebalance")
+		httpRequest.buildHTTPSEndpoint("subclusters/{subcluster}/rebalance", op.scName)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -80,8 +81,8 @@ func (op *httpsRebalanceSubclusterShardsOp) prepare(execContext *opEngineExecCon
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsRebalanceSubclusterShardsOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsRebalanceSubclusterShardsOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
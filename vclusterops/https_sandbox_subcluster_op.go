@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -29,6 +30,8 @@ type httpsSandboxingOp struct {
 	hostRequestBodyMap map[string]string
 	scName             string
 	sandboxName        string
+	// the ops that unsandbox the subcluster if a later op fails
+	unsandboxInstructions []clusterOp
 }
 
 // This op is used to sandbox the given subcluster `scName` as `sandboxName`
@@ -59,7 +62,7 @@ func (op *httpsSandboxingOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
-		httpRequest.buildHTTPSEndpoint("subclusters/" + op.scName + "/sandbox")
+		httpRequest.buildHTTPSEndpoint("subclusters/{subcluster}/sandbox", op.scName)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -102,8 +105,8 @@ func (op *httpsSandboxingOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(hosts)
 }
 
-func (op *httpsSandboxingOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsSandboxingOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -144,6 +147,9 @@ func (op *httpsSandboxingOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
-func (op *httpsSandboxingOp) finalize(_ *opEngineExecContext) error {
+func (op *httpsSandboxingOp) finalize(execContext *opEngineExecContext) error {
+	if len(op.unsandboxInstructions) > 0 {
+		execContext.registerUndo("unsandbox subcluster "+op.scName, op.unsandboxInstructions...)
+	}
 	return nil
 }
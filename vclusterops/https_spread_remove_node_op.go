@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -71,8 +72,8 @@ func (op *httpsSpreadRemoveNodeOp) prepare(execContext *opEngineExecContext) err
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsSpreadRemoveNodeOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsSpreadRemoveNodeOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -207,7 +208,7 @@ func (op *httpsStageSystemTablesOp) prepare(execContext *opEngineExecContext) er
 	return nil
 }
 
-func (op *httpsStageSystemTablesOp) execute(execContext *opEngineExecContext) error {
+func (op *httpsStageSystemTablesOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
 	findCertsInOptions := op.certs != nil
 	for _, systemTableInfo := range execContext.systemTableList.SystemTableList {
 		if slices.Contains(op.excludedTables, systemTableInfo.TableName) {
@@ -220,7 +221,7 @@ func (op *httpsStageSystemTablesOp) execute(execContext *opEngineExecContext) er
 			return err
 		}
 		op.logger.Info("Staging System Table:", "Schema", systemTableInfo.Schema, "Table", systemTableInfo.TableName)
-		if err := op.runExecute(execContext); err != nil {
+		if err := op.runExecute(ctx, execContext); err != nil {
 			return err
 		}
 		if err := op.processResult(execContext); err != nil {
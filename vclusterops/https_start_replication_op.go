@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -147,8 +148,8 @@ func (op *httpsStartReplicationOp) prepare(execContext *opEngineExecContext) err
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *httpsStartReplicationOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsStartReplicationOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
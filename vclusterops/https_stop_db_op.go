@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -111,8 +112,8 @@ func (op *httpsStopDBOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(hosts)
 }
 
-func (op *httpsStopDBOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsStopDBOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
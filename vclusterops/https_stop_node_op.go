@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -69,7 +70,7 @@ func (op *httpsStopNodeOp) setupClusterHTTPRequest(hosts, nodenames []string) er
 	for i, nodename := range nodenames {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
-		httpRequest.buildHTTPSEndpoint("nodes/" + nodename + "/shutdown")
+		httpRequest.buildHTTPSEndpoint("nodes/{node}/shutdown", nodename)
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -102,8 +103,8 @@ func (op *httpsStopNodeOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(hosts, nodenames)
 }
 
-func (op *httpsStopNodeOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *httpsStopNodeOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -57,7 +58,15 @@ func (options *VInstallPackagesOptions) validateAnalyzeOptions(log vlog.Printer)
 	return options.analyzeOptions()
 }
 
-func (vcc VClusterCommands) VInstallPackages(options *VInstallPackagesOptions) (*InstallPackageStatus, error) {
+func (vcc VClusterCommands) VInstallPackages(ctx context.Context,
+	options *VInstallPackagesOptions) (*InstallPackageStatus, *ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandInstallPackages, &options.DatabaseOptions)
+	status, err := vcc.installPackages(ctx, options)
+	report := call.finish(err)
+	return status, report, err
+}
+
+func (vcc VClusterCommands) installPackages(ctx context.Context, options *VInstallPackagesOptions) (*InstallPackageStatus, error) {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -67,7 +76,7 @@ func (vcc VClusterCommands) VInstallPackages(options *VInstallPackagesOptions) (
 	// validate and analyze all options
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return nil, err
+		return nil, makeValidationProblem(err)
 	}
 
 	// Generate the instructions and a pointer to the status object that will
@@ -79,10 +88,11 @@ func (vcc VClusterCommands) VInstallPackages(options *VInstallPackagesOptions) (
 
 	// Create a VClusterOpEngine. No need for certs since this operation doesn't
 	// talk to the NMA.
-	clusterOpEngine := makeClusterOpEngine(instructions, &httpsCerts{})
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	clusterOpEngine.certs = &httpsCerts{}
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return nil, fmt.Errorf("fail to install packages: %w", runError)
 	}
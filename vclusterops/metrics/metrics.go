@@ -0,0 +1,257 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package metrics collects counters and histograms in a Registry, and writes
+// them in the Prometheus text exposition format. A Registry is an
+// http.Handler, so it can be scraped directly:
+//
+//	registry := metrics.NewRegistry()
+//	vcc := vclusterops.VClusterCommands{Metrics: registry}
+//	http.Handle("/metrics", registry)
+//
+// Only what the vcluster ops need is implemented, so that the library does
+// not depend on a metrics client.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the buckets of
+// the duration histograms. The ops of vcluster take from milliseconds to
+// many minutes.
+var DefaultDurationBuckets = []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1800}
+
+const (
+	counterType   = "counter"
+	histogramType = "histogram"
+)
+
+// Registry holds the metric families. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// family is a metric and its series, one for each set of label values
+type family struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	// the value of a counter, or the sum of a histogram
+	value float64
+	// the count of each bucket of a histogram, not cumulative
+	bucketCounts []uint64
+	count        uint64
+}
+
+// Counter is a metric that only goes up
+type Counter struct {
+	registry *Registry
+	family   *family
+}
+
+// Histogram counts the observed values in buckets
+type Histogram struct {
+	registry *Registry
+	family   *family
+}
+
+// Counter returns the counter with the given name, and registers it if it
+// is new. The label values are given, in the same order, when it is
+// incremented.
+func (r *Registry) Counter(name, help string, labelNames ...string) *Counter {
+	return &Counter{registry: r, family: r.getFamily(name, help, counterType, nil, labelNames)}
+}
+
+// Histogram returns the histogram with the given name and bucket upper
+// bounds, and registers it if it is new
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	return &Histogram{registry: r, family: r.getFamily(name, help, histogramType, buckets, labelNames)}
+}
+
+func (r *Registry) getFamily(name, help, metricType string, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		return f
+	}
+	f := &family{name: name, help: help, metricType: metricType, labelNames: labelNames,
+		buckets: buckets, series: make(map[string]*series)}
+	r.families[name] = f
+	return f
+}
+
+// getSeries returns the series with the given label values. The lock of
+// the registry must be held.
+func (f *family) getSeries(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %s has labels %v, but got the values %v", f.name, f.labelNames, labelValues))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.metricType == histogramType {
+			s.bucketCounts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Inc adds one to the counter with the given label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds a non-negative value to the counter with the given label values
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.family.name))
+	}
+	c.registry.mu.Lock()
+	defer c.registry.mu.Unlock()
+	c.family.getSeries(labelValues).value += value
+}
+
+// Observe adds a value to the histogram with the given label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.registry.mu.Lock()
+	defer h.registry.mu.Unlock()
+	s := h.family.getSeries(labelValues)
+	s.value += value
+	s.count++
+	for i, upperBound := range h.family.buckets {
+		if value <= upperBound {
+			s.bucketCounts[i]++
+			break
+		}
+	}
+}
+
+// WriteText writes the metrics in the text exposition format. The families
+// and their series are sorted, so that the output is stable.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		r.families[name].writeText(bw)
+	}
+	return bw.Flush()
+}
+
+func (f *family) writeText(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.metricType)
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := f.series[key]
+		labels := f.formatLabels(s.labelValues)
+		if f.metricType == counterType {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, joinLabels(labels, ""), formatValue(s.value))
+			continue
+		}
+		var cumulativeCount uint64
+		for i, upperBound := range f.buckets {
+			cumulativeCount += s.bucketCounts[i]
+			le := fmt.Sprintf(`le="%s"`, formatValue(upperBound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, joinLabels(labels, le), cumulativeCount)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, joinLabels(labels, `le="+Inf"`), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, joinLabels(labels, ""), formatValue(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, joinLabels(labels, ""), s.count)
+	}
+}
+
+func (f *family) formatLabels(labelValues []string) []string {
+	labels := make([]string, len(labelValues))
+	for i, value := range labelValues {
+		labels[i] = fmt.Sprintf(`%s="%s"`, f.labelNames[i], escapeLabelValue(value))
+	}
+	return labels
+}
+
+// joinLabels formats the labels of a sample, with an extra label if it is
+// not empty
+func joinLabels(labels []string, extraLabel string) string {
+	if extraLabel != "" {
+		labels = append(labels[:len(labels):len(labels)], extraLabel)
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// ServeHTTP writes the metrics in the text exposition format, so that the
+// registry can be scraped
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if err := r.WriteText(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
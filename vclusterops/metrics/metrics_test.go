@@ -0,0 +1,75 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.Counter("requests_total", "Requests sent.", "endpoint", "code")
+	requests.Inc("nodes", "200")
+	requests.Add(2, "nodes", "200")
+	requests.Inc("say \"hi\"\n", "none")
+	duration := registry.Histogram("duration_seconds", "Duration of\nthe ops.", []float64{0.1, 1}, "op")
+	duration.Observe(0.05, "a")
+	duration.Observe(0.5, "a")
+	duration.Observe(2, "a")
+
+	var text strings.Builder
+	assert.NoError(t, registry.WriteText(&text))
+	expected := `# HELP duration_seconds Duration of\nthe ops.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{op="a",le="0.1"} 1
+duration_seconds_bucket{op="a",le="1"} 2
+duration_seconds_bucket{op="a",le="+Inf"} 3
+duration_seconds_sum{op="a"} 2.55
+duration_seconds_count{op="a"} 3
+# HELP requests_total Requests sent.
+# TYPE requests_total counter
+requests_total{endpoint="nodes",code="200"} 3
+requests_total{endpoint="say \"hi\"\n",code="none"} 1
+`
+	assert.Equal(t, expected, text.String())
+}
+
+func TestRegistryReturnsSameFamily(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("ops_total", "Ops run.").Inc()
+	registry.Counter("ops_total", "Ops run.").Inc()
+
+	var text strings.Builder
+	assert.NoError(t, registry.WriteText(&text))
+	assert.Contains(t, text.String(), "ops_total 2\n")
+	assert.Panics(t, func() { registry.Counter("ops_total", "Ops run.").Inc("extra") })
+}
+
+func TestServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("ops_total", "Ops run.").Inc()
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "# TYPE ops_total counter\nops_total 1\n")
+}
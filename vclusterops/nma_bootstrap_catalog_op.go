@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -172,8 +173,8 @@ func (op *nmaBootstrapCatalogOp) prepare(execContext *opEngineExecContext) error
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaBootstrapCatalogOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaBootstrapCatalogOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -95,6 +96,8 @@ func makeNMADownloadFileOp(newNodes []string, sourceFilePath, destinationFilePat
 	op := nmaDownloadFileOp{}
 	op.name = "NMADownloadFileOp"
 	op.description = fmt.Sprintf("Download %s", filepath.Base(sourceFilePath))
+	// the file is read from the communal storage, nothing is changed
+	op.readsOnly = true
 	initiator := getInitiator(newNodes)
 	op.hosts = []string{initiator}
 	op.vdb = vdb
@@ -178,8 +181,8 @@ func (op *nmaDownloadFileOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaDownloadFileOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaDownloadFileOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
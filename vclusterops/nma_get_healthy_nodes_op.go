@@ -16,12 +16,14 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
+	"time"
 )
 
 // nodes being down is not unusual for the purpose of this op.
 // don't block 6 minutes because of one down node.
-const healthRequestTimeoutSeconds = 20
+const healthRequestTimeout = 20 * time.Second
 
 type nmaGetHealthyNodesOp struct {
 	opBase
@@ -43,7 +45,7 @@ func (op *nmaGetHealthyNodesOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
-		httpRequest.Timeout = healthRequestTimeoutSeconds
+		httpRequest.Timeout = healthRequestTimeout
 		httpRequest.buildNMAEndpoint("health")
 		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
 	}
@@ -57,8 +59,8 @@ func (op *nmaGetHealthyNodesOp) prepare(execContext *opEngineExecContext) error
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaGetHealthyNodesOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaGetHealthyNodesOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
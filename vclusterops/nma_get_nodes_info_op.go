@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 )
 
@@ -24,13 +25,13 @@ import (
 type nmaGetNodesInfoOp struct {
 	opBase
 	dbName               string
-	catalogPrefix        string
-	ignoreInternalErrors bool // e.g. in scrutinize, continue even if host has issues
+	catalogPrefixes      map[string]string // the catalog prefix of each host
+	ignoreInternalErrors bool              // e.g. in scrutinize, continue even if host has issues
 	vdb                  *VCoordinationDatabase
 }
 
 func makeNMAGetNodesInfoOp(hosts []string,
-	dbName, catalogPrefix string,
+	dbName string, catalogPrefixes map[string]string,
 	ignoreInternalErrors bool,
 	vdb *VCoordinationDatabase) nmaGetNodesInfoOp {
 	op := nmaGetNodesInfoOp{}
@@ -38,7 +39,7 @@ func makeNMAGetNodesInfoOp(hosts []string,
 	op.description = "Collect nodes information"
 	op.hosts = hosts
 	op.dbName = dbName
-	op.catalogPrefix = catalogPrefix
+	op.catalogPrefixes = catalogPrefixes
 	op.ignoreInternalErrors = ignoreInternalErrors
 	op.vdb = vdb
 	op.vdb.HostNodeMap = makeVHostNodeMap()
@@ -50,7 +51,8 @@ func (op *nmaGetNodesInfoOp) setupClusterHTTPRequest(hosts []string) error {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
 		httpRequest.buildNMAEndpoint("nodes")
-		httpRequest.QueryParams = map[string]string{"db_name": op.dbName, "catalog_prefix": op.catalogPrefix}
+		httpRequest.Idempotent = true
+		httpRequest.QueryParams = map[string]string{"db_name": op.dbName, "catalog_prefix": op.catalogPrefixes[host]}
 		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
 	}
 
@@ -63,8 +65,8 @@ func (op *nmaGetNodesInfoOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaGetNodesInfoOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaGetNodesInfoOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -25,13 +26,15 @@ import (
 
 type nmaGetScrutinizeTarOp struct {
 	scrutinizeOpBase
-	useInitiator bool
+	useInitiator           bool
+	maxConcurrentDownloads int
 }
 
 func makeNMAGetScrutinizeTarOp(
 	id, batch string,
 	hosts []string,
-	hostNodeNameMap map[string]string) (nmaGetScrutinizeTarOp, error) {
+	hostNodeNameMap map[string]string,
+	maxConcurrentDownloads int) (nmaGetScrutinizeTarOp, error) {
 	// base members
 	op := nmaGetScrutinizeTarOp{}
 	op.name = "NMAGetScrutinizeTarOp"
@@ -43,6 +46,7 @@ func makeNMAGetScrutinizeTarOp(
 	op.batch = batch
 	op.hostNodeNameMap = hostNodeNameMap
 	op.httpMethod = GetMethod
+	op.maxConcurrentDownloads = maxConcurrentDownloads
 
 	// the caller is responsible for making sure hosts and maps match up exactly
 	err := validateHostMaps(hosts, hostNodeNameMap)
@@ -108,12 +112,13 @@ func (op *nmaGetScrutinizeTarOp) prepare(execContext *opEngineExecContext) error
 			op.batch)
 	}
 	execContext.dispatcher.setupForDownload(op.hosts, hostToFilePathsMap)
+	op.clusterHTTPRequest.MaxConcurrency = op.maxConcurrentDownloads
 
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaGetScrutinizeTarOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaGetScrutinizeTarOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
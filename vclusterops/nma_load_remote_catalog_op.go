@@ -16,9 +16,11 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 )
 
 type nmaLoadRemoteCatalogOp struct {
@@ -27,7 +29,7 @@ type nmaLoadRemoteCatalogOp struct {
 	configurationParameters map[string]string
 	oldHosts                []string
 	vdb                     *VCoordinationDatabase
-	timeout                 uint
+	timeout                 time.Duration
 	primaryNodeCount        uint
 	restorePoint            *RestorePointPolicy
 }
@@ -49,7 +51,7 @@ type loadRemoteCatalogRequestData struct {
 }
 
 func makeNMALoadRemoteCatalogOp(oldHosts []string, configurationParameters map[string]string,
-	vdb *VCoordinationDatabase, timeout uint, restorePoint *RestorePointPolicy) nmaLoadRemoteCatalogOp {
+	vdb *VCoordinationDatabase, timeout time.Duration, restorePoint *RestorePointPolicy) nmaLoadRemoteCatalogOp {
 	op := nmaLoadRemoteCatalogOp{}
 	op.name = "NMALoadRemoteCatalogOp"
 	op.description = "Load remote catalog"
@@ -124,7 +126,7 @@ func (op *nmaLoadRemoteCatalogOp) setupClusterHTTPRequest(hosts []string) error
 		httpRequest.Method = PostMethod
 		httpRequest.buildNMAEndpoint("catalog/revive")
 		httpRequest.RequestData = op.hostRequestBodyMap[host]
-		httpRequest.Timeout = int(op.timeout)
+		httpRequest.Timeout = op.timeout
 
 		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
 	}
@@ -142,8 +144,8 @@ func (op *nmaLoadRemoteCatalogOp) prepare(execContext *opEngineExecContext) erro
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaLoadRemoteCatalogOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaLoadRemoteCatalogOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
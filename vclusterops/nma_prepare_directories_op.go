@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -100,8 +101,8 @@ func (op *nmaPrepareDirectoriesOp) prepare(execContext *opEngineExecContext) err
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaPrepareDirectoriesOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaPrepareDirectoriesOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
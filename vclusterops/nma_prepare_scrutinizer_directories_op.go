@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -95,8 +96,8 @@ func (op *nmaPrepareScrutinizeDirectoriesOp) prepare(execContext *opEngineExecCo
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaPrepareScrutinizeDirectoriesOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaPrepareScrutinizeDirectoriesOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -16,11 +16,13 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type nmaReadCatalogEditorOp struct {
@@ -95,8 +97,8 @@ func (op *nmaReadCatalogEditorOp) prepare(execContext *opEngineExecContext) erro
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaReadCatalogEditorOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaReadCatalogEditorOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -164,6 +166,21 @@ type nmaVDatabase struct {
 	PrimaryNodeCount uint `json:",omitempty"`
 }
 
+// copy returns a deep copy of the database, whose nodes and host to node
+// map can be changed without changing the ones of the receiver
+func (vdb *nmaVDatabase) copy() nmaVDatabase {
+	vdbCopy := *vdb
+	vdbCopy.Nodes = slices.Clone(vdb.Nodes)
+	if vdb.HostNodeMap != nil {
+		vdbCopy.HostNodeMap = make(map[string]*nmaVNode, len(vdb.HostNodeMap))
+		for host, vnode := range vdb.HostNodeMap {
+			vnodeCopy := *vnode
+			vdbCopy.HostNodeMap[host] = &vnodeCopy
+		}
+	}
+	return vdbCopy
+}
+
 func (op *nmaReadCatalogEditorOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 	var hostsWithLatestCatalog []string
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -127,8 +128,8 @@ func (op *nmaShowRestorePointsOp) prepare(execContext *opEngineExecContext) erro
 	return op.setupClusterHTTPRequest(hostRequestBodyMap)
 }
 
-func (op *nmaShowRestorePointsOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaShowRestorePointsOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
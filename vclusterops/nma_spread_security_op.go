@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	crand "crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -114,8 +115,8 @@ func (op *nmaSpreadSecurityOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(hostRequestBodyMap)
 }
 
-func (op *nmaSpreadSecurityOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaSpreadSecurityOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -1,6 +1,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -35,7 +36,7 @@ func TestStartNodeOp(t *testing.T) {
 	// for testing
 	execContext.nmaVDatabase.HostNodeMap[hosts[0]] = &nmaVNode{StartCommand: startCmd}
 
-	err := clusterOpEngine.runWithExecContext(vl, &execContext)
+	err := clusterOpEngine.runWithExecContext(context.Background(), vl, &execContext)
 	assert.NoError(t, err)
 	httpRequest := op.clusterHTTPRequest.RequestCollection[hosts[0]]
 	startNodeData := startNodeRequestData{}
@@ -16,10 +16,12 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/vertica/vcluster/rfc7807"
 	"github.com/vertica/vcluster/vclusterops/util"
 )
 
@@ -103,6 +105,7 @@ func (op *nmaVerticaVersionOp) setupClusterHTTPRequest(hosts []string) error {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
 		httpRequest.buildNMAEndpoint("vertica/version")
+		httpRequest.Idempotent = true
 		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
 	}
 
@@ -205,8 +208,8 @@ func (op *nmaVerticaVersionOp) prepare(execContext *opEngineExecContext) error {
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
-func (op *nmaVerticaVersionOp) execute(execContext *opEngineExecContext) error {
-	if err := op.runExecute(execContext); err != nil {
+func (op *nmaVerticaVersionOp) execute(ctx context.Context, execContext *opEngineExecContext) error {
+	if err := op.runExecute(ctx, execContext); err != nil {
 		return err
 	}
 
@@ -286,9 +289,11 @@ func (op *nmaVerticaVersionOp) logCheckVersionMatch() error {
 				versionStr = version
 			} else if version != versionStr && op.RequireSameVersion {
 				if op.IsEon && op.HasIncomingSCNames {
-					return fmt.Errorf("[%s] Found mismatched versions: [%s] and [%s] in subcluster [%s]", op.name, versionStr, version, sc)
+					return makeProblem(rfc7807.VersionMismatch, "",
+						fmt.Errorf("[%s] Found mismatched versions: [%s] and [%s] in subcluster [%s]", op.name, versionStr, version, sc))
 				}
-				return fmt.Errorf("[%s] Found mismatched versions: [%s] and [%s]", op.name, versionStr, version)
+				return makeProblem(rfc7807.VersionMismatch, "",
+					fmt.Errorf("[%s] Found mismatched versions: [%s] and [%s]", op.name, versionStr, version))
 			}
 		}
 		// no version collected at all
@@ -0,0 +1,94 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vertica/vcluster/rfc7807"
+)
+
+// makeProblem returns err as an occurrence of one of the problems that
+// vcluster raises itself, on the host if it is not empty. The problem wraps
+// err, so errors.Is and errors.As still find the errors in its chain.
+func makeProblem(id rfc7807.ProblemID, host string, err error) error {
+	if err == nil || errors.Is(err, rfc7807.New(id)) {
+		return err
+	}
+	return rfc7807.New(id).WithHost(host).WithCause(err)
+}
+
+// makeValidationProblem returns the error of options that are not valid
+func makeValidationProblem(err error) error {
+	return makeProblem(rfc7807.ValidationError, "", err)
+}
+
+// makeSendRequestProblem returns the error of a request that could not be
+// sent to the host. The host is unreachable if it could not be resolved or
+// connected to, or if it did not respond in time. The requests that failed
+// because of the TLS config, or that the caller canceled, are not.
+func makeSendRequestProblem(host string, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var netErr net.Error
+	if errors.As(err, &dnsErr) || (errors.As(err, &opErr) && opErr.Op == "dial") ||
+		(errors.As(err, &netErr) && netErr.Timeout()) {
+		return makeProblem(rfc7807.HostUnreachable, host, err)
+	}
+	return err
+}
+
+// makeResponseProblem returns the error of a failed response. A 401
+// response is caused by wrong credentials if it tells so, and not, for
+// instance, because the node has not joined the cluster yet.
+func makeResponseProblem(host string, statusCode int, respBody string, err error) error {
+	if statusCode != UnauthorizedCode {
+		return err
+	}
+	for _, msg := range wrongCredentialErrMsg {
+		if strings.Contains(respBody, msg) {
+			return makeProblem(rfc7807.WrongCredentials, host, err)
+		}
+	}
+	return err
+}
+
+// makePartialFailureProblem returns the error of an op that failed on some
+// of its hosts, but succeeded on the others. The error of an op that failed
+// on all of its hosts, or that did not send any request, is returned as it is.
+func makePartialFailureProblem(op clusterOp, err error) error {
+	var failedHosts, succeededHosts []string
+	for _, hostResult := range op.getHostResults() {
+		if hostResult.Status == resultStatusNames[SUCCESS] {
+			succeededHosts = append(succeededHosts, hostResult.Host)
+		} else {
+			failedHosts = append(failedHosts, hostResult.Host)
+		}
+	}
+	if len(failedHosts) == 0 || len(succeededHosts) == 0 {
+		return err
+	}
+	return rfc7807.New(rfc7807.PartialFailure).
+		WithDetail(fmt.Sprintf("%v, failed on host(s) %v and succeeded on host(s) %v", err, failedHosts, succeededHosts)).
+		WithCause(err)
+}
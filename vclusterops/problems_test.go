@@ -0,0 +1,94 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+)
+
+func TestSendRequestProblem(t *testing.T) {
+	const host = "192.168.1.101"
+	refusedErr := &url.Error{Op: "Get", URL: "https://192.168.1.101:5554/v1/health", Err: &net.OpError{
+		Op:  "dial",
+		Net: "tcp",
+		Err: os.NewSyscallError("connect", syscall.ECONNREFUSED),
+	}}
+	err := makeSendRequestProblem(host, refusedErr)
+	problem := &rfc7807.VProblem{}
+	if assert.ErrorAs(t, err, &problem) {
+		assert.True(t, problem.IsInstanceOf(rfc7807.HostUnreachable))
+		assert.Equal(t, host, problem.Host)
+	}
+	// the cause is still in the chain
+	assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+
+	// the requests that the caller canceled, or that failed in the TLS
+	// handshake, are not unreachable
+	canceledErr := &url.Error{Op: "Get", URL: "https://192.168.1.101:5554/v1/health", Err: context.Canceled}
+	assert.Equal(t, canceledErr, makeSendRequestProblem(host, canceledErr))
+	tlsErr := &url.Error{Op: "Get", URL: "https://192.168.1.101:5554/v1/health", Err: x509.UnknownAuthorityError{}}
+	assert.Equal(t, tlsErr, makeSendRequestProblem(host, tlsErr))
+}
+
+func TestResponseProblem(t *testing.T) {
+	const host = "192.168.1.101"
+	err := errors.New("Wrong password")
+	assert.ErrorIs(t, makeResponseProblem(host, UnauthorizedCode, "Wrong password", err),
+		rfc7807.New(rfc7807.WrongCredentials))
+	// a node that has not joined the cluster yet does not take credentials
+	notJoinedErr := errors.New("Local node has not joined cluster yet")
+	assert.Equal(t, notJoinedErr, makeResponseProblem(host, UnauthorizedCode, notJoinedErr.Error(), notJoinedErr))
+	assert.Equal(t, err, makeResponseProblem(host, InternalErrorCode, "Wrong password", err))
+}
+
+func TestProblemsOfCommands(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+
+	// options that are not valid
+	options := VCreateDatabaseOptionsFactory()
+	setFakeClusterOptions(&options.DatabaseOptions, cluster, fakeClusterHosts)
+	_, _, err := vcc.VCreateDatabase(context.Background(), &options)
+	problem := &rfc7807.VProblem{}
+	if assert.ErrorAs(t, err, &problem) {
+		assert.True(t, problem.IsInstanceOf(rfc7807.ValidationError))
+		assert.Empty(t, problem.Host)
+	}
+
+	// an op that fails on one host but not the others
+	cluster.SetNMARunning(fakeClusterHosts[2], false)
+	options.CatalogPrefix = defaultPath
+	options.DataPrefix = defaultPath
+	_, _, err = vcc.VCreateDatabase(context.Background(), &options)
+	if assert.ErrorAs(t, err, &problem) {
+		assert.True(t, problem.IsInstanceOf(rfc7807.PartialFailure))
+		assert.Contains(t, problem.Detail, "failed on host(s) [192.168.1.103]")
+	}
+	assert.ErrorIs(t, err, rfc7807.New(rfc7807.HostUnreachable))
+	assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+}
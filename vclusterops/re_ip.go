@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -117,7 +118,14 @@ func (opt *VReIPOptions) validateAnalyzeOptions(logger vlog.Printer) error {
 
 // VReIP changes the node address, control address, and control broadcast for a node.
 // It returns any error encountered.
-func (vcc VClusterCommands) VReIP(options *VReIPOptions) error {
+func (vcc VClusterCommands) VReIP(ctx context.Context, options *VReIPOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandReIP, &options.DatabaseOptions)
+	err := vcc.reIP(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) reIP(ctx context.Context, options *VReIPOptions) error {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -126,7 +134,7 @@ func (vcc VClusterCommands) VReIP(options *VReIPOptions) error {
 
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return makeValidationProblem(err)
 	}
 
 	// VER-93369 may improve this if the CLI knows which nodes are primary
@@ -137,7 +145,7 @@ func (vcc VClusterCommands) VReIP(options *VReIPOptions) error {
 		const warningMsg = " for an Eon database, re_ip after revive_db could fail " +
 			"because we cannot retrieve the correct database information"
 		if options.CommunalStorageLocation != "" {
-			vdb, e := options.getVDBWhenDBIsDown(vcc)
+			vdb, e := options.getVDBWhenDBIsDown(ctx, vcc)
 			if e != nil {
 				// show a warning message if we cannot get VDB from a down database
 				vcc.Log.PrintWarning("failed to retrieve the communal storage location" + warningMsg)
@@ -158,11 +166,10 @@ func (vcc VClusterCommands) VReIP(options *VReIPOptions) error {
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to re-ip: %w", runError)
 	}
@@ -203,7 +210,7 @@ func (vcc VClusterCommands) produceReIPInstructions(options *VReIPOptions, vdb *
 	// When we cannot get db info from cluster_config.json, we will fetch it from NMA /nodes endpoint.
 	if vdb == nil {
 		vdb = new(VCoordinationDatabase)
-		nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(options.Hosts, options.DBName, options.CatalogPrefix,
+		nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(options.Hosts, options.DBName, options.getCatalogPrefixes(options.Hosts),
 			false /* report all errors */, vdb)
 		// read catalog editor to get hosts with latest catalog
 		nmaReadCatEdOp, err := makeNMAReadCatalogEditorOp(vdb)
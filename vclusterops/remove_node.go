@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -101,16 +102,23 @@ func (o *VRemoveNodeOptions) validateAnalyzeOptions(log vlog.Printer) error {
 	return o.setUsePassword(log)
 }
 
-func (vcc VClusterCommands) VRemoveNode(options *VRemoveNodeOptions) (VCoordinationDatabase, error) {
+func (vcc VClusterCommands) VRemoveNode(ctx context.Context, options *VRemoveNodeOptions) (VCoordinationDatabase, *ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandRemoveNode, &options.DatabaseOptions)
+	vdb, err := vcc.removeNode(ctx, options)
+	report := call.finish(err)
+	return vdb, report, err
+}
+
+func (vcc VClusterCommands) removeNode(ctx context.Context, options *VRemoveNodeOptions) (VCoordinationDatabase, error) {
 	vdb := makeVCoordinationDatabase()
 
 	// validate and analyze options
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return vdb, err
+		return vdb, makeValidationProblem(err)
 	}
 
-	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+	err = vcc.getVDBFromRunningDB(ctx, &vdb, &options.DatabaseOptions)
 	if err != nil {
 		return vdb, err
 	}
@@ -134,18 +142,19 @@ func (vcc VClusterCommands) VRemoveNode(options *VRemoveNodeOptions) (VCoordinat
 	var hostsNotInCatalog []string
 	options.HostsToRemove, hostsNotInCatalog = vdb.containNodes(options.HostsToRemove)
 
-	vdb, err = vcc.removeNodesInCatalog(options, &vdb)
+	vdb, err = vcc.removeNodesInCatalog(ctx, options, &vdb)
 	if err != nil || len(hostsNotInCatalog) == 0 {
 		return vdb, err
 	}
 
-	return vcc.handleRemoveNodeForHostsNotInCatalog(&vdb, options, hostsNotInCatalog)
+	return vcc.handleRemoveNodeForHostsNotInCatalog(ctx, &vdb, options, hostsNotInCatalog)
 }
 
 // removeNodesInCatalog will perform the steps to remove nodes. The node list in
 // options.HostsToRemove has already been verified that each node is in the
 // catalog.
-func (vcc VClusterCommands) removeNodesInCatalog(options *VRemoveNodeOptions, vdb *VCoordinationDatabase) (VCoordinationDatabase, error) {
+func (vcc VClusterCommands) removeNodesInCatalog(ctx context.Context,
+	options *VRemoveNodeOptions, vdb *VCoordinationDatabase) (VCoordinationDatabase, error) {
 	if len(options.HostsToRemove) == 0 {
 		vcc.Log.Info("Exit early because there are no hosts to remove")
 		return *vdb, nil
@@ -164,14 +173,17 @@ func (vcc VClusterCommands) removeNodesInCatalog(options *VRemoveNodeOptions, vd
 
 	remainingHosts := util.SliceDiff(vdb.HostList, options.HostsToRemove)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	if runError := clusterOpEngine.run(vcc.Log); runError != nil {
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	if runError := clusterOpEngine.run(ctx, vcc.Log); runError != nil {
+		var plan *DryRunPlan
+		if errors.As(runError, &plan) {
+			return vdb.copy(remainingHosts), runError
+		}
 		// If the machines of the to-be-removed nodes crashed or get killed,
 		// the run error may be ignored.
 		// Here we check whether the to-be-removed nodes are still in the catalog.
 		// If they have been removed from catalog, we let remove_node succeed.
-		if vcc.findRemovedNodesInCatalog(options, remainingHosts) {
+		if vcc.findRemovedNodesInCatalog(ctx, options, remainingHosts) {
 			return *vdb, fmt.Errorf("fail to complete remove node operation, %w", runError)
 		}
 		// If the target nodes have already been removed from catalog,
@@ -187,17 +199,17 @@ func (vcc VClusterCommands) removeNodesInCatalog(options *VRemoveNodeOptions, vd
 // handleRemoveNodeForHostsNotInCatalog will build and execute a list of
 // instructions to do remove of hosts that aren't present in the catalog. We
 // will do basic cleanup logic for this needed by the operator.
-func (vcc VClusterCommands) handleRemoveNodeForHostsNotInCatalog(vdb *VCoordinationDatabase, options *VRemoveNodeOptions,
+func (vcc VClusterCommands) handleRemoveNodeForHostsNotInCatalog(ctx context.Context,
+	vdb *VCoordinationDatabase, options *VRemoveNodeOptions,
 	missingHosts []string) (VCoordinationDatabase, error) {
 	vcc.Log.Info("Doing cleanup of hosts missing from database", "hostsNotInCatalog", missingHosts)
 
 	// We need to find the paths for the hosts we are removing.
-	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(missingHosts, options.DBName, options.CatalogPrefix,
+	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(missingHosts, options.DBName, options.getCatalogPrefixes(missingHosts),
 		false /* report all errors */, vdb)
 	instructions := []clusterOp{&nmaGetNodesInfoOp}
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	opEng := makeClusterOpEngine(instructions, &certs)
-	err := opEng.run(vcc.Log)
+	opEng := options.makeClusterOpEngine(instructions)
+	err := opEng.run(ctx, vcc.Log)
 	if err != nil {
 		return *vdb, fmt.Errorf("failed to get node info for missing hosts: %w", err)
 	}
@@ -217,8 +229,8 @@ func (vcc VClusterCommands) handleRemoveNodeForHostsNotInCatalog(vdb *VCoordinat
 		return *vdb, err
 	}
 	instructions = []clusterOp{&nmaDeleteDirectoriesOp}
-	opEng = makeClusterOpEngine(instructions, &certs)
-	err = opEng.run(vcc.Log)
+	opEng = options.makeClusterOpEngine(instructions)
+	err = opEng.run(ctx, vcc.Log)
 	if err != nil {
 		return *vdb, fmt.Errorf("failed to delete directories for missing hosts: %w", err)
 	}
@@ -254,10 +266,10 @@ func checkRemoveNodeRequirements(vdb *VCoordinationDatabase, options *VRemoveNod
 func (o *VRemoveNodeOptions) completeVDBSetting(vdb *VCoordinationDatabase) error {
 	vdb.DataPrefix = o.DataPrefix
 
-	if o.DepotPrefix == "" {
+	if o.DepotPrefix == "" && len(o.HostDepotPrefixes) == 0 {
 		return nil
 	}
-	if vdb.IsEon {
+	if vdb.IsEon && o.DepotPrefix != "" {
 		// checking this here because now we have got eon value from
 		// the running db. This will be removed once we are able to get
 		// the depot path from db through an https endpoint(VER-88122).
@@ -267,14 +279,8 @@ func (o *VRemoveNodeOptions) completeVDBSetting(vdb *VCoordinationDatabase) erro
 		}
 	}
 	vdb.DepotPrefix = o.DepotPrefix
-	hostNodeMap := makeVHostNodeMap()
-	// TODO: we set the depot path from /nodes rather than manually
-	// (VER-92725). This is useful for nmaDeleteDirectoriesOp.
-	for h, vnode := range vdb.HostNodeMap {
-		vnode.DepotPath = vdb.genDepotPath(vnode.Name)
-		hostNodeMap[h] = vnode
-	}
-	vdb.HostNodeMap = hostNodeMap
+	// the depot paths that /nodes reports are kept
+	o.completeDepotPaths(vdb)
 	return nil
 }
 
@@ -489,21 +495,21 @@ func (o *VRemoveNodeOptions) setInitiator(primaryUpNodes []string) error {
 }
 
 // findRemovedNodesInCatalog checks whether the to-be-removed nodes are still in catalog.
-// Return true if they are still in catalog.
-func (vcc VClusterCommands) findRemovedNodesInCatalog(options *VRemoveNodeOptions,
+// Return true if they are still in catalog, or if the catalog cannot be checked.
+func (vcc VClusterCommands) findRemovedNodesInCatalog(ctx context.Context, options *VRemoveNodeOptions,
 	remainingHosts []string) bool {
 	fetchNodeStateOpt := VFetchNodeStateOptionsFactory()
-	fetchNodeStateOpt.DBName = options.DBName
+	// the ports, certs and timeouts are the ones of remove_node, and the
+	// ops run in its call, so they are also added to its report
+	fetchNodeStateOpt.DatabaseOptions = options.DatabaseOptions
 	fetchNodeStateOpt.RawHosts = remainingHosts
-	fetchNodeStateOpt.IPv6 = options.IPv6
-	fetchNodeStateOpt.UserName = options.UserName
-	fetchNodeStateOpt.Password = options.Password
 
 	var nodesInformation nodesInfo
-	res, err := vcc.VFetchNodeState(&fetchNodeStateOpt)
+	res, err := vcc.fetchNodeState(ctx, &fetchNodeStateOpt)
 	if err != nil {
+		// the nodes may not have been removed, so the run error is not ignored
 		vcc.Log.PrintWarning("Fail to fetch states of the nodes, detail: %v", err)
-		return false
+		return true
 	}
 	nodesInformation.NodeList = res
 
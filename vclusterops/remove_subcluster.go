@@ -16,6 +16,8 @@
 package vclusterops
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -120,18 +122,26 @@ func (o *VRemoveScOptions) validateAnalyzeOptions(logger vlog.Printer) error {
 //  1. Pre-check: check the subcluster name and get nodes for the subcluster.
 //  2. Removes nodes: Optional. If there are any nodes still associated with the subcluster, runs VRemoveNode.
 //  3. Drop the subcluster: Remove the subcluster name from the database catalog.
-func (vcc VClusterCommands) VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VCoordinationDatabase, error) {
+func (vcc VClusterCommands) VRemoveSubcluster(ctx context.Context,
+	removeScOpt *VRemoveScOptions) (VCoordinationDatabase, *ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandRemoveCluster, &removeScOpt.DatabaseOptions)
+	vdb, err := vcc.removeSubcluster(ctx, removeScOpt)
+	report := call.finish(err)
+	return vdb, report, err
+}
+
+func (vcc VClusterCommands) removeSubcluster(ctx context.Context, removeScOpt *VRemoveScOptions) (VCoordinationDatabase, error) {
 	vdb := makeVCoordinationDatabase()
 
 	// validate and analyze options
 	err := removeScOpt.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return vdb, err
+		return vdb, makeValidationProblem(err)
 	}
 
 	// pre-check: should not remove the default subcluster
 	vcc.PrintInfo("Performing db_remove_subcluster pre-checks")
-	hostsToRemove, err := vcc.removeScPreCheck(&vdb, removeScOpt)
+	hostsToRemove, err := vcc.removeScPreCheck(ctx, &vdb, removeScOpt)
 	if err != nil {
 		return vdb, err
 	}
@@ -148,6 +158,7 @@ func (vcc VClusterCommands) VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VC
 		needRemoveNodes = true
 	}
 
+	var removeNodePlan *DryRunPlan
 	if needRemoveNodes {
 		// Remove nodes from the target subcluster
 		removeNodeOpt := VRemoveNodeOptionsFactory()
@@ -157,15 +168,19 @@ func (vcc VClusterCommands) VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VC
 
 		vcc.Log.PrintInfo("Removing nodes %q from subcluster %s",
 			hostsToRemove, removeScOpt.SubclusterToRemove)
-		vdb, err = vcc.VRemoveNode(&removeNodeOpt)
-		if err != nil {
+		vdb, err = vcc.removeNode(ctx, &removeNodeOpt)
+		// in dry run, we go on to plan dropping the subcluster as well
+		if err != nil && !errors.As(err, &removeNodePlan) {
 			return vdb, err
 		}
 	}
 
 	// drop subcluster (i.e., remove the sc name from catalog)
 	vcc.Log.PrintInfo("Removing the subcluster name from catalog")
-	err = vcc.dropSubcluster(&vdb, removeScOpt)
+	err = vcc.dropSubcluster(ctx, &vdb, removeScOpt)
+	if removeNodePlan != nil {
+		return vdb, removeNodePlan.merge(err)
+	}
 	if err != nil {
 		return vdb, err
 	}
@@ -188,12 +203,12 @@ func (e *removeDefaultSubclusterError) Error() string {
 // for a successful remove_node:
 //   - Get cluster and nodes info (check if the target DB is Eon and get to-be-removed node list)
 //   - Get the subcluster info (check if the target sc exists and if it is the default sc)
-func (vcc VClusterCommands) removeScPreCheck(vdb *VCoordinationDatabase, options *VRemoveScOptions) ([]string, error) {
+func (vcc VClusterCommands) removeScPreCheck(ctx context.Context, vdb *VCoordinationDatabase, options *VRemoveScOptions) ([]string, error) {
 	var hostsToRemove []string
 	const preCheckErrMsg = "while performing db_remove_subcluster pre-checks"
 
 	// get cluster and nodes info
-	err := vcc.getVDBFromRunningDB(vdb, &options.DatabaseOptions)
+	err := vcc.getVDBFromRunningDB(ctx, vdb, &options.DatabaseOptions)
 	if err != nil {
 		return hostsToRemove, err
 	}
@@ -226,9 +241,8 @@ func (vcc VClusterCommands) removeScPreCheck(vdb *VCoordinationDatabase, options
 		&httpsFindSubclusterOp,
 	)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		// VER-88585 will improve this rfc error flow
 		if strings.Contains(err.Error(), "does not exist in the database") {
@@ -259,19 +273,12 @@ func (vcc VClusterCommands) removeScPreCheck(vdb *VCoordinationDatabase, options
 func (o *VRemoveScOptions) completeVDBSetting(vdb *VCoordinationDatabase) error {
 	vdb.DataPrefix = o.DataPrefix
 	vdb.DepotPrefix = o.DepotPrefix
-
-	hostNodeMap := makeVHostNodeMap()
-	// TODO: we set the depot path from /nodes rather than manually
-	// (VER-92725). This is useful for nmaDeleteDirectoriesOp.
-	for h, vnode := range vdb.HostNodeMap {
-		vnode.DepotPath = vdb.genDepotPath(vnode.Name)
-		hostNodeMap[h] = vnode
-	}
-	vdb.HostNodeMap = hostNodeMap
+	// the depot paths that /nodes reports are kept
+	o.completeDepotPaths(vdb)
 	return nil
 }
 
-func (vcc VClusterCommands) dropSubcluster(vdb *VCoordinationDatabase, options *VRemoveScOptions) error {
+func (vcc VClusterCommands) dropSubcluster(ctx context.Context, vdb *VCoordinationDatabase, options *VRemoveScOptions) error {
 	dropScErrMsg := fmt.Sprintf("fail to drop subcluster %s", options.SubclusterToRemove)
 
 	// the initiator is a list of one primary up host
@@ -293,9 +300,8 @@ func (vcc VClusterCommands) dropSubcluster(vdb *VCoordinationDatabase, options *
 	var instructions []clusterOp
 	instructions = append(instructions, &httpsDropScOp)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		vcc.Log.Error(err, "fail to drop subcluster, details: %v", dropScErrMsg)
 		return err
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -112,7 +113,14 @@ func (opt *VReplicationDatabaseOptions) validateAnalyzeOptions(logger vlog.Print
 }
 
 // VReplicateDatabase can copy all table data and metadata from this cluster to another
-func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOptions) error {
+func (vcc VClusterCommands) VReplicateDatabase(ctx context.Context, options *VReplicationDatabaseOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandReplicationStart, &options.DatabaseOptions)
+	err := vcc.replicateDatabase(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) replicateDatabase(ctx context.Context, options *VReplicationDatabaseOptions) error {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -122,7 +130,7 @@ func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOpti
 	// validate and analyze options
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return makeValidationProblem(err)
 	}
 
 	// produce database replication instructions
@@ -132,11 +140,10 @@ func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOpti
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		if strings.Contains(runError.Error(), "EnableConnectCredentialForwarding is false") {
 			runError = fmt.Errorf("target database authentication failed, need to do one of the following things: " +
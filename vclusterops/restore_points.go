@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -142,7 +143,16 @@ func (opt *VShowRestorePointsOptions) validateAnalyzeOptions(logger vlog.Printer
 }
 
 // VShowRestorePoints can query the restore points from an archive
-func (vcc VClusterCommands) VShowRestorePoints(options *VShowRestorePointsOptions) (restorePoints []RestorePoint, err error) {
+func (vcc VClusterCommands) VShowRestorePoints(ctx context.Context,
+	options *VShowRestorePointsOptions) (restorePoints []RestorePoint, report *ExecutionReport, err error) {
+	ctx, call := vcc.startCall(ctx, commandShowRestorePoints, &options.DatabaseOptions)
+	restorePoints, err = vcc.showRestorePoints(ctx, options)
+	report = call.finish(err)
+	return restorePoints, report, err
+}
+
+func (vcc VClusterCommands) showRestorePoints(ctx context.Context,
+	options *VShowRestorePointsOptions) (restorePoints []RestorePoint, err error) {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -152,7 +162,7 @@ func (vcc VClusterCommands) VShowRestorePoints(options *VShowRestorePointsOption
 	// validate and analyze options
 	err = options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return restorePoints, err
+		return restorePoints, makeValidationProblem(err)
 	}
 
 	// produce show restore points instructions
@@ -162,11 +172,10 @@ func (vcc VClusterCommands) VShowRestorePoints(options *VShowRestorePointsOption
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return restorePoints, fmt.Errorf("fail to show restore points: %w", runError)
 	}
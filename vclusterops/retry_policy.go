@@ -0,0 +1,110 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// retryPolicy decides whether a failed host request should be sent again,
+// and how long to wait before sending it
+type retryPolicy interface {
+	// getBackoff returns the time to wait before the given retry, which
+	// starts from 1, and false if the request should not be retried anymore.
+	// elapsed is the time spent since the request was first sent.
+	getBackoff(retry int, elapsed time.Duration) (time.Duration, bool)
+}
+
+// exponentialBackoffPolicy doubles the wait time after each retry, up to
+// maxBackoff. The wait time is randomized by up to jitter (a fraction of it),
+// so that requests to many hosts are not retried all at once. No retry is
+// done once maxRetries or maxElapsed is reached.
+type exponentialBackoffPolicy struct {
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	maxElapsed     time.Duration
+	jitter         float64
+}
+
+const (
+	defaultInitialBackoff = 1 * time.Second
+	defaultMaxBackoff     = 10 * time.Second
+	defaultMaxElapsed     = 1 * time.Minute
+	defaultBackoffJitter  = 0.2
+)
+
+// defaultRetryPolicy is used by idempotent requests that do not set a retry policy
+var defaultRetryPolicy retryPolicy = &exponentialBackoffPolicy{
+	maxRetries:     util.DefaultRetryCount,
+	initialBackoff: defaultInitialBackoff,
+	maxBackoff:     defaultMaxBackoff,
+	maxElapsed:     defaultMaxElapsed,
+	jitter:         defaultBackoffJitter,
+}
+
+func (policy *exponentialBackoffPolicy) getBackoff(retry int, elapsed time.Duration) (time.Duration, bool) {
+	if retry > policy.maxRetries {
+		return 0, false
+	}
+
+	backoff := policy.initialBackoff
+	for i := 1; i < retry && backoff < policy.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > policy.maxBackoff {
+		backoff = policy.maxBackoff
+	}
+	if policy.jitter > 0 {
+		// a value in [-jitter, jitter) of the backoff
+		//nolint:gosec // no need of a secure random number for the jitter
+		delta := (rand.Float64()*2 - 1) * policy.jitter * float64(backoff)
+		backoff += time.Duration(delta)
+	}
+
+	if policy.maxElapsed > 0 && elapsed+backoff > policy.maxElapsed {
+		return 0, false
+	}
+	return backoff, true
+}
+
+// isRetryable returns true if the request failed on an error that may
+// go away when the request is sent again, like a connection reset, a
+// timeout, or the server being temporarily unavailable
+func (hostResult *hostHTTPResult) isRetryable() bool {
+	if hostResult.isPassing() {
+		return false
+	}
+	switch hostResult.statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	if !hostResult.isException() {
+		return false
+	}
+	return hostResult.isTimeout() ||
+		errors.Is(hostResult.err, syscall.ECONNRESET) ||
+		errors.Is(hostResult.err, io.ErrUnexpectedEOF) ||
+		errors.Is(hostResult.err, io.EOF)
+}
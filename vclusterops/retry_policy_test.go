@@ -0,0 +1,109 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBackoffPolicy(t *testing.T) {
+	policy := exponentialBackoffPolicy{
+		maxRetries:     4,
+		initialBackoff: time.Second,
+		maxBackoff:     5 * time.Second,
+		maxElapsed:     time.Minute,
+	}
+
+	// the backoff doubles after each retry, up to maxBackoff
+	for retry, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		backoff, ok := policy.getBackoff(retry+1, 0)
+		assert.True(t, ok)
+		assert.Equal(t, expected, backoff)
+	}
+
+	// no more retry after maxRetries
+	_, ok := policy.getBackoff(5, 0)
+	assert.False(t, ok)
+
+	// no more retry once maxElapsed would be exceeded
+	_, ok = policy.getBackoff(1, time.Minute)
+	assert.False(t, ok)
+
+	// the jitter keeps the backoff within the given fraction
+	policy.jitter = 0.5
+	for i := 0; i < 100; i++ {
+		backoff, ok := policy.getBackoff(2, 0)
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, backoff, time.Second)
+		assert.Less(t, backoff, 3*time.Second)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, (&hostHTTPResult{status: SUCCESS, statusCode: SuccessCode}).isRetryable())
+	assert.True(t, (&hostHTTPResult{status: FAILURE, statusCode: http.StatusServiceUnavailable,
+		err: errors.New("unavailable")}).isRetryable())
+	assert.False(t, (&hostHTTPResult{status: FAILURE, statusCode: InternalErrorCode,
+		err: errors.New("internal error")}).isRetryable())
+	assert.False(t, (&hostHTTPResult{status: EXCEPTION, err: errors.New("connection refused")}).isRetryable())
+}
+
+func TestSendRequestWithRetry(t *testing.T) {
+	var requestCount atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// the server is unavailable for the first two requests
+		if requestCount.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	adapter := setupAdapterForServer(t, server)
+
+	password := "password"
+	request := hostHTTPRequest{Method: GetMethod, Username: "dbadmin", Password: &password}
+	request.buildHTTPSEndpoint("nodes")
+	request.RetryPolicy = &exponentialBackoffPolicy{
+		maxRetries:     3,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     time.Millisecond,
+		maxElapsed:     time.Minute,
+	}
+
+	// non-idempotent requests are not retried
+	result := adapter.sendRequestWithRetry(context.Background(), &request)
+	assert.Equal(t, FAILURE, result.status)
+	assert.Equal(t, 1, result.attempts)
+	assert.Empty(t, result.retriedErrs)
+
+	// idempotent requests are retried until they succeed
+	request.Idempotent = true
+	result = adapter.sendRequestWithRetry(context.Background(), &request)
+	assert.Equal(t, SUCCESS, result.status)
+	assert.Equal(t, "ok", result.content)
+	assert.Equal(t, 2, result.attempts)
+	assert.Len(t, result.retriedErrs, 1)
+	assert.Equal(t, int32(3), requestCount.Load())
+}
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
@@ -29,8 +30,6 @@ type VReviveDatabaseOptions struct {
 
 	/* part 2: revive db info */
 
-	// timeout in seconds of loading remote catalog
-	LoadCatalogTimeout uint
 	// whether force remove existing directories before revive the database
 	ForceRemoval bool
 	// describe the database on communal storage, and exit
@@ -119,9 +118,6 @@ func VReviveDBOptionsFactory() VReviveDatabaseOptions {
 
 func (options *VReviveDatabaseOptions) setDefaultValues() {
 	options.DatabaseOptions.setDefaultValues()
-
-	// set default values for revive db options
-	options.LoadCatalogTimeout = util.DefaultLoadCatalogTimeoutSeconds
 }
 
 func (options *VReviveDatabaseOptions) validateRequiredOptions() error {
@@ -190,7 +186,16 @@ func (options *VReviveDatabaseOptions) validateAnalyzeOptions() error {
 
 // VReviveDatabase revives a database that was terminated but whose communal storage data still exists.
 // It returns the database information retrieved from communal storage and any error encountered.
-func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (dbInfo string, vdbPtr *VCoordinationDatabase, err error) {
+func (vcc VClusterCommands) VReviveDatabase(ctx context.Context,
+	options *VReviveDatabaseOptions) (dbInfo string, vdbPtr *VCoordinationDatabase, report *ExecutionReport, err error) {
+	ctx, call := vcc.startCall(ctx, commandReviveDB, &options.DatabaseOptions)
+	dbInfo, vdbPtr, err = vcc.reviveDatabase(ctx, options)
+	report = call.finish(err)
+	return dbInfo, vdbPtr, report, err
+}
+
+func (vcc VClusterCommands) reviveDatabase(ctx context.Context,
+	options *VReviveDatabaseOptions) (dbInfo string, vdbPtr *VCoordinationDatabase, err error) {
 	/*
 	 *   - Validate options
 	 *   - Run VClusterOpEngine to get terminated database info
@@ -199,6 +204,12 @@ func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (db
 
 	// validate and analyze options
 	err = options.validateAnalyzeOptions()
+	if err != nil {
+		return dbInfo, nil, makeValidationProblem(err)
+	}
+
+	// the directories are prepared only once if the command is resumed
+	err = getCall(ctx).startJournal(&options.DatabaseOptions, commandReviveDB)
 	if err != nil {
 		return dbInfo, nil, err
 	}
@@ -211,11 +222,9 @@ func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (db
 		return dbInfo, nil, fmt.Errorf("fail to produce pre-revive database instructions %w", err)
 	}
 
-	// generate clusterOpEngine certs
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
 	// feed the pre-revive db instructions to the VClusterOpEngine
-	clusterOpEngine := makeClusterOpEngine(preReviveDBInstructions, &certs)
-	err = clusterOpEngine.run(vcc.GetLog())
+	clusterOpEngine := options.makeClusterOpEngine(preReviveDBInstructions)
+	err = clusterOpEngine.run(ctx, vcc.GetLog())
 	if err != nil {
 		return dbInfo, nil, fmt.Errorf("fail to collect the information of database in revive_db %w", err)
 	}
@@ -232,8 +241,8 @@ func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (db
 		}
 
 		// feed the restore db specific instructions to the VClusterOpEngine
-		clusterOpEngine = makeClusterOpEngine(restoreDBSpecificInstructions, &certs)
-		runErr := clusterOpEngine.run(vcc.GetLog())
+		clusterOpEngine = options.makeClusterOpEngine(restoreDBSpecificInstructions)
+		runErr := clusterOpEngine.run(ctx, vcc.GetLog())
 		if runErr != nil {
 			return dbInfo, &vdb, fmt.Errorf("fail to collect the restore-specific information of database in revive_db %w", runErr)
 		}
@@ -251,8 +260,8 @@ func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (db
 	}
 
 	// feed revive db instructions to the VClusterOpEngine
-	clusterOpEngine = makeClusterOpEngine(reviveDBInstructions, &certs)
-	err = clusterOpEngine.run(vcc.GetLog())
+	clusterOpEngine = options.makeClusterOpEngine(reviveDBInstructions)
+	err = clusterOpEngine.run(ctx, vcc.GetLog())
 	if err != nil {
 		return dbInfo, &vdb, fmt.Errorf("fail to revive database %w", err)
 	}
@@ -410,13 +419,11 @@ func (vcc VClusterCommands) produceReviveDBInstructions(options *VReviveDatabase
 	nmaNetworkProfileOp := makeNMANetworkProfileOp(options.Hosts)
 
 	nmaLoadRemoteCatalogOp := makeNMALoadRemoteCatalogOp(oldHosts, options.ConfigurationParameters,
-		&newVDB, options.LoadCatalogTimeout, &options.RestorePoint)
+		&newVDB, getTimeout(options.Timeouts.LoadCatalog, defaultLoadCatalogTimeout), &options.RestorePoint)
 
-	instructions = append(instructions,
-		&nmaPrepareDirectoriesOp,
-		&nmaNetworkProfileOp,
-		&nmaLoadRemoteCatalogOp,
-	)
+	// the directories and the network profiles do not depend on each other
+	instructions = append(instructions, runConcurrently(&nmaPrepareDirectoriesOp, &nmaNetworkProfileOp)...)
+	instructions = append(instructions, &nmaLoadRemoteCatalogOp)
 
 	return instructions, nil
 }
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -133,6 +134,20 @@ func (vcc *VClusterCommands) produceSandboxSubclusterInstructions(options *VSand
 		return instructions, err
 	}
 
+	// Unsandbox the subcluster if its nodes do not come up in the sandbox
+	unsandboxOptions := VUnsandboxOptionsFactory()
+	unsandboxOptions.DatabaseOptions = options.DatabaseOptions
+	unsandboxOptions.SCName = options.SCName
+	// the nodes restart in the sandbox, so they are stopped first
+	unsandboxOptions.hasUpNodeInSC = true
+	// and they are restarted in the main cluster, as they were before the
+	// subcluster was sandboxed
+	unsandboxOptions.RestartSC = true
+	httpsSandboxSubclusterOp.unsandboxInstructions, err = vcc.produceUnsandboxSCInstructions(&unsandboxOptions)
+	if err != nil {
+		return instructions, err
+	}
+
 	// Poll for sandboxed nodes to be up
 	httpsPollSubclusterNodeOp, err := makeHTTPSPollSubclusterNodeStateUpOp(options.SCName,
 		usePassword, username, options.Password)
@@ -150,20 +165,27 @@ func (vcc *VClusterCommands) produceSandboxSubclusterInstructions(options *VSand
 	return instructions, nil
 }
 
-func (vcc VClusterCommands) VSandbox(options *VSandboxOptions) error {
+func (vcc VClusterCommands) VSandbox(ctx context.Context, options *VSandboxOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandSandboxSC, &options.DatabaseOptions)
+	err := vcc.sandbox(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) sandbox(ctx context.Context, options *VSandboxOptions) error {
 	vcc.Log.V(0).Info("VSandbox method called", "options", options)
-	return runSandboxCmd(vcc, options)
+	return runSandboxCmd(ctx, vcc, options)
 }
 
 // sandboxInterface is an interface that will be used by runSandboxCmd().
 // The purpose of this interface is to avoid code duplication.
 type sandboxInterface interface {
 	ValidateAnalyzeOptions(vcc VClusterCommands) error
-	runCommand(vcc VClusterCommands) error
+	runCommand(ctx context.Context, vcc VClusterCommands) error
 }
 
 // runCommand will produce instructions and run them
-func (options *VSandboxOptions) runCommand(vcc VClusterCommands) error {
+func (options *VSandboxOptions) runCommand(ctx context.Context, vcc VClusterCommands) error {
 	// make instructions
 	instructions, err := vcc.produceSandboxSubclusterInstructions(options)
 	if err != nil {
@@ -171,11 +193,10 @@ func (options *VSandboxOptions) runCommand(vcc VClusterCommands) error {
 	}
 
 	// add certs and instructions to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// run the engine
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to sandbox subcluster %s, %w", options.SCName, runError)
 	}
@@ -184,13 +205,13 @@ func (options *VSandboxOptions) runCommand(vcc VClusterCommands) error {
 
 // runSandboxCmd is a help function to run sandbox/unsandbox command.
 // It can avoid code duplication between VSandbox and VUnsandbox.
-func runSandboxCmd(vcc VClusterCommands, i sandboxInterface) error {
+func runSandboxCmd(ctx context.Context, vcc VClusterCommands, i sandboxInterface) error {
 	// check required options
 	err := i.ValidateAnalyzeOptions(vcc)
 	if err != nil {
 		vcc.Log.Error(err, "failed to validate the options")
-		return err
+		return makeValidationProblem(err)
 	}
 
-	return i.runCommand(vcc)
+	return i.runCommand(ctx, vcc)
 }
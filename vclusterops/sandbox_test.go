@@ -0,0 +1,50 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestSandboxUndoRestartsSubcluster(t *testing.T) {
+	vcc := VClusterCommands{}
+	vcc.Log = vlog.Printer{}
+	options := VSandboxOptionsFactory()
+	options.DBName = "test_db"
+	options.Hosts = []string{"192.168.1.101"}
+	options.UserName = "dbadmin"
+	options.Password = new(string)
+	options.SCName = "sc1"
+	options.SandboxName = "sand"
+
+	instructions, err := vcc.produceSandboxSubclusterInstructions(&options)
+	assert.NoError(t, err)
+	sandboxOp, ok := instructions[2].(*httpsSandboxingOp)
+	assert.True(t, ok)
+
+	// the undo unsandboxes the subcluster, and starts its nodes again in the
+	// main cluster
+	var undoOpNames []string
+	for _, op := range sandboxOp.unsandboxInstructions {
+		undoOpNames = append(undoOpNames, op.getName())
+	}
+	assert.Equal(t, []string{"HTTPSGetUpNodesOp", "HTTPSStopNodeOp", "HTTPSPollSubclusterNodeStateOp",
+		"HTTPSUnsansboxingOp", "NMADeleteDirectoriesOp", "NMACheckVerticaVersionOp", "startupOp",
+		"NMAStartNodeOp", "HTTPSPollSubclusterNodeStateOp"}, undoOpNames)
+}
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -39,6 +40,10 @@ const ScrutinizeLogMaxAgeHoursDefault = 24              // copy archived logs pr
 const scrutinizeLogLimitBytes = 10 * 1024 * 1024 * 1024 // 10GB in bytes is the limit for individual log size
 const scrutinizeFileLimitBytes = 100 * 1024 * 1024      // 100 MB in bytes is the limit for individual misc file size
 
+// tarballs downloaded at once if no maximum number of concurrent requests is
+// given, so that a large cluster does not saturate the network
+const scrutinizeMaxConcurrentDownloads = 4
+
 // batches are fixed, top level folders for each node's data
 const scrutinizeBatchNormal = "normal"
 const scrutinizeBatchContext = "context"
@@ -185,19 +190,26 @@ func (options *VScrutinizeOptions) ValidateAnalyzeOptions(logger vlog.Printer) e
 	return options.analyzeOptions(logger)
 }
 
-func (vcc VClusterCommands) VScrutinize(options *VScrutinizeOptions) error {
+func (vcc VClusterCommands) VScrutinize(ctx context.Context, options *VScrutinizeOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, VScrutinizeTypeName, &options.DatabaseOptions)
+	err := vcc.scrutinize(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) scrutinize(ctx context.Context, options *VScrutinizeOptions) error {
 	// check required options (including those that can come from cluster config)
 	err := options.ValidateAnalyzeOptions(vcc.Log)
 	if err != nil {
 		vcc.Log.Error(err, "validation of scrutinize arguments failed")
-		return err
+		return makeValidationProblem(err)
 	}
 
 	// populate vdb with:
 	// 1. slice of nodes with NMA running
 	// 2. host -> node info map
 	vdb := makeVCoordinationDatabase()
-	err = options.getVDBForScrutinize(vcc.Log, &vdb)
+	err = options.getVDBForScrutinize(ctx, vcc.Log, &vdb)
 	if err != nil {
 		vcc.Log.Error(err, "failed to retrieve cluster info for scrutinize")
 		return err
@@ -211,7 +223,7 @@ func (vcc VClusterCommands) VScrutinize(options *VScrutinizeOptions) error {
 		vcc.Log.Error(err, "failed to produce instructions for scrutinize")
 		return err
 	}
-	err = options.runClusterOpEngine(vcc.Log, instructions)
+	err = options.runClusterOpEngine(ctx, vcc.Log, instructions)
 	if err != nil {
 		vcc.Log.Error(err, "failed to run scrutinize operations")
 		return err
@@ -272,19 +284,19 @@ func tarAndRemoveDirectory(tarballName, id string, log vlog.Printer) (err error)
 
 // getVDBForScrutinize populates an empty coordinator database with the minimum
 // required information for further scrutinize operations.
-func (options *VScrutinizeOptions) getVDBForScrutinize(logger vlog.Printer,
+func (options *VScrutinizeOptions) getVDBForScrutinize(ctx context.Context, logger vlog.Printer,
 	vdb *VCoordinationDatabase) error {
 	// get nodes where NMA is running and only use those for NMA ops
 	getHealthyNodesOp := makeNMAGetHealthyNodesOp(options.Hosts, vdb)
-	err := options.runClusterOpEngine(logger, []clusterOp{&getHealthyNodesOp})
+	err := options.runClusterOpEngine(ctx, logger, []clusterOp{&getHealthyNodesOp})
 	if err != nil {
 		return err
 	}
 
 	// get map of host to node name and fully qualified catalog path
 	getNodesInfoOp := makeNMAGetNodesInfoOp(vdb.HostList, options.DBName,
-		options.CatalogPrefix, true /* ignore internal errors */, vdb)
-	err = options.runClusterOpEngine(logger, []clusterOp{&getNodesInfoOp})
+		options.getCatalogPrefixes(vdb.HostList), true /* ignore internal errors */, vdb)
+	err = options.runClusterOpEngine(ctx, logger, []clusterOp{&getNodesInfoOp})
 	if err != nil {
 		return err
 	}
@@ -383,8 +395,9 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 	instructions = append(instructions, &stageCommandsOp)
 
 	// get 'normal' batch tarball (inc. Vertica logs and 'normal' batch files)
+	maxConcurrentDownloads := getScrutinizeMaxConcurrentDownloads(options.MaxConcurrentRequests)
 	getNormalTarballOp, err := makeNMAGetScrutinizeTarOp(options.ID, scrutinizeBatchNormal,
-		options.Hosts, hostNodeNameMap)
+		options.Hosts, hostNodeNameMap, maxConcurrentDownloads)
 	if err != nil {
 		return nil, err
 	}
@@ -392,7 +405,7 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 
 	// get 'context' batch tarball (inc. 'context' batch files)
 	getContextTarballOp, err := makeNMAGetScrutinizeTarOp(options.ID, scrutinizeBatchContext,
-		options.Hosts, hostNodeNameMap)
+		options.Hosts, hostNodeNameMap, maxConcurrentDownloads)
 	if err != nil {
 		return nil, err
 	}
@@ -400,7 +413,7 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 
 	// get 'system_tables' batch tarball last, as staging systables can take a long time
 	getSystemTablesTarballOp, err := makeNMAGetScrutinizeTarOp(options.ID, scrutinizeBatchSystemTables,
-		options.Hosts, hostNodeNameMap)
+		options.Hosts, hostNodeNameMap, maxConcurrentDownloads)
 	if err != nil {
 		return nil, err
 	}
@@ -410,6 +423,16 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 	return instructions, nil
 }
 
+// getScrutinizeMaxConcurrentDownloads returns the number of tarballs that are
+// downloaded at once, which is the maximum number of concurrent requests if
+// one is given
+func getScrutinizeMaxConcurrentDownloads(maxConcurrentRequests int) int {
+	if maxConcurrentRequests > 0 {
+		return maxConcurrentRequests
+	}
+	return scrutinizeMaxConcurrentDownloads
+}
+
 func getNodeInfoForScrutinize(hosts []string, vdb *VCoordinationDatabase,
 ) (hostNodeNameMap, hostCatPathMap map[string]string, err error) {
 	hostNodeNameMap = make(map[string]string)
@@ -42,7 +42,7 @@ func (op *scrutinizeOpBase) setupClusterHTTPRequest(hosts []string) error {
 
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = op.httpMethod
-		httpRequest.buildNMAEndpoint(scrutinizeURLPrefix + op.id + "/" + nodeName + "/" + op.batch + op.urlSuffix)
+		httpRequest.buildNMAEndpoint(scrutinizeURLPrefix+"{id}/{node}/"+op.batch+op.urlSuffix, op.id, nodeName)
 		if op.hostRequestBodyMap != nil {
 			httpRequest.RequestData = op.hostRequestBodyMap[host]
 		}
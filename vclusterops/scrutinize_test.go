@@ -148,3 +148,11 @@ func TestSetLogAgeRange(t *testing.T) {
 	assert.ErrorContains(t, err, "invalid time range: max log age cannot be less than min log age")
 	assert.Contains(t, logBuf.String(), "invalid log age range")
 }
+
+func TestGetScrutinizeMaxConcurrentDownloads(t *testing.T) {
+	// a few tarballs at once by default
+	assert.Equal(t, scrutinizeMaxConcurrentDownloads, getScrutinizeMaxConcurrentDownloads(0))
+	// the maximum number of concurrent requests, whether it is lower or higher
+	assert.Equal(t, 2, getScrutinizeMaxConcurrentDownloads(2))
+	assert.Equal(t, 16, getScrutinizeMaxConcurrentDownloads(16))
+}
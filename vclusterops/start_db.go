@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -27,8 +28,6 @@ import (
 type VStartDatabaseOptions struct {
 	// basic db info
 	DatabaseOptions
-	// timeout for polling the states of all nodes in the database in HTTPSPollNodeStateOp
-	StatePollingTimeout int
 	// whether trim the input host list based on the catalog info
 	TrimHostList bool
 	// If the path is set, the NMA will store the Vertica start command at the path
@@ -49,7 +48,6 @@ func VStartDatabaseOptionsFactory() VStartDatabaseOptions {
 
 func (options *VStartDatabaseOptions) setDefaultValues() {
 	options.DatabaseOptions.setDefaultValues()
-	options.StatePollingTimeout = util.DefaultStatePollingTimeout
 }
 
 func (options *VStartDatabaseOptions) validateRequiredOptions(logger vlog.Printer) error {
@@ -98,7 +96,15 @@ func (options *VStartDatabaseOptions) validateAnalyzeOptions(logger vlog.Printer
 	return options.analyzeOptions()
 }
 
-func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbPtr *VCoordinationDatabase, err error) {
+func (vcc VClusterCommands) VStartDatabase(ctx context.Context,
+	options *VStartDatabaseOptions) (vdbPtr *VCoordinationDatabase, report *ExecutionReport, err error) {
+	ctx, call := vcc.startCall(ctx, commandStartDB, &options.DatabaseOptions)
+	vdbPtr, err = vcc.startDatabase(ctx, options)
+	report = call.finish(err)
+	return vdbPtr, report, err
+}
+
+func (vcc VClusterCommands) startDatabase(ctx context.Context, options *VStartDatabaseOptions) (vdbPtr *VCoordinationDatabase, err error) {
 	/*
 	 *   - Produce Instructions
 	 *   - Create VClusterOpEngine
@@ -108,7 +114,7 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 	// validate and analyze all options
 	err = options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return nil, err
+		return nil, makeValidationProblem(err)
 	}
 
 	// VER-93369 may improve this if the CLI knows which nodes are primary
@@ -119,7 +125,7 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 		const warningMsg = " for an Eon database, start_db after revive_db could fail " +
 			"because we cannot retrieve the correct database information"
 		if options.CommunalStorageLocation != "" {
-			vdbNew, e := options.getVDBWhenDBIsDown(vcc)
+			vdbNew, e := options.getVDBWhenDBIsDown(ctx, vcc)
 			if e != nil {
 				// show a warning message if we cannot get VDB from a down database
 				vcc.Log.PrintWarning("failed to retrieve the communal storage location" + warningMsg)
@@ -136,7 +142,7 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 	}
 
 	// start_db pre-checks and get basic info
-	err = vcc.runStartDBPrecheck(options, &vdb)
+	err = vcc.runStartDBPrecheck(ctx, options, &vdb)
 	if err != nil {
 		return nil, err
 	}
@@ -148,18 +154,17 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 	}
 
 	// create a VClusterOpEngine for start_db instructions, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return nil, fmt.Errorf("fail to start database: %w", runError)
 	}
 
 	// get vdb info from the running database
 	var updatedVDB VCoordinationDatabase
-	err = vcc.getVDBFromRunningDBIncludeSandbox(&updatedVDB, &options.DatabaseOptions, AnySandbox)
+	err = vcc.getVDBFromRunningDBIncludeSandbox(ctx, &updatedVDB, &options.DatabaseOptions, AnySandbox)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +172,7 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 	return &updatedVDB, nil
 }
 
-func (vcc VClusterCommands) runStartDBPrecheck(options *VStartDatabaseOptions, vdb *VCoordinationDatabase) error {
+func (vcc VClusterCommands) runStartDBPrecheck(ctx context.Context, options *VStartDatabaseOptions, vdb *VCoordinationDatabase) error {
 	// pre-instruction to perform basic checks and get basic information
 	preInstructions, err := vcc.produceStartDBPreCheck(options, vdb, options.TrimHostList)
 	if err != nil {
@@ -175,9 +180,8 @@ func (vcc VClusterCommands) runStartDBPrecheck(options *VStartDatabaseOptions, v
 	}
 
 	// create a VClusterOpEngine for pre-check, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(preInstructions, &certs)
-	runError := clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(preInstructions)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to start database pre-checks: %w", runError)
 	}
@@ -245,7 +249,7 @@ func (vcc VClusterCommands) produceStartDBPreCheck(options *VStartDatabaseOption
 
 	// when we cannot get db info from cluster_config.json, we will fetch it from NMA /nodes endpoint.
 	if len(vdb.HostNodeMap) == 0 {
-		nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(options.Hosts, options.DBName, options.CatalogPrefix,
+		nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(options.Hosts, options.DBName, options.getCatalogPrefixes(options.Hosts),
 			true /* ignore internal errors */, vdb)
 		instructions = append(instructions, &nmaGetNodesInfoOp)
 	}
@@ -306,7 +310,8 @@ func (vcc VClusterCommands) produceStartDBInstructions(options *VStartDatabaseOp
 
 	nmaStartNewNodesOp := makeNMAStartNodeOp(options.Hosts, options.StartUpConf)
 	httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOpWithTimeoutAndCommand(options.Hosts,
-		options.usePassword, options.UserName, options.Password, options.StatePollingTimeout, StartDBCmd)
+		options.usePassword, options.UserName, options.Password,
+		getTimeout(options.Timeouts.StatePolling, defaultStatePollingTimeout), StartDBCmd)
 	if err != nil {
 		return instructions, err
 	}
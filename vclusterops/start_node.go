@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -30,8 +31,6 @@ type VStartNodesOptions struct {
 	DatabaseOptions
 	// A set of nodes(nodename - host) that we want to start in the database
 	Nodes map[string]string
-	// timeout for polling nodes that we want to start in httpsPollNodeStateOp
-	StatePollingTimeout int
 	// If the path is set, the NMA will store the Vertica start command at the path
 	// instead of executing it. This is useful in containerized environments where
 	// you may not want to have both the NMA and Vertica server in the same container.
@@ -135,27 +134,29 @@ func (vcc VClusterCommands) startNodePreCheck(vdb *VCoordinationDatabase, option
 // node's IP in the Vertica catalog. If cluster quorum is already lost, use
 // VStartDatabase. It will skip any nodes given that no longer exist in the
 // catalog.
-func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
+func (vcc VClusterCommands) VStartNodes(ctx context.Context, options *VStartNodesOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandStartNode, &options.DatabaseOptions)
+	err := vcc.startNodes(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) startNodes(ctx context.Context, options *VStartNodesOptions) error {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
 	 *   - Give the instructions to the VClusterOpEngine to run
 	 */
 
-	// set default value to StatePollingTimeout
-	if options.StatePollingTimeout == 0 {
-		options.StatePollingTimeout = util.DefaultStatePollingTimeout
-	}
-
 	// validate and analyze options
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return makeValidationProblem(err)
 	}
 
 	// retrieve database information to execute the command so we do not always rely on some user input
 	vdb := makeVCoordinationDatabase()
-	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, AnySandbox)
+	err = vcc.getVDBFromRunningDBIncludeSandbox(ctx, &vdb, &options.DatabaseOptions, AnySandbox)
 	if err != nil {
 		return err
 	}
@@ -174,7 +175,7 @@ func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
 	}
 
 	// sandboxes may have different catalog from the main cluster, update the vdb build from the sandbox of the nodes to restart
-	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, restartNodeInfo.Sandbox)
+	err = vcc.getVDBFromRunningDBIncludeSandbox(ctx, &vdb, &options.DatabaseOptions, restartNodeInfo.Sandbox)
 	if err != nil {
 		if restartNodeInfo.Sandbox != util.MainClusterSandbox {
 			return errors.Join(err, fmt.Errorf("hint: make sure there is at least one UP node in the sandbox %s", restartNodeInfo.Sandbox))
@@ -221,11 +222,10 @@ func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	err = clusterOpEngine.run(vcc.Log)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		return fmt.Errorf("fail to restart node, %w", err)
 	}
@@ -323,7 +323,8 @@ func (vcc VClusterCommands) produceStartNodesInstructions(startNodeInfo *VStartN
 
 	nmaRestartNewNodesOp := makeNMAStartNodeOpWithVDB(startNodeInfo.HostsToStart, options.StartUpConf, vdb)
 	httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOpWithTimeoutAndCommand(startNodeInfo.HostsToStart,
-		options.usePassword, options.UserName, options.Password, options.StatePollingTimeout, StartNodeCmd)
+		options.usePassword, options.UserName, options.Password,
+		getTimeout(options.Timeouts.StatePolling, defaultStatePollingTimeout), StartNodeCmd)
 	if err != nil {
 		return instructions, err
 	}
@@ -16,47 +16,83 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"time"
+
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/util"
 )
 
 const (
-	OneSecond                = 1
-	OneMinute                = 60 * OneSecond
-	StopDBTimeout            = 5 * OneMinute
-	StartupPollingTimeout    = 5 * OneMinute
-	ScrutinizePollingTimeout = -1 * OneMinute // no timeout
-	PollingInterval          = 3 * OneSecond
+	StopDBTimeout         = 5 * time.Minute
+	StartupPollingTimeout = 5 * time.Minute
 )
 
+// pollingSchedule is how long pollState waits between two polls. The first
+// polls are close together, as the state is often reached quickly, and the
+// interval then grows by the multiplier after each poll, up to the max
+// interval, so that a long wait does not load the cluster.
+type pollingSchedule struct {
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	multiplier      float64
+}
+
+// defaultPollingSchedule polls after 1s, 1.5s, 2.25s, ... and then every 15s
+var defaultPollingSchedule = pollingSchedule{
+	initialInterval: time.Second,
+	maxInterval:     15 * time.Second,
+	multiplier:      1.5,
+}
+
+// getInterval returns how long to wait after the given poll, starting at 1
+func (schedule pollingSchedule) getInterval(iteration int) time.Duration {
+	interval := float64(schedule.initialInterval)
+	for i := 1; i < iteration && interval < float64(schedule.maxInterval); i++ {
+		interval *= schedule.multiplier
+	}
+	return util.Min(time.Duration(interval), schedule.maxInterval)
+}
+
 type statePoller interface {
-	getPollingTimeout() int
+	getName() string
+	// the default polling timeout of the op, which the StatePolling timeout
+	// of the TimeoutPolicy replaces if it is set
+	getPollingTimeout() time.Duration
+	// how long to wait between two polls, defaultPollingSchedule for most ops
+	getPollingSchedule() pollingSchedule
+	// shouldStopPolling returns true once the state is reached. It returns
+	// an error to stop polling when the state cannot be reached anymore,
+	// such as when a node that was expected to come up went down.
 	shouldStopPolling() (bool, error)
-	runExecute(execContext *opEngineExecContext) error
+	runExecute(ctx context.Context, execContext *opEngineExecContext) error
 }
 
 // pollState is a helper function to poll state for all ops that implement the StatePoller interface.
-// If poller.getPollingTimeout() returns a value < 0, pollState will poll forever.
-func pollState(poller statePoller, execContext *opEngineExecContext) error {
+// If the polling timeout is negative, pollState will poll forever.
+// Polling stops early with the context error if ctx is canceled.
+func pollState(ctx context.Context, poller statePoller, execContext *opEngineExecContext) error {
 	startTime := time.Now()
-	timeout := poller.getPollingTimeout()
-	duration := time.Duration(timeout) * time.Second
-	count := 0
+	timeout := execContext.dispatcher.timeouts.getPollingTimeout(poller.getPollingTimeout())
+	schedule := poller.getPollingSchedule()
 	needTimeout := true
 	if timeout < 0 {
 		needTimeout = false
 	}
 
-	for endTime := startTime.Add(duration); ; {
-		if needTimeout && time.Now().After(endTime) {
-			break
-		}
-
-		if count > 0 {
-			time.Sleep(PollingInterval * time.Second)
-		}
-
+	endTime := startTime.Add(timeout)
+	for iteration := 1; !needTimeout || time.Now().Before(endTime); iteration++ {
 		shouldStopPoll, err := poller.shouldStopPolling()
+		interval := schedule.getInterval(iteration)
+		if needTimeout {
+			// the last poll is sent at the timeout
+			interval = util.Max(util.Min(interval, time.Until(endTime)), 0)
+		}
+		execContext.dispatcher.progress.polling(poller, iteration, time.Since(startTime), interval)
+		execContext.dispatcher.metrics.countPolling(poller)
+		execContext.dispatcher.logger.Info("Polled the state", "op", poller.getName(), "iteration", iteration,
+			"elapsed", time.Since(startTime), "nextPollIn", interval)
 		if err != nil {
 			return err
 		}
@@ -65,12 +101,68 @@ func pollState(poller statePoller, execContext *opEngineExecContext) error {
 			return nil
 		}
 
-		if err := poller.runExecute(execContext); err != nil {
+		if err := poller.runExecute(ctx, execContext); err != nil {
+			return err
+		}
+
+		if err := sleepWithContext(ctx, interval); err != nil {
 			return err
 		}
+	}
 
-		count++
+	// the state may be reached by the last poll, whose results are only
+	// checked here, as the loop stops at the timeout
+	shouldStopPoll, err := poller.shouldStopPolling()
+	if err != nil {
+		return err
 	}
+	if shouldStopPoll {
+		return nil
+	}
+	return makeProblem(rfc7807.PollingTimeout, "", fmt.Errorf("reached polling timeout of %s", timeout))
+}
+
+// getPollingSchedule returns the schedule of the ops that poll, unless they
+// have one of their own
+func (op *opBase) getPollingSchedule() pollingSchedule {
+	return defaultPollingSchedule
+}
+
+// nodeStateTracker remembers the hosts whose node has been UP while an op
+// polls for the nodes to come up. A node that goes DOWN after that has
+// failed, so the op stops polling instead of waiting for the timeout.
+type nodeStateTracker struct {
+	upHosts map[string]bool
+}
 
-	return fmt.Errorf("reached polling timeout of %d seconds", timeout)
+// checkNodeState returns an error if the node of the host is DOWN, and it
+// was UP in an earlier poll
+func (tracker *nodeStateTracker) checkNodeState(opName, host, state string) error {
+	switch state {
+	case util.NodeUpState:
+		if tracker.upHosts == nil {
+			tracker.upHosts = make(map[string]bool)
+		}
+		tracker.upHosts[host] = true
+	case util.NodeDownState:
+		if tracker.upHosts[host] {
+			return fmt.Errorf("[%s] the node on host %s went down after it came up, please check vertica.log on the host",
+				opName, host)
+		}
+	}
+	return nil
+}
+
+// sleepWithContext pauses for the given duration. It returns early with the
+// context error if ctx is canceled before the duration elapses.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
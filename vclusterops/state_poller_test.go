@@ -0,0 +1,135 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestPollingSchedule(t *testing.T) {
+	schedule := defaultPollingSchedule
+	assert.Equal(t, time.Second, schedule.getInterval(1))
+	assert.Equal(t, 1500*time.Millisecond, schedule.getInterval(2))
+	assert.Equal(t, 2250*time.Millisecond, schedule.getInterval(3))
+	// the interval stops growing at the max interval
+	assert.Equal(t, 15*time.Second, schedule.getInterval(8))
+	assert.Equal(t, 15*time.Second, schedule.getInterval(1000))
+}
+
+func TestNodeStateTracker(t *testing.T) {
+	tracker := nodeStateTracker{}
+	// a node that is not up yet can be down
+	assert.NoError(t, tracker.checkNodeState("PollOp", "host1", util.NodeDownState))
+	assert.NoError(t, tracker.checkNodeState("PollOp", "host1", util.NodeUpState))
+	assert.NoError(t, tracker.checkNodeState("PollOp", "host2", util.NodeDownState))
+	// but it fails if it goes down after it came up
+	err := tracker.checkNodeState("PollOp", "host1", util.NodeDownState)
+	assert.ErrorContains(t, err, "[PollOp] the node on host host1 went down after it came up")
+}
+
+// failingPoller is a statePoller that fails after a number of polls
+type failingPoller struct {
+	opBase
+	polls     int
+	failAfter int
+}
+
+func (*failingPoller) getPollingTimeout() time.Duration {
+	return time.Hour
+}
+
+func (*failingPoller) getPollingSchedule() pollingSchedule {
+	return pollingSchedule{initialInterval: time.Millisecond, maxInterval: 4 * time.Millisecond, multiplier: 2}
+}
+
+func (poller *failingPoller) shouldStopPolling() (bool, error) {
+	poller.polls++
+	if poller.polls > poller.failAfter {
+		return true, errors.New("the node went down")
+	}
+	return false, nil
+}
+
+func (*failingPoller) runExecute(context.Context, *opEngineExecContext) error {
+	return nil
+}
+
+func TestPollStateStopsOnFailure(t *testing.T) {
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	var events []ProgressEvent
+	execContext.dispatcher.progress = makeProgressReporter(func(event ProgressEvent) {
+		events = append(events, event)
+	})
+	poller := &failingPoller{failAfter: 4}
+	poller.name = "FailingPoller"
+
+	// the poller fails long before the timeout
+	err := pollState(context.Background(), poller, &execContext)
+	assert.EqualError(t, err, "the node went down")
+	assert.Equal(t, 5, poller.polls)
+
+	// each poll is reported, with the interval to the next one
+	if assert.Len(t, events, 5) {
+		intervals := []time.Duration{}
+		for i := range events {
+			assert.Equal(t, PollingEvent, events[i].Type)
+			assert.Equal(t, i+1, events[i].Iteration)
+			intervals = append(intervals, events[i].NextPollIn)
+		}
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond,
+			4 * time.Millisecond, 4 * time.Millisecond}, intervals)
+	}
+}
+
+// latePoller is a statePoller whose state is reached by its first poll,
+// which is sent in the last interval before the timeout
+type latePoller struct {
+	opBase
+	executed bool
+}
+
+func (*latePoller) getPollingTimeout() time.Duration {
+	return 50 * time.Millisecond
+}
+
+func (*latePoller) getPollingSchedule() pollingSchedule {
+	return pollingSchedule{initialInterval: time.Second, maxInterval: time.Second, multiplier: 1}
+}
+
+func (poller *latePoller) shouldStopPolling() (bool, error) {
+	return poller.executed, nil
+}
+
+func (poller *latePoller) runExecute(context.Context, *opEngineExecContext) error {
+	poller.executed = true
+	return nil
+}
+
+func TestPollStateChecksLastPoll(t *testing.T) {
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	poller := &latePoller{}
+	poller.name = "LatePoller"
+
+	// the results of the poll sent before the timeout are still checked
+	assert.NoError(t, pollState(context.Background(), poller, &execContext))
+}
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -119,7 +120,14 @@ func (options *VStopDatabaseOptions) validateAnalyzeOptions(log vlog.Printer) er
 	return options.analyzeOptions()
 }
 
-func (vcc VClusterCommands) VStopDatabase(options *VStopDatabaseOptions) error {
+func (vcc VClusterCommands) VStopDatabase(ctx context.Context, options *VStopDatabaseOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandStopDB, &options.DatabaseOptions)
+	err := vcc.stopDatabase(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) stopDatabase(ctx context.Context, options *VStopDatabaseOptions) error {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -129,12 +137,12 @@ func (vcc VClusterCommands) VStopDatabase(options *VStopDatabaseOptions) error {
 	// validate and analyze all options
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return makeValidationProblem(err)
 	}
 
 	// get vdb and check requirements
 	vdb := makeVCoordinationDatabase()
-	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, AnySandbox)
+	err = vcc.getVDBFromRunningDBIncludeSandbox(ctx, &vdb, &options.DatabaseOptions, AnySandbox)
 	if err != nil {
 		vcc.LogError(err, "failed to get vdb from running db")
 	} else {
@@ -151,11 +159,10 @@ func (vcc VClusterCommands) VStopDatabase(options *VStopDatabaseOptions) error {
 	}
 
 	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to stop database: %w", runError)
 	}
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -78,15 +79,22 @@ func (o *VStopNodeOptions) validateAnalyzeOptions(logger vlog.Printer) error {
 
 // VStopNode stops a host in an existing database.
 // It returns any error encountered.
-func (vcc VClusterCommands) VStopNode(options *VStopNodeOptions) error {
+func (vcc VClusterCommands) VStopNode(ctx context.Context, options *VStopNodeOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandStopNode, &options.DatabaseOptions)
+	err := vcc.stopNode(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) stopNode(ctx context.Context, options *VStopNodeOptions) error {
 	vdb := makeVCoordinationDatabase()
 
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return makeValidationProblem(err)
 	}
 
-	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+	err = vcc.getVDBFromRunningDB(ctx, &vdb, &options.DatabaseOptions)
 	if err != nil {
 		return err
 	}
@@ -105,9 +113,8 @@ func (vcc VClusterCommands) VStopNode(options *VStopNodeOptions) error {
 		return fmt.Errorf("fail to produce stop node instructions, %w", err)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	if runError := clusterOpEngine.run(vcc.Log); runError != nil {
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	if runError := clusterOpEngine.run(ctx, vcc.Log); runError != nil {
 		return fmt.Errorf("fail to complete stop node operation, %w", runError)
 	}
 	return nil
@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -109,7 +110,14 @@ func (options *VStopSubclusterOptions) validateAnalyzeOptions(log vlog.Printer)
 	return options.analyzeOptions()
 }
 
-func (vcc VClusterCommands) VStopSubcluster(options *VStopSubclusterOptions) error {
+func (vcc VClusterCommands) VStopSubcluster(ctx context.Context, options *VStopSubclusterOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandStopCluster, &options.DatabaseOptions)
+	err := vcc.stopSubcluster(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) stopSubcluster(ctx context.Context, options *VStopSubclusterOptions) error {
 	/*
 	 *   - Validate Options
 	 *   - Produce Instructions
@@ -120,7 +128,7 @@ func (vcc VClusterCommands) VStopSubcluster(options *VStopSubclusterOptions) err
 	// validate and analyze all options
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return makeValidationProblem(err)
 	}
 
 	instructions, err := vcc.produceStopSCInstructions(options)
@@ -129,11 +137,10 @@ func (vcc VClusterCommands) VStopSubcluster(options *VStopSubclusterOptions) err
 	}
 
 	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("failed to stop subcluster %s: %w", options.SCName, runError)
 	}
@@ -0,0 +1,125 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// the timeouts of the commands when the TimeoutPolicy does not set them
+const (
+	defaultNodeStartupTimeout  = time.Duration(util.DefaultTimeoutSeconds) * time.Second
+	defaultStatePollingTimeout = time.Duration(util.DefaultStatePollingTimeout) * time.Second
+	defaultLoadCatalogTimeout  = time.Duration(util.DefaultLoadCatalogTimeoutSeconds) * time.Second
+)
+
+// NoTimeout is a timeout of a TimeoutPolicy that means there is no limit.
+// Any negative timeout means the same.
+const NoTimeout time.Duration = -1
+
+// TimeoutPolicy bounds how long a VClusterCommands call waits. A timeout
+// that is zero means the default of the command, and a negative one, such
+// as NoTimeout, means there is no limit.
+type TimeoutPolicy struct {
+	// Command is the deadline of the whole call, from when it starts. The
+	// call fails with an OpCanceledError once it is reached. There is no
+	// deadline by default.
+	Command time.Duration
+	// Request is how long a request to a host can take, for the requests
+	// that an op does not give a timeout of its own. The default is 5
+	// minutes.
+	Request time.Duration
+	// StatePolling is how long an op polls the state of the nodes, such as
+	// when it waits for them to come up or go down. The default is the one
+	// of the NODE_STATE_POLLING_TIMEOUT environment variable, in seconds, if
+	// it is set. Otherwise it is set by the command: 5 minutes for create_db
+	// and most of the others, and 20 minutes for start_db and restart_node.
+	StatePolling time.Duration
+	// LoadCatalog is how long revive_db waits for the catalog to be loaded
+	// from communal storage. The default is one hour.
+	LoadCatalog time.Duration
+}
+
+// withCommandDeadline returns the context that the call runs with, which is
+// canceled once the Command timeout has passed, if there is one
+func (policy *TimeoutPolicy) withCommandDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if policy.Command <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, policy.Command)
+}
+
+// getRequestTimeout returns the timeout of the http.Client that sends a
+// request with the given timeout, where 0 means no timeout
+func (policy *TimeoutPolicy) getRequestTimeout(requestTimeout time.Duration) time.Duration {
+	timeout := getTimeout(requestTimeout, getTimeout(policy.Request, defaultRequestTimeout))
+	return util.Max(timeout, 0)
+}
+
+// getPollingTimeout returns how long an op polls the state of the nodes:
+// the StatePolling timeout if it is set, or else the one of the environment,
+// or else the default of the op
+func (policy *TimeoutPolicy) getPollingTimeout(opTimeout time.Duration) time.Duration {
+	envTimeout, _ := getEnvPollingTimeout()
+	return getTimeout(policy.StatePolling, getTimeout(envTimeout, opTimeout))
+}
+
+// getTimeout returns the default timeout if the timeout is not set
+func getTimeout(timeout, defaultTimeout time.Duration) time.Duration {
+	if timeout == 0 {
+		return defaultTimeout
+	}
+	return timeout
+}
+
+// statePollingTimeoutEnv sets, in seconds, the StatePolling timeout of the
+// calls that do not set it
+const statePollingTimeoutEnv = "NODE_STATE_POLLING_TIMEOUT"
+
+var (
+	envPollingTimeoutOnce sync.Once
+	envPollingTimeout     time.Duration
+	envPollingTimeoutErr  error
+)
+
+// getEnvPollingTimeout returns the StatePolling timeout of the environment,
+// or 0 if it is not set. The environment is read once. An invalid value is
+// an error, which the validation of the options reports.
+func getEnvPollingTimeout() (time.Duration, error) {
+	envPollingTimeoutOnce.Do(func() {
+		envPollingTimeout, envPollingTimeoutErr = readEnvPollingTimeout()
+	})
+	return envPollingTimeout, envPollingTimeoutErr
+}
+
+func readEnvPollingTimeout() (time.Duration, error) {
+	timeoutSecondStr := os.Getenv(statePollingTimeoutEnv)
+	if timeoutSecondStr == "" {
+		return 0, nil
+	}
+	timeoutSecond, err := strconv.Atoi(timeoutSecondStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout value %s of %s: %w", timeoutSecondStr, statePollingTimeoutEnv, err)
+	}
+	return time.Duration(timeoutSecond) * time.Second, nil
+}
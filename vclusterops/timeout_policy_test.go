@@ -0,0 +1,108 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/fakecluster"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestTimeoutPolicy(t *testing.T) {
+	policy := TimeoutPolicy{}
+	// the timeout of the op comes first, then the one of the policy, then
+	// the default
+	assert.Equal(t, defaultRequestTimeout, policy.getRequestTimeout(0))
+	assert.Equal(t, 20*time.Second, policy.getRequestTimeout(20*time.Second))
+	assert.Equal(t, time.Duration(0), policy.getRequestTimeout(NoTimeout))
+	assert.Equal(t, StartupPollingTimeout, policy.getPollingTimeout(StartupPollingTimeout))
+
+	policy = TimeoutPolicy{Request: time.Minute, StatePolling: NoTimeout}
+	assert.Equal(t, time.Minute, policy.getRequestTimeout(0))
+	assert.Equal(t, 20*time.Second, policy.getRequestTimeout(20*time.Second))
+	assert.Equal(t, NoTimeout, policy.getPollingTimeout(StartupPollingTimeout))
+
+	policy = TimeoutPolicy{Request: NoTimeout}
+	assert.Equal(t, time.Duration(0), policy.getRequestTimeout(0))
+}
+
+func TestEnvPollingTimeout(t *testing.T) {
+	t.Setenv(statePollingTimeoutEnv, "")
+	timeout, err := readEnvPollingTimeout()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), timeout)
+
+	t.Setenv(statePollingTimeoutEnv, "30")
+	timeout, err = readEnvPollingTimeout()
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	t.Setenv(statePollingTimeoutEnv, "soon")
+	_, err = readEnvPollingTimeout()
+	assert.ErrorContains(t, err, "invalid timeout value soon of NODE_STATE_POLLING_TIMEOUT")
+}
+
+// neverDonePoller is a statePoller whose state is never reached
+type neverDonePoller struct {
+	opBase
+}
+
+func (*neverDonePoller) getPollingTimeout() time.Duration {
+	return time.Hour
+}
+
+func (*neverDonePoller) shouldStopPolling() (bool, error) {
+	return false, nil
+}
+
+func (*neverDonePoller) runExecute(context.Context, *opEngineExecContext) error {
+	return nil
+}
+
+func TestPollingTimeoutOfPolicy(t *testing.T) {
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	execContext.dispatcher.timeouts.StatePolling = time.Nanosecond
+	poller := &neverDonePoller{}
+	poller.name = "NeverDonePoller"
+
+	// the timeout of the policy replaces the one of the op
+	err := pollState(context.Background(), poller, &execContext)
+	assert.ErrorContains(t, err, "reached polling timeout of 1ns")
+	assert.ErrorIs(t, err, rfc7807.New(rfc7807.PollingTimeout))
+}
+
+func TestCommandDeadline(t *testing.T) {
+	cluster := fakecluster.New(fakeClusterHosts...)
+	defer cluster.Close()
+	vcc := makeFakeClusterCommands(cluster)
+	options := VCreateDatabaseOptionsFactory()
+	setFakeClusterOptions(&options.DatabaseOptions, cluster, fakeClusterHosts)
+	options.CatalogPrefix = defaultPath
+	options.DataPrefix = defaultPath
+	options.Timeouts.Command = time.Nanosecond
+
+	_, _, err := vcc.VCreateDatabase(context.Background(), &options)
+	var canceledErr *OpCanceledError
+	assert.ErrorAs(t, err, &canceledErr)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Empty(t, cluster.DatabaseName())
+}
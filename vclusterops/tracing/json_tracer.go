@@ -0,0 +1,118 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK    = "OK"
+	StatusError = "ERROR"
+)
+
+// SpanRecord is a span that has ended, as JSONTracer writes it
+type SpanRecord struct {
+	TraceID      string         `json:"traceId"`
+	SpanID       string         `json:"spanId"`
+	ParentSpanID string         `json:"parentSpanId,omitempty"`
+	Name         string         `json:"name"`
+	StartTime    time.Time      `json:"startTime"`
+	EndTime      time.Time      `json:"endTime"`
+	Duration     time.Duration  `json:"durationNs"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	// StatusOK, or StatusError with the error in StatusMessage
+	Status        string `json:"status"`
+	StatusMessage string `json:"statusMessage,omitempty"`
+}
+
+// JSONTracer writes each span, when it ends, as a line of JSON. It is safe
+// for concurrent use.
+type JSONTracer struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+func NewJSONTracer(w io.Writer) *JSONTracer {
+	return &JSONTracer{encoder: json.NewEncoder(w)}
+}
+
+func (tracer *JSONTracer) Start(name string, parent SpanContext) Span {
+	return &jsonSpan{
+		tracer: tracer,
+		record: SpanRecord{
+			ParentSpanID: parent.SpanID,
+			Name:         name,
+			StartTime:    time.Now(),
+		},
+		context: newSpanContext(parent),
+	}
+}
+
+// Err returns the first error that happened while writing the spans
+func (tracer *JSONTracer) Err() error {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	return tracer.err
+}
+
+func (tracer *JSONTracer) write(record *SpanRecord) {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	if err := tracer.encoder.Encode(record); err != nil && tracer.err == nil {
+		tracer.err = err
+	}
+}
+
+type jsonSpan struct {
+	tracer  *JSONTracer
+	context SpanContext
+	mu      sync.Mutex
+	record  SpanRecord
+}
+
+func (span *jsonSpan) SpanContext() SpanContext {
+	return span.context
+}
+
+func (span *jsonSpan) SetAttribute(key string, value any) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	if span.record.Attributes == nil {
+		span.record.Attributes = make(map[string]any)
+	}
+	span.record.Attributes[key] = value
+}
+
+func (span *jsonSpan) End(err error) {
+	span.mu.Lock()
+	record := span.record
+	span.mu.Unlock()
+	record.TraceID = span.context.TraceID
+	record.SpanID = span.context.SpanID
+	record.EndTime = time.Now()
+	record.Duration = record.EndTime.Sub(record.StartTime)
+	record.Status = StatusOK
+	if err != nil {
+		record.Status = StatusError
+		record.StatusMessage = err.Error()
+	}
+	span.tracer.write(&record)
+}
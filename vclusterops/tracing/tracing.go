@@ -0,0 +1,136 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package tracing records what the vcluster commands spend their time on as
+// spans: one for each command, one for each of its operations, and one for
+// each request an operation sends to a host. The spans follow the model of
+// OpenTelemetry, so a Tracer can be an adapter to an OpenTelemetry tracer,
+// and the trace context is sent to the hosts in the W3C traceparent header.
+//
+// The default is NoopTracer, which records nothing. JSONTracer writes the
+// spans as JSON lines for offline analysis.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+)
+
+// TraceParentHeader is the W3C trace context header sent with the requests
+const TraceParentHeader = "traceparent"
+
+// SpanContext identifies a span and its trace. The IDs are hex encoded,
+// 32 characters for a trace ID and 16 for a span ID, as in OpenTelemetry.
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+}
+
+// IsValid returns true if the span context identifies a span
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != "" && sc.SpanID != ""
+}
+
+// Tracer starts the spans
+type Tracer interface {
+	// Start starts a span with the given name. The parent is the zero
+	// SpanContext for the root span of a trace.
+	Start(name string, parent SpanContext) Span
+}
+
+// Span is a unit of work. A span must be safe for use by the goroutine that
+// ends it while its children are started from other goroutines.
+type Span interface {
+	SpanContext() SpanContext
+	SetAttribute(key string, value any)
+	// End ends the span, and records that it failed if err is not nil
+	End(err error)
+}
+
+// NoopTracer starts spans that record nothing
+type NoopTracer struct{}
+
+func (NoopTracer) Start(string, SpanContext) Span {
+	return noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SpanContext() SpanContext { return SpanContext{} }
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) End(error)                {}
+
+type activeSpanKey struct{}
+
+// activeSpan is the span in a context, and the tracer that its children are
+// started with
+type activeSpan struct {
+	tracer Tracer
+	span   Span
+}
+
+// Start starts a span with the tracer, the child of the span in ctx if
+// there is one, and returns a context that holds the new span
+func Start(ctx context.Context, tracer Tracer, name string) (context.Context, Span) {
+	var parent SpanContext
+	if active, ok := ctx.Value(activeSpanKey{}).(activeSpan); ok {
+		parent = active.span.SpanContext()
+	}
+	span := tracer.Start(name, parent)
+	return context.WithValue(ctx, activeSpanKey{}, activeSpan{tracer: tracer, span: span}), span
+}
+
+// StartChild starts a child of the span in ctx, with the tracer of that
+// span. If ctx has no span, it returns ctx and a span that records nothing.
+func StartChild(ctx context.Context, name string) (context.Context, Span) {
+	active, ok := ctx.Value(activeSpanKey{}).(activeSpan)
+	if !ok {
+		return ctx, noopSpan{}
+	}
+	return Start(ctx, active.tracer, name)
+}
+
+// Inject sets the traceparent header to the span in ctx, so that the
+// receiver of a request can continue the trace
+func Inject(ctx context.Context, header http.Header) {
+	active, ok := ctx.Value(activeSpanKey{}).(activeSpan)
+	if !ok {
+		return
+	}
+	sc := active.span.SpanContext()
+	if sc.IsValid() {
+		header.Set(TraceParentHeader, fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID))
+	}
+}
+
+// newSpanContext returns the context of a new span, in the trace of the
+// parent, or in a new trace if there is no parent
+func newSpanContext(parent SpanContext) SpanContext {
+	sc := SpanContext{TraceID: parent.TraceID, SpanID: randomID(8)}
+	if sc.TraceID == "" {
+		sc.TraceID = randomID(16)
+	}
+	return sc
+}
+
+func randomID(size int) string {
+	id := make([]byte, size)
+	// crypto/rand does not fail on the platforms vcluster runs on
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,95 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewJSONTracer(&buf)
+
+	ctx, root := Start(context.Background(), tracer, "create_db")
+	root.SetAttribute("vcluster.database", "test_db")
+	childCtx, child := StartChild(ctx, "NMAHealthOp")
+	header := http.Header{}
+	Inject(childCtx, header)
+	child.End(errors.New("host is down"))
+	root.End(nil)
+	assert.NoError(t, tracer.Err())
+
+	var records []SpanRecord
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record SpanRecord
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	// the spans are written as they end
+	if !assert.Len(t, records, 2) {
+		return
+	}
+	childRecord, rootRecord := records[0], records[1]
+	assert.Equal(t, "create_db", rootRecord.Name)
+	assert.Len(t, rootRecord.TraceID, 32)
+	assert.Len(t, rootRecord.SpanID, 16)
+	assert.Empty(t, rootRecord.ParentSpanID)
+	assert.Equal(t, StatusOK, rootRecord.Status)
+	assert.Equal(t, map[string]any{"vcluster.database": "test_db"}, rootRecord.Attributes)
+
+	assert.Equal(t, "NMAHealthOp", childRecord.Name)
+	assert.Equal(t, rootRecord.TraceID, childRecord.TraceID)
+	assert.Equal(t, rootRecord.SpanID, childRecord.ParentSpanID)
+	assert.Equal(t, StatusError, childRecord.Status)
+	assert.Equal(t, "host is down", childRecord.StatusMessage)
+	assert.Equal(t, "00-"+childRecord.TraceID+"-"+childRecord.SpanID+"-01", header.Get(TraceParentHeader))
+}
+
+func TestNoSpanInContext(t *testing.T) {
+	ctx := context.Background()
+	childCtx, span := StartChild(ctx, "NMAHealthOp")
+	assert.Equal(t, ctx, childCtx)
+	assert.False(t, span.SpanContext().IsValid())
+	span.End(nil)
+
+	// a no-op span is not sent to the hosts
+	header := http.Header{}
+	ctx, _ = Start(ctx, NoopTracer{}, "create_db")
+	Inject(ctx, header)
+	assert.Empty(t, header)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestJSONTracerWriteError(t *testing.T) {
+	tracer := NewJSONTracer(failingWriter{})
+	_, span := Start(context.Background(), tracer, "create_db")
+	span.End(nil)
+	assert.ErrorContains(t, tracer.Err(), "no space left on device")
+}
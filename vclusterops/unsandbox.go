@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vertica/vcluster/rfc7807"
@@ -103,8 +104,8 @@ func (e *SubclusterNotSandboxedError) Error() string {
 // for a successful unsandbox_subcluster
 // - Get cluster and nodes info (check if the DB is Eon)
 // - Get the subcluster info (check if the target subcluster is sandboxed)
-func (vcc *VClusterCommands) unsandboxPreCheck(vdb *VCoordinationDatabase, options *VUnsandboxOptions) error {
-	err := vcc.getVDBFromRunningDB(vdb, &options.DatabaseOptions)
+func (vcc *VClusterCommands) unsandboxPreCheck(ctx context.Context, vdb *VCoordinationDatabase, options *VUnsandboxOptions) error {
+	err := vcc.getVDBFromRunningDB(ctx, vdb, &options.DatabaseOptions)
 	if err != nil {
 		return err
 	}
@@ -258,15 +259,22 @@ func (vcc *VClusterCommands) produceUnsandboxSCInstructions(options *VUnsandboxO
 	return instructions, nil
 }
 
-func (vcc VClusterCommands) VUnsandbox(options *VUnsandboxOptions) error {
+func (vcc VClusterCommands) VUnsandbox(ctx context.Context, options *VUnsandboxOptions) (*ExecutionReport, error) {
+	ctx, call := vcc.startCall(ctx, commandUnsandboxSC, &options.DatabaseOptions)
+	err := vcc.unsandbox(ctx, options)
+	report := call.finish(err)
+	return report, err
+}
+
+func (vcc VClusterCommands) unsandbox(ctx context.Context, options *VUnsandboxOptions) error {
 	vcc.Log.V(0).Info("VUnsandbox method called", "options", options)
-	return runSandboxCmd(vcc, options)
+	return runSandboxCmd(ctx, vcc, options)
 }
 
 // runCommand will produce instructions and run them
-func (options *VUnsandboxOptions) runCommand(vcc VClusterCommands) error {
+func (options *VUnsandboxOptions) runCommand(ctx context.Context, vcc VClusterCommands) error {
 	vdb := makeVCoordinationDatabase()
-	err := vcc.unsandboxPreCheck(&vdb, options)
+	err := vcc.unsandboxPreCheck(ctx, &vdb, options)
 	if err != nil {
 		return err
 	}
@@ -277,11 +285,10 @@ func (options *VUnsandboxOptions) runCommand(vcc VClusterCommands) error {
 	}
 
 	// add certs and instructions to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// run the engine
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(ctx, vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to unsandbox subcluster %s, %w", options.SCName, runError)
 	}
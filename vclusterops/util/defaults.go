@@ -20,6 +20,7 @@ const (
 	DefaultClientPort                = 5433
 	DefaultHTTPPortOffset            = 3010
 	DefaultHTTPPort                  = DefaultClientPort + DefaultHTTPPortOffset
+	DefaultNMAPort                   = 5554
 	DefaultControlAddressFamily      = "ipv4"
 	IPv6ControlAddressFamily         = "ipv6"
 	DefaultRestartPolicy             = "ksafe"
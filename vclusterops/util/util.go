@@ -490,6 +490,17 @@ func ValidateRequiredAbsPath(path, pathName string) error {
 	return ValidateAbsPath(path, pathName)
 }
 
+// ValidatePort checks whether a port is in the valid range.
+// Zero is allowed, it means the default port is used.
+func ValidatePort(port int, portName string) error {
+	const maxPort = 65535
+	if port < 0 || port > maxPort {
+		return fmt.Errorf("%s %d is out of range, must be between 1 and %d", portName, port, maxPort)
+	}
+
+	return nil
+}
+
 func ParamNotSetErrorMsg(param string) error {
 	return fmt.Errorf("%s is pointed to nil", param)
 }
@@ -562,6 +573,14 @@ func Max[T constraints.Ordered](a, b T) T {
 	return b
 }
 
+// Min is the counterpart of Max, and can be removed with it
+func Min[T constraints.Ordered](a, b T) T {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 // GetPathPrefix returns a path prefix for a (catalog/data/depot) path of a node
 func GetPathPrefix(path string) string {
 	return filepath.Dir(filepath.Dir(path))
@@ -269,6 +269,17 @@ func TestValidateDBName(t *testing.T) {
 	assert.ErrorContains(t, err, "invalid character in "+obj+" name: !")
 }
 
+func TestValidatePort(t *testing.T) {
+	// positive cases
+	assert.NoError(t, ValidatePort(0, "NMA port"))
+	assert.NoError(t, ValidatePort(5554, "NMA port"))
+	assert.NoError(t, ValidatePort(65535, "NMA port"))
+
+	// negative cases
+	assert.ErrorContains(t, ValidatePort(-1, "NMA port"), "NMA port -1 is out of range")
+	assert.ErrorContains(t, ValidatePort(65536, "HTTPS port"), "HTTPS port 65536 is out of range")
+}
+
 func TestSetEonFlagHelpMsg(t *testing.T) {
 	msg := "Path to depot directory"
 	finalMsg := "[Eon only] Path to depot directory"
@@ -0,0 +1,92 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+
+	"github.com/vertica/vcluster/vclusterops/tracing"
+)
+
+// vclusterCall is a VClusterCommands call, from the time it is started until
+// it returns. It holds what the ops of the call share, which the op engines
+// of the call get from its context, so that the options of the caller are
+// not changed and can be given to several calls at once. Once the call is
+// done it completes the execution report, records the metrics of the command
+// and ends the span of the call.
+type vclusterCall struct {
+	command string
+	dbName  string
+	// the ops that the call runs are added to the report
+	report *ExecutionReport
+	// the transport of the requests, nil means the network
+	transport HTTPTransport
+	// the progress events of the call are sent to it
+	progress *progressReporter
+	// the metrics of the call are recorded in it
+	metrics *opMetrics
+	// the journal of the call, if it records one
+	journal *opJournal
+	// the span of the call, if it is traced
+	span tracing.Span
+	// cancels the context of the call once it has finished
+	cancel context.CancelFunc
+}
+
+// callContextKey is the key of the call in its context
+type callContextKey struct{}
+
+// startCall starts a VClusterCommands call of the given command. It starts
+// the execution report and sets the transport that the ops of the call use.
+// The calls that record a journal start or continue it themselves. The
+// returned context holds the call, has the deadline of the command from the
+// timeouts of opt, and holds the span of the call, if vcc has a tracer.
+func (vcc VClusterCommands) startCall(ctx context.Context, command string, opt *DatabaseOptions) (context.Context, *vclusterCall) {
+	call := &vclusterCall{
+		command:   command,
+		dbName:    opt.DBName,
+		report:    startReport(),
+		transport: vcc.Transport,
+		progress:  makeProgressReporter(vcc.OnProgress),
+		metrics:   makeOpMetrics(vcc.Metrics),
+	}
+	ctx = context.WithValue(ctx, callContextKey{}, call)
+	ctx, call.cancel = opt.Timeouts.withCommandDeadline(ctx)
+	if vcc.Tracer != nil {
+		ctx, call.span = tracing.Start(ctx, vcc.Tracer, command)
+		call.span.SetAttribute("vcluster.command", command)
+		call.span.SetAttribute("vcluster.database", opt.DBName)
+	}
+	return ctx, call
+}
+
+// getCall returns the VClusterCommands call that ctx is the context of, or
+// nil if there is none
+func getCall(ctx context.Context) *vclusterCall {
+	call, _ := ctx.Value(callContextKey{}).(*vclusterCall)
+	return call
+}
+
+// finish ends the call with the error it returns, and returns its report
+func (call *vclusterCall) finish(err error) *ExecutionReport {
+	call.report.finish(err)
+	call.metrics.observeCommand(call.command, call.dbName, call.report.Duration, err)
+	if call.span != nil {
+		call.span.End(err)
+	}
+	call.cancel()
+	return call.report
+}
@@ -0,0 +1,61 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/metrics"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestFinishCall(t *testing.T) {
+	vcc := VClusterCommands{Metrics: metrics.NewRegistry()}
+	vcc.Log = vlog.Printer{}
+	options := DatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.Timeouts.Command = time.Minute
+	savedOptions := options
+	ctx, call := vcc.startCall(context.Background(), commandStopDB, &options)
+	assert.Same(t, call, getCall(ctx))
+	// the options of the caller are not changed, so they can be given to
+	// several calls at once
+	assert.Equal(t, savedOptions, options)
+
+	report := call.finish(errors.New("stop failed"))
+	assert.Same(t, call.report, report)
+	assert.Equal(t, FailureResult, report.Status)
+	assert.Equal(t, "stop failed", report.Error)
+	// the context of the call is done once it has finished
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	var text strings.Builder
+	assert.NoError(t, vcc.Metrics.WriteText(&text))
+	assert.Contains(t, text.String(),
+		`vcluster_command_duration_seconds_count{command="stop_db",database="test_db",status="FAILURE"} 1`)
+}
+
+// startTestCall starts a call that the op engines of a test run in
+func startTestCall(ctx context.Context, command string, options *DatabaseOptions) (context.Context, *vclusterCall) {
+	vcc := VClusterCommands{}
+	vcc.Log = vlog.Printer{}
+	return vcc.startCall(ctx, command, options)
+}
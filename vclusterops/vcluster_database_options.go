@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -38,6 +39,10 @@ type DatabaseOptions struct {
 	IPv6 bool
 	// path of catalog directory
 	CatalogPrefix string
+	// catalog paths of specific hosts, which override CatalogPrefix when the
+	// catalogs of the existing nodes are looked for. The keys are host
+	// addresses, as in Hosts.
+	HostCatalogPrefixes map[string]string
 	// path of data directory
 	DataPrefix string
 	// File path to YAML config file
@@ -47,6 +52,10 @@ type DatabaseOptions struct {
 
 	// path of depot directory
 	DepotPrefix string
+	// depot paths of specific hosts, which override DepotPrefix for the
+	// existing nodes whose depot the database does not report. The keys are
+	// host addresses, as in Hosts.
+	HostDepotPrefixes map[string]string
 	// whether the database is in Eon mode
 	IsEon bool
 	// path of the communal storage
@@ -73,6 +82,50 @@ type DatabaseOptions struct {
 	LogPath string
 	// whether use password
 	usePassword bool
+
+	/* part 5: ports info */
+
+	// port of the node management agent (NMA), 0 means util.DefaultNMAPort
+	NMAPort int
+	// port of the Vertica HTTPS service, 0 means util.DefaultHTTPPort
+	HTTPSPort int
+	// ports of specific hosts, which override NMAPort and HTTPSPort.
+	// The keys are host addresses, as in Hosts.
+	HostPorts map[string]HostPorts
+
+	/* part 6: dry run info */
+
+	// plan the operations that change the database instead of running them.
+	// The command then returns a *DryRunPlan error.
+	DryRun bool
+
+	/* part 7: request info */
+
+	// the maximum number of requests that an op sends to the hosts at once,
+	// 0 means no limit. It is also the number of scrutinize tarballs that
+	// are downloaded at once, which is otherwise limited to a few.
+	MaxConcurrentRequests int
+	// how long the command, its requests to the hosts and its polling of
+	// the node states can take
+	Timeouts TimeoutPolicy
+
+	/* part 8: journal info */
+
+	// path of the file where the completed operations are recorded, so that
+	// the command can be resumed if it does not finish. Only the commands
+	// whose operations can be resumed, like db_add_subcluster and
+	// revive_db, use it.
+	JournalPath string
+	// resume the command from the journal in JournalPath: the operations
+	// that it has completed and that changed the cluster are skipped
+	ResumeFromJournal bool
+}
+
+// HostPorts holds the ports of the services running on a host.
+// A zero port means the database-wide port is used.
+type HostPorts struct {
+	NMAPort   int
+	HTTPSPort int
 }
 
 const (
@@ -99,8 +152,13 @@ const (
 	commandShowRestorePoints = "show_restore_points"
 	commandInstallPackages   = "install_packages"
 	commandConfigRecover     = "manage_config_recover"
+	commandReviveDB          = "revive_db"
 	commandReplicationStart  = "replication_start"
 	commandFetchNodesDetails = "fetch_nodes_details"
+	commandFetchNodeState    = "fetch_node_state"
+	commandReIP              = "re_ip"
+	commandStartNode         = "restart_node"
+	commandStopNode          = "stop_node"
 )
 
 func DatabaseOptionsFactory() DatabaseOptions {
@@ -133,11 +191,40 @@ func (opt *DatabaseOptions) validateBaseOptions(commandName string, log vlog.Pri
 		return err
 	}
 
+	// ports
+	err = opt.validatePorts()
+	if err != nil {
+		return err
+	}
+
+	// request concurrency
+	if opt.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("the maximum number of concurrent requests cannot be negative")
+	}
+
+	// the polling timeout of the environment
+	_, err = getEnvPollingTimeout()
+	if err != nil {
+		return err
+	}
+
 	// paths
 	err = opt.validatePaths(commandName)
 	if err != nil {
 		return err
 	}
+	for host, catalogPrefix := range opt.HostCatalogPrefixes {
+		err = util.ValidateRequiredAbsPath(catalogPrefix, fmt.Sprintf("catalog path of host %s", host))
+		if err != nil {
+			return err
+		}
+	}
+	for host, depotPrefix := range opt.HostDepotPrefixes {
+		err = util.ValidateRequiredAbsPath(depotPrefix, fmt.Sprintf("depot path of host %s", host))
+		if err != nil {
+			return err
+		}
+	}
 
 	// config directory
 	// VER-91801: remove this condition once re_ip supports the config file
@@ -177,6 +264,29 @@ func (opt *DatabaseOptions) validateHostsAndPwd(commandName string, log vlog.Pri
 	return nil
 }
 
+// validatePorts checks that all the given ports are in the valid range
+func (opt *DatabaseOptions) validatePorts() error {
+	err := util.ValidatePort(opt.NMAPort, "NMA port")
+	if err != nil {
+		return err
+	}
+	err = util.ValidatePort(opt.HTTPSPort, "HTTPS port")
+	if err != nil {
+		return err
+	}
+	for host, ports := range opt.HostPorts {
+		err = util.ValidatePort(ports.NMAPort, fmt.Sprintf("NMA port of host %s", host))
+		if err != nil {
+			return err
+		}
+		err = util.ValidatePort(ports.HTTPSPort, fmt.Sprintf("HTTPS port of host %s", host))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // validate catalog, data, and depot paths
 func (opt *DatabaseOptions) validatePaths(commandName string) error {
 	// validate for the following commands only
@@ -272,10 +382,49 @@ func (opt *DatabaseOptions) normalizePaths() {
 	opt.CatalogPrefix = util.GetCleanPath(opt.CatalogPrefix)
 	opt.DataPrefix = util.GetCleanPath(opt.DataPrefix)
 	opt.DepotPrefix = util.GetCleanPath(opt.DepotPrefix)
+	for host, catalogPrefix := range opt.HostCatalogPrefixes {
+		opt.HostCatalogPrefixes[host] = util.GetCleanPath(catalogPrefix)
+	}
+	for host, depotPrefix := range opt.HostDepotPrefixes {
+		opt.HostDepotPrefixes[host] = util.GetCleanPath(depotPrefix)
+	}
+}
+
+// getCatalogPrefixes returns the catalog prefix of each host: the one in
+// HostCatalogPrefixes, or else CatalogPrefix
+func (opt *DatabaseOptions) getCatalogPrefixes(hosts []string) map[string]string {
+	catalogPrefixes := make(map[string]string, len(hosts))
+	for _, host := range hosts {
+		catalogPrefix, ok := opt.HostCatalogPrefixes[host]
+		if !ok {
+			catalogPrefix = opt.CatalogPrefix
+		}
+		catalogPrefixes[host] = catalogPrefix
+	}
+	return catalogPrefixes
+}
+
+// completeDepotPaths sets the depot path of the nodes that the database has
+// not reported one for, from the depot prefix of their host: the one in
+// HostDepotPrefixes, or else DepotPrefix. The depot paths are used by
+// nmaDeleteDirectoriesOp.
+func (opt *DatabaseOptions) completeDepotPaths(vdb *VCoordinationDatabase) {
+	for host, vnode := range vdb.HostNodeMap {
+		if vnode.DepotPath != "" {
+			continue
+		}
+		depotPrefix, ok := opt.HostDepotPrefixes[host]
+		if !ok {
+			depotPrefix = opt.DepotPrefix
+		}
+		if depotPrefix != "" {
+			vnode.DepotPath = makeDepotPath(depotPrefix, vdb.Name, vnode.Name)
+		}
+	}
 }
 
 // getVDBWhenDBIsDown can retrieve db configurations from NMA /nodes endpoint and cluster_config.json when db is down
-func (opt *DatabaseOptions) getVDBWhenDBIsDown(vcc VClusterCommands) (vdb VCoordinationDatabase, err error) {
+func (opt *DatabaseOptions) getVDBWhenDBIsDown(ctx context.Context, vcc VClusterCommands) (vdb VCoordinationDatabase, err error) {
 	/*
 	 *   1. Get node names for input hosts from NMA /nodes.
 	 *   2. Get other node information for input hosts from cluster_config.json.
@@ -293,16 +442,15 @@ func (opt *DatabaseOptions) getVDBWhenDBIsDown(vcc VClusterCommands) (vdb VCoord
 	vdb1 := VCoordinationDatabase{}
 	var instructions1 []clusterOp
 	nmaHealthOp := makeNMAHealthOp(opt.Hosts)
-	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(opt.Hosts, opt.DBName, opt.CatalogPrefix,
+	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(opt.Hosts, opt.DBName, opt.getCatalogPrefixes(opt.Hosts),
 		false /* report all errors */, &vdb1)
 	instructions1 = append(instructions1,
 		&nmaHealthOp,
 		&nmaGetNodesInfoOp,
 	)
 
-	certs := httpsCerts{key: opt.Key, cert: opt.Cert, caCert: opt.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions1, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := opt.makeClusterOpEngine(instructions1)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		vcc.Log.PrintError("fail to retrieve node names from NMA /nodes: %v", err)
 		return vdb, err
@@ -319,8 +467,8 @@ func (opt *DatabaseOptions) getVDBWhenDBIsDown(vcc VClusterCommands) (vdb VCoord
 	}
 	instructions2 = append(instructions2, &nmaDownLoadFileOp)
 
-	clusterOpEngine = makeClusterOpEngine(instructions2, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine = opt.makeClusterOpEngine(instructions2)
+	err = clusterOpEngine.run(ctx, vcc.Log)
 	if err != nil {
 		vcc.Log.PrintError("fail to retrieve node details from %s: %v", descriptionFileName, err)
 		return vdb, err
@@ -393,11 +541,32 @@ func (opt *DatabaseOptions) isSpreadEncryptionEnabled() (enabled bool, encryptio
 	return false, ""
 }
 
-func (opt *DatabaseOptions) runClusterOpEngine(log vlog.Printer, instructions []clusterOp) error {
-	// Create a VClusterOpEngine, and add certs to the engine
+// makeClusterOpEngine creates a VClusterOpEngine that runs the given
+// instructions with the certs and ports in the options. The report, the
+// transport and the journal are the ones of the call that runs it.
+func (opt *DatabaseOptions) makeClusterOpEngine(instructions []clusterOp) VClusterOpEngine {
 	certs := httpsCerts{key: opt.Key, cert: opt.Cert, caCert: opt.CaCert}
 	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine.ports = opt.getPortConfig()
+	clusterOpEngine.dryRun = opt.DryRun
+	clusterOpEngine.maxConcurrency = opt.MaxConcurrentRequests
+	clusterOpEngine.timeouts = opt.Timeouts
+	return clusterOpEngine
+}
+
+// getPortConfig returns the ports the HTTP adapters should connect to
+func (opt *DatabaseOptions) getPortConfig() portConfig {
+	return portConfig{
+		nmaPort:   opt.NMAPort,
+		httpsPort: opt.HTTPSPort,
+		hostPorts: opt.HostPorts,
+	}
+}
+
+func (opt *DatabaseOptions) runClusterOpEngine(ctx context.Context, log vlog.Printer, instructions []clusterOp) error {
+	// Create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := opt.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	return clusterOpEngine.run(log)
+	return clusterOpEngine.run(ctx, log)
 }
@@ -65,3 +65,34 @@ func TestGetDescriptionFilePath(t *testing.T) {
 	path = opt.getCurrConfigFilePath()
 	assert.Equal(t, targetGCPPath, path)
 }
+
+func TestGetCatalogPrefixes(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.CatalogPrefix = "/data"
+	opt.HostCatalogPrefixes = map[string]string{"192.168.1.102": "/catalog//"}
+	opt.normalizePaths()
+
+	// a host without its own catalog path uses the catalog prefix
+	catalogPrefixes := opt.getCatalogPrefixes([]string{"192.168.1.101", "192.168.1.102"})
+	assert.Equal(t, map[string]string{"192.168.1.101": "/data", "192.168.1.102": "/catalog"}, catalogPrefixes)
+}
+
+func TestCompleteDepotPaths(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.DepotPrefix = "/depot"
+	opt.HostDepotPrefixes = map[string]string{"192.168.1.102": "/ssd"}
+	vdb := makeVCoordinationDatabase()
+	vdb.Name = "test_db"
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_test_db_node0001", DepotPath: "/reported/test_db/v_test_db_node0001_depot"},
+		"192.168.1.102": {Name: "v_test_db_node0002"},
+		"192.168.1.103": {Name: "v_test_db_node0003"},
+	}
+	opt.completeDepotPaths(&vdb)
+
+	// the depot path the database reports is kept, and the others are made
+	// from the depot prefix of their host
+	assert.Equal(t, "/reported/test_db/v_test_db_node0001_depot", vdb.HostNodeMap["192.168.1.101"].DepotPath)
+	assert.Equal(t, "/ssd/test_db/v_test_db_node0002_depot", vdb.HostNodeMap["192.168.1.102"].DepotPath)
+	assert.Equal(t, "/depot/test_db/v_test_db_node0003_depot", vdb.HostNodeMap["192.168.1.103"].DepotPath)
+}
@@ -18,11 +18,13 @@ package vlog
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 const (
@@ -71,6 +73,17 @@ func (p *Printer) Info(msg string, keysAndValues ...any) {
 	p.Log.Info(msg, keysAndValues...)
 }
 
+// Debug displays a message to the log only if the log level is debug.
+func (p *Printer) Debug(msg string, keysAndValues ...any) {
+	p.Log.V(1).Info(msg, keysAndValues...)
+}
+
+// IsDebugEnabled returns true if the log level is debug, so that the values
+// logged by Debug are only computed when they are logged
+func (p *Printer) IsDebugEnabled() bool {
+	return p.Log.V(1).Enabled()
+}
+
 // APIs to control printing to both the log and standard out.
 
 // PrintInfo will display the given message in the log. And if not logging to
@@ -181,40 +194,148 @@ func logMaskedArgParseHelper(inputArgv []string) (maskedPairs []string) {
 	return maskedPairs
 }
 
-// setupOrDie will setup the logging for vcluster CLI. On exit, p.Log will
+// the formats of the log entries
+const (
+	ConsoleFormat = "console"
+	JSONFormat    = "json"
+)
+
+// the log levels, from the most to the least verbose. The debug level also
+// logs the bodies of the requests and responses, with the secrets masked.
+// There is no warning level, as logr logs the warnings at info level.
+const (
+	DebugLevel = "debug"
+	InfoLevel  = "info"
+	ErrorLevel = "error"
+)
+
+// the default sampling of the log entries
+const (
+	DefaultSamplingInitial    = 100
+	DefaultSamplingThereafter = 100
+)
+
+const bytesPerMB = 1024 * 1024
+
+// LogOptions are the options of the logger that SetupWithOptionsOrDie and
+// Setup build. The zero value logs in the console format at info level to
+// stderr.
+type LogOptions struct {
+	// File is the path of the log file. The log goes to stderr if it is empty.
+	File string
+	// Format is ConsoleFormat, the default, or JSONFormat
+	Format string
+	// Level is DebugLevel, InfoLevel, the default, or ErrorLevel
+	Level string
+	// MaxSizeMB is the size that the log file is rotated at, in MB. The file
+	// is not rotated if it is 0.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated log files that are kept
+	MaxBackups int
+	// In each second, after the first SamplingInitial entries with the same
+	// level and message, only every SamplingThereafter-th one is logged.
+	// Both are 100 by default. NoSampling logs all the entries.
+	SamplingInitial    int
+	SamplingThereafter int
+	NoSampling         bool
+}
+
+// SetupOrDie will setup the logging for vcluster CLI. On exit, p.Log will
 // be set.
 func (p *Printer) SetupOrDie(logFile string) {
+	p.SetupWithOptionsOrDie(LogOptions{File: logFile})
+}
+
+// SetupWithOptionsOrDie is SetupOrDie with all the options of the logger
+func (p *Printer) SetupWithOptionsOrDie(options LogOptions) {
+	if err := p.Setup(options); err != nil {
+		fmt.Printf("Failed to setup the logger: %s", err.Error())
+		os.Exit(1)
+	}
+}
+
+// Setup sets p.Log to a logger built from the options
+func (p *Printer) Setup(options LogOptions) error {
 	// The vcluster library uses logr as the logging API. We use Uber's zap
 	// package to implement the logging API.
-	EncoderConfigWithoutCaller := zap.NewDevelopmentEncoderConfig()
-	EncoderConfigWithoutCaller.EncodeCaller = nil // Set EncodeCaller to nil to exclude caller information
+	cfg, err := options.makeZapConfig()
+	if err != nil {
+		return err
+	}
+	zapLg, err := cfg.Build()
+	if err != nil {
+		return err
+	}
+	// If no log file is given, we just log to standard output
+	p.LogToFileOnly = options.File != ""
+	p.Log = zapr.NewLogger(zapLg)
+	p.Log.Info("Successfully started logger", "logFile", options.File, "format", cfg.Encoding, "level", cfg.Level.String())
+	return nil
+}
+
+func (options *LogOptions) makeZapConfig() (*zap.Config, error) {
+	level, ok := map[string]zapcore.Level{
+		"":         zapcore.InfoLevel,
+		DebugLevel: zapcore.DebugLevel,
+		InfoLevel:  zapcore.InfoLevel,
+		ErrorLevel: zapcore.ErrorLevel,
+	}[options.Level]
+	if !ok {
+		return nil, fmt.Errorf("invalid log level %q, it must be %s, %s or %s", options.Level, DebugLevel, InfoLevel, ErrorLevel)
+	}
 	cfg := zap.Config{
-		Level:       zap.NewAtomicLevelAt(zap.InfoLevel),
-		Development: false,
-		// Sampling is enabled at 100:100, meaning that after the first 100 log
-		// entries with the same level and message in the same second, it will
-		// log every 100th entry with the same level and message in the same second.
-		Sampling: &zap.SamplingConfig{
-			Initial:    100,
-			Thereafter: 100,
-		},
-		Encoding:         "console",
-		EncoderConfig:    EncoderConfigWithoutCaller,
+		Level:            zap.NewAtomicLevelAt(level),
+		Development:      false,
 		OutputPaths:      []string{"stderr"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
-	// If no log file is given, we just log to standard output
-	if logFile != "" {
-		p.LogToFileOnly = true
-		cfg.OutputPaths = []string{logFile}
+
+	switch options.Format {
+	case "", ConsoleFormat:
+		cfg.Encoding = ConsoleFormat
+		cfg.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	case JSONFormat:
+		cfg.Encoding = JSONFormat
+		cfg.EncoderConfig = zap.NewProductionEncoderConfig()
+		cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	default:
+		return nil, fmt.Errorf("invalid log format %q, it must be %s or %s", options.Format, ConsoleFormat, JSONFormat)
 	}
-	zapLg, err := cfg.Build()
-	if err != nil {
-		fmt.Printf("Failed to setup the logger: %s", err.Error())
-		os.Exit(1)
+	cfg.EncoderConfig.EncodeCaller = nil // Set EncodeCaller to nil to exclude caller information
+	cfg.DisableCaller = true
+
+	// Sampling is enabled at 100:100 by default, meaning that after the first
+	// 100 log entries with the same level and message in the same second, it
+	// will log every 100th entry with the same level and message in the same
+	// second.
+	if !options.NoSampling {
+		cfg.Sampling = &zap.SamplingConfig{
+			Initial:    DefaultSamplingInitial,
+			Thereafter: DefaultSamplingThereafter,
+		}
+		if options.SamplingInitial > 0 {
+			cfg.Sampling.Initial = options.SamplingInitial
+		}
+		if options.SamplingThereafter > 0 {
+			cfg.Sampling.Thereafter = options.SamplingThereafter
+		}
 	}
-	p.Log = zapr.NewLogger(zapLg)
-	p.Log.Info("Successfully started logger", "logFile", logFile)
+
+	if options.File != "" {
+		cfg.OutputPaths = []string{options.File}
+		if options.MaxSizeMB > 0 {
+			path, err := filepath.Abs(options.File)
+			if err != nil {
+				return nil, err
+			}
+			sinkURL, err := rotatingFileURL(path, int64(options.MaxSizeMB)*bytesPerMB, options.MaxBackups)
+			if err != nil {
+				return nil, err
+			}
+			cfg.OutputPaths = []string{sinkURL}
+		}
+	}
+	return &cfg, nil
 }
 
 func isVerboseOutputEnabled() bool {
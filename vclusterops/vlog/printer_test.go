@@ -16,8 +16,11 @@
 package vlog
 
 import (
+	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -52,3 +55,77 @@ func TestPasswordRedaction(t *testing.T) {
 	assert.Len(t, unmaskedArgs, 2)
 	assert.Equal(t, pw, unmaskedArgs[1])
 }
+
+func TestJSONLogWithLevel(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "vcluster.log")
+	p := Printer{}
+	err := p.Setup(LogOptions{File: logFile, Format: JSONFormat})
+	assert.NoError(t, err)
+	assert.True(t, p.LogToFileOnly)
+
+	p.Debug("not logged at info level")
+	p.Info("logged at info level", "host", "192.168.1.101")
+	p.Error(os.ErrNotExist, "logged with its error")
+
+	content, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	// the first line is the one of the logger setup
+	if assert.Len(t, lines, 3) {
+		entry := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+		assert.Equal(t, "info", entry["level"])
+		assert.Equal(t, "logged at info level", entry["msg"])
+		assert.Equal(t, "192.168.1.101", entry["host"])
+		assert.NoError(t, json.Unmarshal([]byte(lines[2]), &entry))
+		assert.Equal(t, "error", entry["level"])
+		assert.Equal(t, os.ErrNotExist.Error(), entry["error"])
+	}
+	assert.False(t, p.IsDebugEnabled())
+
+	assert.ErrorContains(t, p.Setup(LogOptions{Level: "trace"}), "invalid log level")
+	assert.ErrorContains(t, p.Setup(LogOptions{Format: "xml"}), "invalid log format")
+}
+
+func TestRotatingFile(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "vcluster.log")
+	f, err := openRotatingFile(logFile, 10, 2)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	for _, entry := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = f.Write([]byte(entry))
+		assert.NoError(t, err)
+	}
+	// each entry is rotated to a backup, and only two backups are kept
+	for path, content := range map[string]string{
+		logFile:        "fourth\n",
+		logFile + ".1": "third\n",
+		logFile + ".2": "second\n",
+	} {
+		actual, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(actual))
+	}
+	_, err = os.Stat(logFile + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingLog(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "vcluster.log")
+	p := Printer{}
+	err := p.Setup(LogOptions{File: logFile, Level: DebugLevel, MaxSizeMB: 1, MaxBackups: 1, NoSampling: true})
+	assert.NoError(t, err)
+	assert.True(t, p.IsDebugEnabled())
+
+	message := strings.Repeat("x", 1024)
+	for i := 0; i < 2048; i++ {
+		p.Debug(message)
+	}
+	for _, path := range []string{logFile, logFile + ".1"} {
+		info, err := os.Stat(path)
+		if assert.NoError(t, err) {
+			assert.LessOrEqual(t, info.Size(), int64(bytesPerMB))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlog
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// rotatingFileScheme is the scheme of the zap sink that writes to a rotating
+// log file, with the max size and the number of backups as query params
+const rotatingFileScheme = "vlog-rotating-file"
+
+const logFilePerm = 0644
+
+var registerSinkOnce sync.Once
+var registerSinkErr error
+
+// rotatingFileURL returns the URL that the logger opens the rotating file
+// with, and registers the sink of the rotating files with zap if needed
+func rotatingFileURL(path string, maxSize int64, maxBackups int) (string, error) {
+	registerSinkOnce.Do(func() {
+		registerSinkErr = zap.RegisterSink(rotatingFileScheme, openRotatingFileSink)
+	})
+	if registerSinkErr != nil {
+		return "", fmt.Errorf("fail to register the sink of the rotating log files: %w", registerSinkErr)
+	}
+	query := url.Values{}
+	query.Set("maxSize", strconv.FormatInt(maxSize, 10))
+	query.Set("maxBackups", strconv.Itoa(maxBackups))
+	sinkURL := url.URL{Scheme: rotatingFileScheme, Path: path, RawQuery: query.Encode()}
+	return sinkURL.String(), nil
+}
+
+func openRotatingFileSink(sinkURL *url.URL) (zap.Sink, error) {
+	maxSize, err := strconv.ParseInt(sinkURL.Query().Get("maxSize"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max size of the log file: %w", err)
+	}
+	maxBackups, err := strconv.Atoi(sinkURL.Query().Get("maxBackups"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid number of backups of the log file: %w", err)
+	}
+	return openRotatingFile(sinkURL.Path, maxSize, maxBackups)
+}
+
+// rotatingFile is a log file that is rotated once it would grow past its
+// max size: it is renamed to <path>.1, the older backups are renamed from
+// <path>.N to <path>.N+1, the backups past maxBackups are removed, and a new
+// file is started.
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// an entry larger than the max size is still written, in a file of its own
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	if f.maxBackups <= 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return f.open()
+	}
+	if err := os.Remove(f.backupPath(f.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(f.backupPath(i), f.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(f.path, f.backupPath(1)); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+func (f *rotatingFile) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", f.path, index)
+}
+
+func (f *rotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Sync()
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}